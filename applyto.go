@@ -0,0 +1,152 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ApplyTo copies every Option field of the struct patch that is provided onto
+// the field of the same JSON name of the struct target points to, leaving the
+// other fields of target unchanged. patch may also be a pointer to a struct.
+// The fields of target may be plain values, pointers, which are allocated as
+// needed, or Options, which are provided. Values are converted as they are by
+// Scan, so an Option[int64] may be applied to an int32 field, or an
+// Option[string] to a time.Time field.
+// If a provided value is a struct holding Options and the field of target is a
+// struct too, the value is applied to the field recursively rather than
+// replacing it, and so are plain struct fields of patch holding Options.
+// Fields of patch without a matching field in target are ignored.
+// If a value cannot be converted, ApplyTo returns an error naming the field,
+// and the fields applied before it are left in place.
+func ApplyTo(patch any, target any) (err error) {
+	pv := indirectValue(reflect.ValueOf(patch))
+	if !pv.IsValid() || pv.Kind() != reflect.Struct || isOptionType(pv.Type()) {
+		return fmt.Errorf("opt: ApplyTo requires a struct patch, got %T", patch)
+	}
+
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Pointer || tv.IsNil() || tv.Elem().Kind() != reflect.Struct || isOptionType(tv.Elem().Type()) {
+		return fmt.Errorf("opt: ApplyTo requires a non-nil pointer to a struct target, got %T", target)
+	}
+
+	if err = applyStruct(tv.Elem(), pv, ""); err != nil {
+		return fmt.Errorf("opt: ApplyTo %w", err)
+	}
+
+	return nil
+}
+
+// applyStruct applies the struct patch to the struct dst, found at path.
+func applyStruct(dst, patch reflect.Value, path string) (err error) {
+	targets := map[string]field{}
+	for _, f := range cachedFields(dst.Type()) {
+		targets[f.name] = f
+	}
+
+	for _, f := range cachedFields(patch.Type()) {
+		tf, ok := targets[f.name]
+		if !ok {
+			continue
+		}
+
+		fv, err := patch.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+
+		var src reflect.Value
+		switch {
+		case f.option:
+			value, exists := fv.Interface().(option).AnyValue()
+			if !exists {
+				continue
+			}
+
+			src = reflect.ValueOf(value)
+		case isPatchStruct(f.typ):
+			src = fv
+		default:
+			continue
+		}
+
+		fieldPath := joinPath(path, f.name)
+		if err = applyValue(allocFieldByIndex(dst, tf.index), src, fieldPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyValue applies src to dst, found at path. Errors are prefixed with path.
+func applyValue(dst, src reflect.Value, path string) (err error) {
+	dt := dst.Type()
+
+	switch {
+	case src.IsValid() && !isPatchStruct(src.Type()) && src.Type().AssignableTo(dt):
+		dst.Set(src)
+		return nil
+	case isOptionType(dt):
+		return applyValue(dst.Addr().Interface().(optionSetter).provide(), src, path)
+	case !src.IsValid():
+		dst.SetZero()
+		return nil
+	case isPatchStruct(src.Type()):
+		if dt.Kind() == reflect.Pointer && dt.Elem().Kind() == reflect.Struct {
+			if dst.IsNil() {
+				dst.Set(reflect.New(dt.Elem()))
+			}
+
+			dst = dst.Elem()
+		}
+
+		if dst.Kind() == reflect.Struct && !isLeafType(dst.Type()) {
+			return applyStruct(dst, src, path)
+		}
+	case src.Kind() == reflect.Pointer && !src.Type().AssignableTo(dt):
+		if src.IsNil() {
+			dst.SetZero()
+			return nil
+		}
+
+		return applyValue(dst, src.Elem(), path)
+	case dt.Kind() == reflect.Pointer && !src.Type().AssignableTo(dt):
+		elem := reflect.New(dt.Elem())
+		if err = applyValue(elem.Elem(), src, path); err != nil {
+			return err
+		}
+
+		dst.Set(elem)
+		return nil
+	}
+
+	if err = assignValue(dst, src); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+// isPatchStruct reports whether values of t are applied field by field: t is a
+// struct holding Options that does not encode itself.
+func isPatchStruct(t reflect.Type) (ok bool) {
+	return t.Kind() == reflect.Struct && !isOptionType(t) && !isLeafType(t) && containsOption(t)
+}
+
+// allocFieldByIndex returns the field of the struct v with the given index,
+// allocating the embedded pointers on the way to it.
+func allocFieldByIndex(v reflect.Value, index []int) (f reflect.Value) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v
+}
@@ -0,0 +1,112 @@
+package opt_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testApplyAddress struct {
+	City     string `json:"city"`
+	Postcode string `json:"postcode"`
+}
+
+type testApplyMeta struct {
+	Revision int `json:"revision"`
+}
+
+type testApplyTarget struct {
+	testApplyMeta
+	Name      string             `json:"name"`
+	Age       int32              `json:"age"`
+	Nickname  *string            `json:"nickname"`
+	Manager   *string            `json:"manager"`
+	Email     opt.Option[string] `json:"email"`
+	Joined    time.Time          `json:"joined"`
+	Address   testApplyAddress   `json:"address"`
+	Billing   *testApplyAddress  `json:"billing"`
+	Tags      []string           `json:"tags"`
+	Untouched string             `json:"untouched"`
+}
+
+type testApplyAddressPatch struct {
+	City     opt.Option[string] `json:"city"`
+	Postcode opt.Option[string] `json:"postcode"`
+}
+
+type testApplyPatch struct {
+	Name      opt.Option[string]                `json:"name"`
+	Age       opt.Option[int64]                 `json:"age"`
+	Nickname  opt.Option[string]                `json:"nickname"`
+	Manager   opt.Option[*string]               `json:"manager"`
+	Email     opt.Option[string]                `json:"email"`
+	Joined    opt.Option[string]                `json:"joined"`
+	Address   testApplyAddressPatch             `json:"address"`
+	Billing   opt.Option[testApplyAddressPatch] `json:"billing"`
+	Tags      opt.Option[[]string]              `json:"tags"`
+	Revision  opt.Option[int]                   `json:"revision"`
+	Unmatched opt.Option[string]                `json:"unmatched"`
+	Untouched opt.Option[string]                `json:"untouched"`
+}
+
+func newApplyTarget() (target testApplyTarget) {
+	manager := "Charles"
+
+	return testApplyTarget{
+		Name:      "Ada",
+		Age:       36,
+		Manager:   &manager,
+		Address:   testApplyAddress{City: "London", Postcode: "W1"},
+		Tags:      []string{"admin"},
+		Untouched: "value",
+	}
+}
+
+func Test_ApplyTo(t *testing.T) {
+	t.Run("Present fields", func(t *testing.T) {
+		target := newApplyTarget()
+		err := opt.ApplyTo(testApplyPatch{
+			Name:      opt.Some("Ada Lovelace"),
+			Age:       opt.Some(int64(37)),
+			Nickname:  opt.Some("Countess"),
+			Manager:   opt.Some[*string](nil),
+			Email:     opt.Some("ada@example.com"),
+			Joined:    opt.Some("1843-07-01T00:00:00Z"),
+			Address:   testApplyAddressPatch{Postcode: opt.Some("NW1")},
+			Billing:   opt.Some(testApplyAddressPatch{City: opt.Some("Paris")}),
+			Tags:      opt.Some([]string{"owner"}),
+			Revision:  opt.Some(2),
+			Unmatched: opt.Some("ignored"),
+		}, &target)
+
+		snaps.MatchSnapshot(t, fmt.Sprint(err), target.Name, target.Age, *target.Nickname, target.Manager,
+			target.Email, target.Joined, target.Address, *target.Billing, target.Tags, target.Revision, target.Untouched)
+	})
+
+	t.Run("Nothing present", func(t *testing.T) {
+		target := newApplyTarget()
+		err := opt.ApplyTo(&testApplyPatch{}, &target)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), reflect.DeepEqual(target, newApplyTarget()))
+	})
+
+	t.Run("Conversion error", func(t *testing.T) {
+		target := newApplyTarget()
+		err := opt.ApplyTo(struct {
+			Age opt.Option[string] `json:"age"`
+		}{Age: opt.Some("old")}, &target)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), target.Age)
+	})
+
+	t.Run("Invalid patch", func(t *testing.T) {
+		target := newApplyTarget()
+		snaps.MatchSnapshot(t, fmt.Sprint(opt.ApplyTo(opt.Some("Ada"), &target)))
+	})
+
+	t.Run("Invalid target", func(t *testing.T) {
+		snaps.MatchSnapshot(t, fmt.Sprint(opt.ApplyTo(testApplyPatch{}, newApplyTarget())))
+	})
+}
@@ -0,0 +1,83 @@
+package opt
+
+import "sync/atomic"
+
+// Atomic is an Option that can be loaded and stored by several goroutines at
+// the same time, such as the current snapshot of a configuration that is
+// reloaded in the background.
+// The zero Atomic holds an Option whose value is not provided.
+// An Atomic must not be copied after first use.
+type Atomic[T any] struct {
+	// p points to the value, or is nil if the value is not provided.
+	p atomic.Pointer[T]
+}
+
+// NewAtomic returns an Atomic holding o.
+func NewAtomic[T any](o Option[T]) (a *Atomic[T]) {
+	a = &Atomic[T]{}
+	a.Store(o)
+	return a
+}
+
+// Load returns the Option the Atomic holds.
+func (a *Atomic[T]) Load() (o Option[T]) {
+	return optionOfPointer(a.p.Load())
+}
+
+// Store sets the Option the Atomic holds to o.
+func (a *Atomic[T]) Store(o Option[T]) {
+	a.p.Store(pointerOfOption(o))
+}
+
+// Swap sets the Option the Atomic holds to o and returns the previous one.
+func (a *Atomic[T]) Swap(o Option[T]) (old Option[T]) {
+	return optionOfPointer(a.p.Swap(pointerOfOption(o)))
+}
+
+// CompareAndSwap sets the Option the Atomic holds to new if it is equal to
+// old, and reports whether it did.
+// Two Options are equal if neither value is provided, or if both are and the
+// values are equal as compared by ==. Like sync.Map.CompareAndSwap,
+// CompareAndSwap panics if both values are provided and T is not comparable.
+func (a *Atomic[T]) CompareAndSwap(old, new Option[T]) (swapped bool) {
+	for {
+		p := a.p.Load()
+		if !atomicEqual(optionOfPointer(p), old) {
+			return false
+		}
+
+		if a.p.CompareAndSwap(p, pointerOfOption(new)) {
+			return true
+		}
+	}
+}
+
+// atomicEqual reports whether the Options a and b are equal as defined by
+// CompareAndSwap.
+func atomicEqual[T any](a, b Option[T]) (equal bool) {
+	if !a.exists || !b.exists {
+		return a.exists == b.exists
+	}
+
+	return any(a.value) == any(b.value)
+}
+
+// optionOfPointer returns an Option holding *p, or None if p is nil.
+func optionOfPointer[T any](p *T) (o Option[T]) {
+	if p == nil {
+		return None[T]()
+	}
+
+	return Some(*p)
+}
+
+// pointerOfOption returns a pointer to a copy of the value of o, or nil if the
+// value is not provided.
+func pointerOfOption[T any](o Option[T]) (p *T) {
+	if !o.exists {
+		return nil
+	}
+
+	value := o.value
+	return &value
+}
@@ -0,0 +1,75 @@
+package opt_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_Atomic(t *testing.T) {
+	t.Run("Zero", func(t *testing.T) {
+		var a opt.Atomic[string]
+		o := a.Load()
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+
+	t.Run("Store and swap", func(t *testing.T) {
+		a := opt.NewAtomic(opt.Some("v1"))
+		first := a.Load()
+
+		old := a.Swap(opt.None[string]())
+		cleared := a.Load()
+
+		a.Store(opt.Some("v2"))
+		stored := a.Load()
+
+		snaps.MatchSnapshot(t, first.Unwrap(), old.Unwrap(), cleared.Exists(), stored.Unwrap())
+	})
+
+	t.Run("Compare and swap", func(t *testing.T) {
+		a := opt.NewAtomic(opt.None[int]())
+
+		swapped := []bool{
+			a.CompareAndSwap(opt.Some(0), opt.Some(1)),
+			a.CompareAndSwap(opt.None[int](), opt.Some(1)),
+			a.CompareAndSwap(opt.Some(2), opt.Some(3)),
+			a.CompareAndSwap(opt.Some(1), opt.None[int]()),
+		}
+
+		o := a.Load()
+		snaps.MatchSnapshot(t, swapped, o.Exists())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		a := opt.NewAtomic(opt.Some(0))
+
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					o := a.Load()
+					if a.CompareAndSwap(o, opt.Some(o.Unwrap()+1)) {
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		snaps.MatchSnapshot(t, a.Load().Unwrap())
+	})
+
+	t.Run("Not comparable", func(t *testing.T) {
+		a := opt.NewAtomic(opt.Some([]int{1}))
+
+		defer func() {
+			snaps.MatchSnapshot(t, recover() != nil)
+		}()
+
+		a.CompareAndSwap(opt.Some([]int{1}), opt.None[[]int]())
+	})
+}
@@ -0,0 +1,172 @@
+package opt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// FieldError describes a field of a request body that could not be bound.
+type FieldError struct {
+	// Name is the dotted path of JSON names leading to the field.
+	Name string `json:"name"`
+
+	// Reason describes why the value of the field could not be bound.
+	Reason string `json:"reason"`
+}
+
+// BindError is returned by BindJSON when a request body cannot be bound.
+// It encodes as JSON suitable for the body of a 400 Bad Request response:
+//
+//	{"reason":"invalid fields","fields":[{"name":"age","reason":"expected int, got string"}]}
+type BindError struct {
+	// Reason describes why the body could not be bound.
+	Reason string `json:"reason"`
+
+	// Fields lists the fields that could not be bound. It is empty if the
+	// body as a whole could not be decoded.
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *BindError) Error() (msg string) {
+	msg = "opt: BindJSON: " + e.Reason
+	for i, f := range e.Fields {
+		sep := ", "
+		if i == 0 {
+			sep = ": "
+		}
+
+		msg += sep + f.Name + ": " + f.Reason
+	}
+
+	return msg
+}
+
+// BindJSON decodes the JSON body of r into the struct target points to, as
+// Unmarshal does, so that Option fields missing from the body are left
+// unprovided. It is meant for handlers written with net/http or
+// github.com/go-chi/chi:
+//
+//	var patch UserPatch
+//	if err := opt.BindJSON(r, &patch); err != nil {
+//		var bindErr *opt.BindError
+//		if errors.As(err, &bindErr) {
+//			w.WriteHeader(http.StatusBadRequest)
+//			json.NewEncoder(w).Encode(bindErr)
+//			return
+//		}
+//		...
+//	}
+//
+// If the body is empty, is not valid JSON or holds values of the wrong types,
+// BindJSON returns a *BindError listing every top level field that could not
+// be bound, and target may be partly bound.
+// If the body cannot be read, BindJSON returns the error from reading it.
+func BindJSON(r *http.Request, target any) (err error) {
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Pointer || tv.IsNil() || tv.Elem().Kind() != reflect.Struct || isOptionType(tv.Elem().Type()) {
+		return fmt.Errorf("opt: BindJSON requires a non-nil pointer to a struct target, got %T", target)
+	}
+
+	if r.Body == nil {
+		return &BindError{Reason: "request body is empty"}
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return &BindError{Reason: "request body is empty"}
+	}
+
+	if err = Unmarshal(data, target); err == nil {
+		return nil
+	}
+
+	var object map[string]json.RawMessage
+	if objErr := json.Unmarshal(data, &object); objErr != nil || object == nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(objErr, &syntaxErr) {
+			return &BindError{Reason: bindReason(objErr)}
+		}
+
+		return &BindError{Reason: "request body must be a JSON object"}
+	}
+
+	fields := bindFieldErrors("", object, tv.Elem().Type())
+	if len(fields) == 0 {
+		return &BindError{Reason: bindReason(err)}
+	}
+
+	return &BindError{Reason: "invalid fields", Fields: fields}
+}
+
+// bindFieldErrors decodes the members of object into the fields of the struct
+// type t they belong to, returning the errors found, named after prefix.
+// The fields of nested structs are reported individually.
+func bindFieldErrors(prefix string, object map[string]json.RawMessage, t reflect.Type) (fields []FieldError) {
+	for _, f := range cachedFields(t) {
+		key, found := lookupKey(object, f.name)
+		for _, alias := range f.aliases {
+			if found {
+				break
+			}
+
+			key, found = lookupKey(object, alias)
+		}
+
+		if !found {
+			continue
+		}
+
+		err := Unmarshal(object[key], reflect.New(f.typ).Interface())
+		if err == nil {
+			continue
+		}
+
+		name := prefix + f.name
+
+		st := f.typ
+		if f.option {
+			st = optionElemType(st)
+		}
+
+		for st.Kind() == reflect.Pointer {
+			st = st.Elem()
+		}
+
+		var nested map[string]json.RawMessage
+		if st.Kind() == reflect.Struct && !isUnmarshalerType(st) && json.Unmarshal(object[key], &nested) == nil && nested != nil {
+			if nestedFields := bindFieldErrors(name+".", nested, st); len(nestedFields) > 0 {
+				fields = append(fields, nestedFields...)
+				continue
+			}
+		}
+
+		fields = append(fields, FieldError{Name: name, Reason: bindReason(err)})
+	}
+
+	return fields
+}
+
+// bindReason returns the description of err used by BindError.
+func bindReason(err error) (reason string) {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return "expected " + typeErr.Type.String() + ", got " + typeErr.Value
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Sprintf("invalid JSON at offset %d: %s", syntaxErr.Offset, strings.TrimPrefix(syntaxErr.Error(), "json: "))
+	}
+
+	return strings.TrimPrefix(err.Error(), "json: ")
+}
@@ -0,0 +1,64 @@
+package opt_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testBindAddress struct {
+	City opt.Option[string] `json:"city"`
+	Zip  opt.Option[int]    `json:"zip"`
+}
+
+type testBindBody struct {
+	Name    opt.Option[string]          `json:"name"`
+	Age     opt.Option[int]             `json:"age"`
+	Born    opt.Option[time.Time]       `json:"born"`
+	Address opt.Option[testBindAddress] `json:"address"`
+	Email   opt.Option[string]          `json:"email" opt:"aliases=mail"`
+}
+
+func Test_BindJSON(t *testing.T) {
+	testCases := map[string]string{
+		"Partial":       `{"name": "ada"}`,
+		"Values":        `{"name": "ada", "age": 36, "born": "1815-12-10T00:00:00Z", "address": {"city": "London"}, "mail": "ada@example.com"}`,
+		"Empty":         "",
+		"Syntax":        `{"name": "ada",}`,
+		"Not an object": `["ada"]`,
+		"Invalid fields": `{"name": 1, "age": "old", "born": "yesterday",
+			"address": {"zip": "N1"}, "mail": false}`,
+	}
+
+	for n, body := range testCases {
+		t.Run(n, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+			var b testBindBody
+			err := opt.BindJSON(r, &b)
+			snaps.MatchSnapshot(t, b, err)
+		})
+	}
+
+	t.Run("Response body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age": "old"}`))
+
+		var b testBindBody
+		err := opt.BindJSON(r, &b)
+
+		data, jsonErr := json.Marshal(err)
+		snaps.MatchSnapshot(t, string(data), jsonErr, err.Error())
+	})
+
+	t.Run("Not a pointer", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+		err := opt.BindJSON(r, testBindBody{})
+		snaps.MatchSnapshot(t, err)
+	})
+}
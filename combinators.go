@@ -0,0 +1,223 @@
+package opt
+
+// Map returns the result of fn applied to the value, keeping its presence.
+// If the value is provided, Map returns Some of fn applied to the value.
+// If the value is not provided, fn is not called and Map returns None.
+func Map[T, U any](o Option[T], fn func(T) U) (mapped Option[U]) {
+	if !o.exists {
+		return None[U]()
+	}
+
+	return Some(fn(o.value))
+}
+
+// FlatMap returns the result of fn applied to the value, for chaining
+// computations that may themselves not produce a value. It is known as
+// and_then in Rust.
+// If the value is provided, FlatMap returns fn applied to the value.
+// If the value is not provided, fn is not called and FlatMap returns None.
+func FlatMap[T, U any](o Option[T], fn func(T) Option[U]) (mapped Option[U]) {
+	if !o.exists {
+		return None[U]()
+	}
+
+	return fn(o.value)
+}
+
+// Filter returns the Option if its value satisfies pred, so that a value that
+// is provided but invalid can be treated as not provided.
+// If the value is provided and pred returns true, Filter returns the Option.
+// If pred returns false, or the value is not provided, Filter returns None.
+func Filter[T any](o Option[T], pred func(T) bool) (filtered Option[T]) {
+	if !o.exists || !pred(o.value) {
+		return None[T]()
+	}
+
+	return o
+}
+
+// MapOr returns fn applied to the value, or def if the value is not provided.
+// If the value is provided, MapOr returns fn applied to the value.
+// If the value is not provided, fn is not called and MapOr returns def.
+func MapOr[T, U any](o Option[T], def U, fn func(T) U) (value U) {
+	if !o.exists {
+		return def
+	}
+
+	return fn(o.value)
+}
+
+// MapOrElse returns fn applied to the value, or the result of def if the value
+// is not provided. Unlike MapOr, the default is only computed when it is
+// needed.
+// If the value is provided, MapOrElse returns fn applied to the value and def
+// is not called.
+// If the value is not provided, fn is not called and MapOrElse returns the
+// result of def.
+func MapOrElse[T, U any](o Option[T], def func() U, fn func(T) U) (value U) {
+	if !o.exists {
+		return def()
+	}
+
+	return fn(o.value)
+}
+
+// And returns b if a is provided, so that b only applies once a is present.
+// If the value of a is provided, And returns b.
+// If the value of a is not provided, And returns None.
+func And[T, U any](a Option[T], b Option[U]) (result Option[U]) {
+	if !a.exists {
+		return None[U]()
+	}
+
+	return b
+}
+
+// Xor returns whichever of a and b is provided if exactly one of them is, so
+// that mutually exclusive values can be checked in one call.
+// If exactly one of the values is provided, Xor returns that Option.
+// If both or neither of the values are provided, Xor returns None.
+func Xor[T any](a, b Option[T]) (result Option[T]) {
+	switch {
+	case a.exists && !b.exists:
+		return a
+	case b.exists && !a.exists:
+		return b
+	}
+
+	return None[T]()
+}
+
+// Pair holds two values produced together, such as by Zip.
+type Pair[A, B any] struct {
+	// First is the first value, taken from the first Option given to Zip.
+	First A
+
+	// Second is the second value, taken from the second Option given to Zip.
+	Second B
+}
+
+// Zip combines a and b into an Option of both their values, so that several
+// values can be required together.
+// If both values are provided, Zip returns Some of a Pair holding them.
+// If either value is not provided, Zip returns None.
+func Zip[A, B any](a Option[A], b Option[B]) (zipped Option[Pair[A, B]]) {
+	return ZipWith(a, b, func(a A, b B) Pair[A, B] {
+		return Pair[A, B]{First: a, Second: b}
+	})
+}
+
+// ZipWith combines the values of a and b with fn.
+// If both values are provided, ZipWith returns Some of fn applied to them.
+// If either value is not provided, fn is not called and ZipWith returns None.
+func ZipWith[A, B, R any](a Option[A], b Option[B], fn func(A, B) R) (zipped Option[R]) {
+	if !a.exists || !b.exists {
+		return None[R]()
+	}
+
+	return Some(fn(a.value, b.value))
+}
+
+// Unzip splits an Option of a Pair into Options of its values, the inverse of
+// Zip.
+// If the value is provided, Unzip returns Some of each value of the Pair.
+// If the value is not provided, Unzip returns None twice.
+func Unzip[A, B any](o Option[Pair[A, B]]) (a Option[A], b Option[B]) {
+	if !o.exists {
+		return None[A](), None[B]()
+	}
+
+	return Some(o.value.First), Some(o.value.Second)
+}
+
+// Flatten collapses an Option of an Option into a single Option.
+// If both the outer and inner values are provided, Flatten returns the inner
+// Option.
+// If either value is not provided, Flatten returns None.
+func Flatten[T any](o Option[Option[T]]) (flat Option[T]) {
+	if !o.exists {
+		return None[T]()
+	}
+
+	return o.value
+}
+
+// Match returns the result of some applied to the value, or of none if the
+// value is not provided, so that both cases are handled in one expression.
+// If the value is provided, Match returns some applied to the value and none
+// is not called.
+// If the value is not provided, Match returns the result of none and some is
+// not called.
+func Match[T, R any](o Option[T], some func(T) R, none func() R) (result R) {
+	if !o.exists {
+		return none()
+	}
+
+	return some(o.value)
+}
+
+// Coalesce returns the first of opts whose value is provided, so that sources
+// can be listed in order of precedence:
+//
+//	port := opt.Coalesce(flagPort, envPort, filePort)
+//
+// If none of the values are provided, or opts is empty, Coalesce returns
+// None.
+func Coalesce[T any](opts ...Option[T]) (first Option[T]) {
+	for _, o := range opts {
+		if o.exists {
+			return o
+		}
+	}
+
+	return None[T]()
+}
+
+// FirstSome returns the first Option of s whose value is provided.
+// If none of the values are provided, or s is empty, FirstSome returns None.
+func FirstSome[T any](s []Option[T]) (first Option[T]) {
+	return Coalesce(s...)
+}
+
+// LastSome returns the last Option of s whose value is provided.
+// If none of the values are provided, or s is empty, LastSome returns None.
+func LastSome[T any](s []Option[T]) (last Option[T]) {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].exists {
+			return s[i]
+		}
+	}
+
+	return None[T]()
+}
+
+// Collect gathers the values of s into a slice, only if all of them are
+// provided.
+// If every value is provided, Collect returns Some of the values in order; an
+// empty s gives Some of an empty slice.
+// If any value is not provided, Collect returns None.
+func Collect[T any](s []Option[T]) (all Option[[]T]) {
+	values := make([]T, 0, len(s))
+	for _, o := range s {
+		if !o.exists {
+			return None[[]T]()
+		}
+
+		values = append(values, o.value)
+	}
+
+	return Some(values)
+}
+
+// Values returns the values of s that are provided, in order, leaving out the
+// others. If no value is provided, Values returns an empty slice.
+func Values[T any](s []Option[T]) (values []T) {
+	values = make([]T, 0, len(s))
+	for _, o := range s {
+		if o.exists {
+			values = append(values, o.value)
+		}
+	}
+
+	return values
+}
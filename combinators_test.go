@@ -0,0 +1,265 @@
+package opt_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_Map(t *testing.T) {
+	t.Run("Some", func(t *testing.T) {
+		o := opt.Map(opt.Some(42), strconv.Itoa)
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+
+	t.Run("Some zero", func(t *testing.T) {
+		o := opt.Map(opt.Some(""), func(s string) int { return len(s) })
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+
+	t.Run("None", func(t *testing.T) {
+		called := false
+		o := opt.Map(opt.None[int](), func(i int) string {
+			called = true
+			return strconv.Itoa(i)
+		})
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap(), called)
+	})
+}
+
+func Test_FlatMap(t *testing.T) {
+	parse := func(s string) opt.Option[int] {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return opt.None[int]()
+		}
+
+		return opt.Some(i)
+	}
+
+	names := map[int]string{1: "one"}
+	lookup := func(i int) opt.Option[string] {
+		name, ok := names[i]
+		if !ok {
+			return opt.None[string]()
+		}
+
+		return opt.Some(name)
+	}
+
+	for n, o := range map[string]opt.Option[string]{
+		"Found":     opt.Some("1"),
+		"Not found": opt.Some("2"),
+		"Invalid":   opt.Some("one"),
+		"None":      opt.None[string](),
+	} {
+		t.Run(n, func(t *testing.T) {
+			result := opt.FlatMap(opt.FlatMap(o, parse), lookup)
+			snaps.MatchSnapshot(t, result.Exists(), result.Unwrap())
+		})
+	}
+}
+
+func Test_Filter(t *testing.T) {
+	positive := func(i int) bool { return i > 0 }
+
+	for n, o := range map[string]opt.Option[int]{
+		"Passes": opt.Some(10),
+		"Fails":  opt.Some(-1),
+		"None":   opt.None[int](),
+	} {
+		t.Run(n, func(t *testing.T) {
+			result := opt.Filter(o, positive)
+			snaps.MatchSnapshot(t, result.Exists(), result.Unwrap())
+		})
+	}
+}
+
+func Test_MapOr(t *testing.T) {
+	t.Run("Some", func(t *testing.T) {
+		snaps.MatchSnapshot(t, opt.MapOr(opt.Some(42), "none", strconv.Itoa))
+	})
+
+	t.Run("None", func(t *testing.T) {
+		snaps.MatchSnapshot(t, opt.MapOr(opt.None[int](), "none", strconv.Itoa))
+	})
+}
+
+func Test_MapOrElse(t *testing.T) {
+	calls := 0
+	def := func() string {
+		calls++
+		return "none"
+	}
+
+	t.Run("Some", func(t *testing.T) {
+		calls = 0
+		snaps.MatchSnapshot(t, opt.MapOrElse(opt.Some(42), def, strconv.Itoa), calls)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		calls = 0
+		snaps.MatchSnapshot(t, opt.MapOrElse(opt.None[int](), def, strconv.Itoa), calls)
+	})
+}
+
+func Test_And(t *testing.T) {
+	for n, tc := range map[string]struct {
+		gate   opt.Option[bool]
+		config opt.Option[int]
+	}{
+		"Both":        {gate: opt.Some(false), config: opt.Some(10)},
+		"Gate only":   {gate: opt.Some(true), config: opt.None[int]()},
+		"Config only": {gate: opt.None[bool](), config: opt.Some(10)},
+		"Neither":     {gate: opt.None[bool](), config: opt.None[int]()},
+	} {
+		t.Run(n, func(t *testing.T) {
+			result := opt.And(tc.gate, tc.config)
+			snaps.MatchSnapshot(t, result.Exists(), result.Unwrap())
+		})
+	}
+}
+
+func Test_Xor(t *testing.T) {
+	for n, tc := range map[string]struct {
+		a, b opt.Option[string]
+	}{
+		"First":   {a: opt.Some("id"), b: opt.None[string]()},
+		"Second":  {a: opt.None[string](), b: opt.Some("email")},
+		"Both":    {a: opt.Some("id"), b: opt.Some("email")},
+		"Neither": {a: opt.None[string](), b: opt.None[string]()},
+	} {
+		t.Run(n, func(t *testing.T) {
+			result := opt.Xor(tc.a, tc.b)
+			snaps.MatchSnapshot(t, result.Exists(), result.Unwrap())
+		})
+	}
+}
+
+func Test_Zip(t *testing.T) {
+	for n, tc := range map[string]struct {
+		a opt.Option[string]
+		b opt.Option[int]
+	}{
+		"Both":        {a: opt.Some("page"), b: opt.Some(2)},
+		"First only":  {a: opt.Some("page"), b: opt.None[int]()},
+		"Second only": {a: opt.None[string](), b: opt.Some(2)},
+	} {
+		t.Run(n, func(t *testing.T) {
+			result := opt.Zip(tc.a, tc.b)
+			snaps.MatchSnapshot(t, result.Exists(), result.Unwrap())
+		})
+	}
+}
+
+func Test_ZipWith(t *testing.T) {
+	area := func(w, h int) int { return w * h }
+
+	t.Run("Both", func(t *testing.T) {
+		result := opt.ZipWith(opt.Some(3), opt.Some(4), area)
+		snaps.MatchSnapshot(t, result.Exists(), result.Unwrap())
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		result := opt.ZipWith(opt.Some(3), opt.None[int](), area)
+		snaps.MatchSnapshot(t, result.Exists(), result.Unwrap())
+	})
+}
+
+func Test_Unzip(t *testing.T) {
+	t.Run("Some", func(t *testing.T) {
+		a, b := opt.Unzip(opt.Zip(opt.Some("page"), opt.Some(2)))
+		snaps.MatchSnapshot(t, a.Exists(), a.Unwrap(), b.Exists(), b.Unwrap())
+	})
+
+	t.Run("None", func(t *testing.T) {
+		a, b := opt.Unzip(opt.None[opt.Pair[string, int]]())
+		snaps.MatchSnapshot(t, a.Exists(), a.Unwrap(), b.Exists(), b.Unwrap())
+	})
+}
+
+func Test_Flatten(t *testing.T) {
+	for n, o := range map[string]opt.Option[opt.Option[int]]{
+		"Some":       opt.Some(opt.Some(1)),
+		"Inner none": opt.Some(opt.None[int]()),
+		"None":       opt.None[opt.Option[int]](),
+	} {
+		t.Run(n, func(t *testing.T) {
+			result := opt.Flatten(o)
+			snaps.MatchSnapshot(t, result.Exists(), result.Unwrap())
+		})
+	}
+}
+
+func Test_Match(t *testing.T) {
+	greet := func(o opt.Option[string]) string {
+		return opt.Match(o,
+			func(name string) string { return "Hello, " + name },
+			func() string { return "Hello, stranger" },
+		)
+	}
+
+	t.Run("Some", func(t *testing.T) {
+		snaps.MatchSnapshot(t, greet(opt.Some("Ada")))
+	})
+
+	t.Run("None", func(t *testing.T) {
+		snaps.MatchSnapshot(t, greet(opt.None[string]()))
+	})
+}
+
+func Test_Coalesce(t *testing.T) {
+	flag, env, file := opt.None[int](), opt.Some(8080), opt.Some(80)
+
+	for n, opts := range map[string][]opt.Option[int]{
+		"First":  {opt.Some(9090), env, file},
+		"Middle": {flag, env, file},
+		"None":   {flag, flag},
+		"Empty":  nil,
+	} {
+		t.Run(n, func(t *testing.T) {
+			result := opt.Coalesce(opts...)
+			snaps.MatchSnapshot(t, result.Exists(), result.Unwrap())
+		})
+	}
+}
+
+func Test_FirstSome(t *testing.T) {
+	for n, s := range map[string][]opt.Option[string]{
+		"Mixed": {opt.None[string](), opt.Some("first"), opt.None[string](), opt.Some("last"), opt.None[string]()},
+		"None":  {opt.None[string](), opt.None[string]()},
+		"Empty": nil,
+	} {
+		t.Run(n, func(t *testing.T) {
+			first, last := opt.FirstSome(s), opt.LastSome(s)
+			snaps.MatchSnapshot(t, first.Exists(), first.Unwrap(), last.Exists(), last.Unwrap())
+		})
+	}
+}
+
+func Test_Collect(t *testing.T) {
+	for n, s := range map[string][]opt.Option[int]{
+		"All":     {opt.Some(1), opt.Some(0), opt.Some(3)},
+		"Missing": {opt.Some(1), opt.None[int](), opt.Some(3)},
+		"Empty":   nil,
+	} {
+		t.Run(n, func(t *testing.T) {
+			result := opt.Collect(s)
+			snaps.MatchSnapshot(t, result.Exists(), result.Unwrap())
+		})
+	}
+}
+
+func Test_Values(t *testing.T) {
+	for n, s := range map[string][]opt.Option[string]{
+		"Mixed": {opt.Some("a"), opt.None[string](), opt.Some(""), opt.None[string](), opt.Some("c")},
+		"None":  {opt.None[string]()},
+		"Empty": nil,
+	} {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, opt.Values(s))
+		})
+	}
+}
@@ -0,0 +1,841 @@
+package opt
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/fletcharoo/opt/internal/logwalk"
+)
+
+var (
+	binaryMarshalerType   = reflect.TypeFor[encoding.BinaryMarshaler]()
+	binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+)
+
+// CBOR major types used by the compact encoding.
+const (
+	cborUint   byte = 0
+	cborNegInt byte = 1
+	cborBytes  byte = 2
+	cborText   byte = 3
+	cborArray  byte = 4
+	cborMap    byte = 5
+	cborSimple byte = 7
+)
+
+// CBOR initial bytes of the simple values and floats used by the compact
+// encoding.
+const (
+	cborFalse   byte = 0xf4
+	cborTrue    byte = 0xf5
+	cborNull    byte = 0xf6
+	cborFloat32 byte = 0xfa
+	cborFloat64 byte = 0xfb
+)
+
+// errCompactEOF is returned when compact data ends in the middle of a value.
+var errCompactEOF = errors.New("opt: unexpected end of compact data")
+
+// MarshalCompact returns the compact binary encoding of v, which is meant for
+// caches and other places where JSON is too large.
+// Values are written as CBOR (RFC 8949). A struct is written as an array
+// holding a presence bitmap, with one bit per Option field, followed by the
+// values of its fields in declaration order, leaving out Options that were not
+// provided. Field names are not written, so the data can only be read back
+// into the same type with UnmarshalCompact.
+// Types implementing encoding.BinaryMarshaler or encoding.TextMarshaler, such
+// as time.Time, are written in their own form.
+// MarshalCompact returns an error for a struct that holds itself through a
+// pointer cycle, which cannot be written.
+func MarshalCompact(v any) (data []byte, err error) {
+	var buf bytes.Buffer
+	if err = encodeCompact(&buf, reflect.ValueOf(v), logwalk.Seen{}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalCompact parses data written by MarshalCompact and stores the result
+// in the value pointed to by v, which must be of the type that was encoded.
+// Options left out by MarshalCompact are not provided.
+func UnmarshalCompact(data []byte, v any) (err error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("opt: UnmarshalCompact requires a non-nil pointer, got %T", v)
+	}
+
+	d := compactDecoder{data: data}
+	if err = d.decodeValue(rv.Elem()); err != nil {
+		return err
+	}
+
+	if d.pos != len(d.data) {
+		return errors.New("opt: unexpected data after compact value")
+	}
+
+	return nil
+}
+
+// compactForm describes how values of a type are written by MarshalCompact.
+type compactForm int
+
+const (
+	// compactPlain values are written according to their kind.
+	compactPlain compactForm = iota
+
+	// compactBinary values are written as the byte string produced by their
+	// encoding.BinaryMarshaler implementation.
+	compactBinary
+
+	// compactText values are written as the text string produced by their
+	// encoding.TextMarshaler implementation.
+	compactText
+)
+
+// compactFormOf returns the compactForm of t. Marshalers are only used when t
+// also implements the matching unmarshaler, so the value can be read back.
+func compactFormOf(t reflect.Type) (form compactForm) {
+	if isOptionType(t) {
+		return compactPlain
+	}
+
+	pt := reflect.PointerTo(t)
+
+	switch {
+	case pt.Implements(binaryMarshalerType) && pt.Implements(binaryUnmarshalerType):
+		return compactBinary
+	case pt.Implements(textMarshalerType) && pt.Implements(textUnmarshalerType):
+		return compactText
+	}
+
+	return compactPlain
+}
+
+// encodeCompact writes the compact encoding of v to buf.
+func encodeCompact(buf *bytes.Buffer, v reflect.Value, seen logwalk.Seen) (err error) {
+	if !v.IsValid() {
+		buf.WriteByte(cborNull)
+		return nil
+	}
+
+	t := v.Type()
+
+	if isOptionType(t) {
+		// An Option outside of a struct has no bitmap to record its presence
+		// in, so it is written as an array of zero or one values.
+		value, exists := v.Interface().(option).AnyValue()
+		if !exists {
+			writeCBORHead(buf, cborArray, 0)
+			return nil
+		}
+
+		writeCBORHead(buf, cborArray, 1)
+		return encodeCompact(buf, reflect.ValueOf(value), seen)
+	}
+
+	switch compactFormOf(t) {
+	case compactBinary:
+		b, err := addressable(v).Interface().(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		writeCBORHead(buf, cborBytes, uint64(len(b)))
+		buf.Write(b)
+		return nil
+	case compactText:
+		b, err := addressable(v).Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+
+		writeCBORHead(buf, cborText, uint64(len(b)))
+		buf.Write(b)
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			buf.WriteByte(cborTrue)
+		} else {
+			buf.WriteByte(cborFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := v.Int(); i < 0 {
+			writeCBORHead(buf, cborNegInt, uint64(-(i + 1)))
+		} else {
+			writeCBORHead(buf, cborUint, uint64(i))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		writeCBORHead(buf, cborUint, v.Uint())
+	case reflect.Float32:
+		buf.WriteByte(cborFloat32)
+		buf.Write(binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(v.Float()))))
+	case reflect.Float64:
+		buf.WriteByte(cborFloat64)
+		buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(v.Float())))
+	case reflect.String:
+		writeCBORHead(buf, cborText, uint64(v.Len()))
+		buf.WriteString(v.String())
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteByte(cborNull)
+			return nil
+		}
+
+		return encodeCompact(buf, v.Elem(), seen)
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteByte(cborNull)
+			return nil
+		}
+
+		if t.Elem().Kind() == reflect.Uint8 {
+			writeCBORHead(buf, cborBytes, uint64(v.Len()))
+			buf.Write(v.Bytes())
+			return nil
+		}
+
+		return encodeCompactArray(buf, v, seen)
+	case reflect.Array:
+		return encodeCompactArray(buf, v, seen)
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteByte(cborNull)
+			return nil
+		}
+
+		writeCBORHead(buf, cborMap, uint64(v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			if err = encodeCompact(buf, iter.Key(), seen); err != nil {
+				return err
+			}
+
+			if err = encodeCompact(buf, iter.Value(), seen); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return encodeCompactStruct(buf, v, seen)
+	default:
+		return fmt.Errorf("opt: unsupported type %s in compact encoding", t)
+	}
+
+	return nil
+}
+
+// encodeCompactArray writes the elements of the slice or array v to buf.
+func encodeCompactArray(buf *bytes.Buffer, v reflect.Value, seen logwalk.Seen) (err error) {
+	writeCBORHead(buf, cborArray, uint64(v.Len()))
+
+	for i := range v.Len() {
+		if err = encodeCompact(buf, v.Index(i), seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeCompactStruct writes the struct v to buf as an array holding the
+// presence bitmap followed by the values of its fields.
+func encodeCompactStruct(buf *bytes.Buffer, v reflect.Value, seen logwalk.Seen) (err error) {
+	if seen.Contains(v) {
+		return fmt.Errorf("opt: encountered a cycle via %s in compact encoding", v.Type())
+	}
+
+	seen.Enter(v)
+	defer seen.Leave(v)
+
+	fields := cachedFields(v.Type())
+	values := make([]reflect.Value, 0, len(fields))
+	bitmap := make([]byte, (countOptions(fields)+7)/8)
+
+	bit := 0
+	for _, f := range fields {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// A nil embedded pointer is written as the zero values of its
+			// promoted fields.
+			fv = reflect.Zero(f.typ)
+		}
+
+		if f.option {
+			value, exists := fv.Interface().(option).AnyValue()
+			if exists {
+				bitmap[bit/8] |= 1 << (bit % 8)
+				values = append(values, reflect.ValueOf(value))
+			}
+
+			bit++
+			continue
+		}
+
+		values = append(values, fv)
+	}
+
+	writeCBORHead(buf, cborArray, uint64(len(values)+1))
+	writeCBORHead(buf, cborBytes, uint64(len(bitmap)))
+	buf.Write(bitmap)
+
+	for _, fv := range values {
+		if err = encodeCompact(buf, fv, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// countOptions returns the number of fields holding an Option.
+func countOptions(fields []field) (n int) {
+	for _, f := range fields {
+		if f.option {
+			n++
+		}
+	}
+
+	return n
+}
+
+// addressable returns a pointer to a copy of v if v is not addressable, so
+// that methods with pointer receivers can be called on it.
+func addressable(v reflect.Value) (p reflect.Value) {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+
+	p = reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p
+}
+
+// writeCBORHead writes the initial byte of a CBOR data item of the major type
+// and its argument n.
+func writeCBORHead(buf *bytes.Buffer, major byte, n uint64) {
+	major <<= 5
+
+	switch {
+	case n < 24:
+		buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{major | 24, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(major | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= math.MaxUint32:
+		buf.WriteByte(major | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		buf.WriteByte(major | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+// compactDecoder reads values written by MarshalCompact.
+type compactDecoder struct {
+	// data holds the encoded input.
+	data []byte
+
+	// pos is the offset of the next unread byte in data.
+	pos int
+}
+
+// peek returns the next byte without consuming it.
+func (d *compactDecoder) peek() (b byte, err error) {
+	if d.pos >= len(d.data) {
+		return 0, errCompactEOF
+	}
+
+	return d.data[d.pos], nil
+}
+
+// next consumes and returns the next n bytes.
+func (d *compactDecoder) next(n uint64) (b []byte, err error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errCompactEOF
+	}
+
+	b = d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// readHead consumes the head of a data item and returns its major type and
+// argument. For major type 7 the argument is the raw additional information.
+func (d *compactDecoder) readHead() (major byte, n uint64, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	major, info := b[0]>>5, b[0]&0x1f
+	if major == cborSimple {
+		return major, uint64(info), nil
+	}
+
+	var size uint64
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info <= 27:
+		size = 1 << (info - 24)
+	default:
+		return 0, 0, fmt.Errorf("opt: invalid compact data at offset %d", d.pos-1)
+	}
+
+	arg, err := d.next(size)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, c := range arg {
+		n = n<<8 | uint64(c)
+	}
+
+	return major, n, nil
+}
+
+// readNull consumes a null and reports whether one was present.
+func (d *compactDecoder) readNull() (null bool, err error) {
+	b, err := d.peek()
+	if err != nil {
+		return false, err
+	}
+
+	if b != cborNull {
+		return false, nil
+	}
+
+	d.pos++
+	return true, nil
+}
+
+// expect consumes the head of a data item of the major type and returns its
+// argument, or returns an error naming t if the item is of another type.
+func (d *compactDecoder) expect(major byte, t reflect.Type) (n uint64, err error) {
+	start := d.pos
+
+	got, n, err := d.readHead()
+	if err != nil {
+		return 0, err
+	}
+
+	if got != major {
+		return 0, fmt.Errorf("opt: cannot decode compact data at offset %d into %s", start, t)
+	}
+
+	return n, nil
+}
+
+// readString consumes a byte or text string of the major type.
+func (d *compactDecoder) readString(major byte, t reflect.Type) (b []byte, err error) {
+	n, err := d.expect(major, t)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.next(n)
+}
+
+// decodeValue decodes the next value into v.
+func (d *compactDecoder) decodeValue(v reflect.Value) (err error) {
+	t := v.Type()
+
+	if isOptionType(t) {
+		n, err := d.expect(cborArray, t)
+		if err != nil {
+			return err
+		}
+
+		v.SetZero()
+		if n == 0 {
+			return nil
+		}
+
+		return d.decodeValue(v.Addr().Interface().(optionSetter).provide())
+	}
+
+	switch compactFormOf(t) {
+	case compactBinary:
+		b, err := d.readString(cborBytes, t)
+		if err != nil {
+			return err
+		}
+
+		return v.Addr().Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(b)
+	case compactText:
+		b, err := d.readString(cborText, t)
+		if err != nil {
+			return err
+		}
+
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(b)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		null, err := d.readNull()
+		if err != nil {
+			return err
+		}
+
+		if null {
+			v.SetZero()
+			return nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := d.next(1)
+		if err != nil {
+			return err
+		}
+
+		if b[0] != cborTrue && b[0] != cborFalse {
+			return fmt.Errorf("opt: cannot decode compact data at offset %d into %s", d.pos-1, t)
+		}
+
+		v.SetBool(b[0] == cborTrue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		start := d.pos
+
+		major, n, err := d.readHead()
+		if err != nil {
+			return err
+		}
+
+		var i int64
+		switch {
+		case major == cborUint && n <= math.MaxInt64:
+			i = int64(n)
+		case major == cborNegInt && n <= math.MaxInt64:
+			i = -int64(n) - 1
+		default:
+			return fmt.Errorf("opt: cannot decode compact data at offset %d into %s", start, t)
+		}
+
+		if v.OverflowInt(i) {
+			return fmt.Errorf("opt: compact value %d overflows %s", i, t)
+		}
+
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := d.expect(cborUint, t)
+		if err != nil {
+			return err
+		}
+
+		if v.OverflowUint(n) {
+			return fmt.Errorf("opt: compact value %d overflows %s", n, t)
+		}
+
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := d.readFloat(t)
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	case reflect.String:
+		b, err := d.readString(cborText, t)
+		if err != nil {
+			return err
+		}
+
+		v.SetString(string(b))
+	case reflect.Pointer:
+		p := reflect.New(t.Elem())
+		if err = d.decodeValue(p.Elem()); err != nil {
+			return err
+		}
+
+		v.Set(p)
+	case reflect.Interface:
+		if t.NumMethod() != 0 {
+			return fmt.Errorf("opt: unsupported type %s in compact encoding", t)
+		}
+
+		value, err := d.decodeAny()
+		if err != nil {
+			return err
+		}
+
+		if value == nil {
+			v.SetZero()
+			return nil
+		}
+
+		v.Set(reflect.ValueOf(value))
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b, err := d.readString(cborBytes, t)
+			if err != nil {
+				return err
+			}
+
+			v.SetBytes(bytes.Clone(b))
+			return nil
+		}
+
+		n, err := d.expect(cborArray, t)
+		if err != nil {
+			return err
+		}
+
+		if n > uint64(len(d.data)-d.pos) {
+			return errCompactEOF
+		}
+
+		s := reflect.MakeSlice(t, int(n), int(n))
+		for i := range int(n) {
+			if err = d.decodeValue(s.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		v.Set(s)
+	case reflect.Array:
+		n, err := d.expect(cborArray, t)
+		if err != nil {
+			return err
+		}
+
+		if n != uint64(t.Len()) {
+			return fmt.Errorf("opt: compact array of length %d cannot be decoded into %s", n, t)
+		}
+
+		for i := range t.Len() {
+			if err = d.decodeValue(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		n, err := d.expect(cborMap, t)
+		if err != nil {
+			return err
+		}
+
+		if n > uint64(len(d.data)-d.pos) {
+			return errCompactEOF
+		}
+
+		m := reflect.MakeMapWithSize(t, int(n))
+		for range n {
+			key, value := reflect.New(t.Key()).Elem(), reflect.New(t.Elem()).Elem()
+
+			if err = d.decodeValue(key); err != nil {
+				return err
+			}
+
+			if err = d.decodeValue(value); err != nil {
+				return err
+			}
+
+			m.SetMapIndex(key, value)
+		}
+
+		v.Set(m)
+	case reflect.Struct:
+		return d.decodeStruct(v)
+	default:
+		return fmt.Errorf("opt: unsupported type %s in compact encoding", t)
+	}
+
+	return nil
+}
+
+// readFloat consumes a float of either precision.
+func (d *compactDecoder) readFloat(t reflect.Type) (f float64, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, err
+	}
+
+	switch b[0] {
+	case cborFloat32:
+		bits, err := d.next(4)
+		if err != nil {
+			return 0, err
+		}
+
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(bits))), nil
+	case cborFloat64:
+		bits, err := d.next(8)
+		if err != nil {
+			return 0, err
+		}
+
+		return math.Float64frombits(binary.BigEndian.Uint64(bits)), nil
+	}
+
+	return 0, fmt.Errorf("opt: cannot decode compact data at offset %d into %s", d.pos-1, t)
+}
+
+// decodeStruct decodes a struct written by encodeCompactStruct into v.
+func (d *compactDecoder) decodeStruct(v reflect.Value) (err error) {
+	t := v.Type()
+	fields := cachedFields(t)
+
+	n, err := d.expect(cborArray, t)
+	if err != nil {
+		return err
+	}
+
+	bitmap, err := d.readString(cborBytes, t)
+	if err != nil {
+		return err
+	}
+
+	if len(bitmap) != (countOptions(fields)+7)/8 {
+		return fmt.Errorf("opt: compact presence bitmap does not match %s", t)
+	}
+
+	v.SetZero()
+
+	bit, read := 0, uint64(1)
+	for _, f := range fields {
+		if f.option {
+			exists := bitmap[bit/8]&(1<<(bit%8)) != 0
+			bit++
+
+			if !exists {
+				continue
+			}
+		}
+
+		if read == n {
+			return fmt.Errorf("opt: compact struct holds too few values for %s", t)
+		}
+		read++
+
+		fv := fieldByIndexAlloc(v, f.index)
+		if f.option {
+			fv = fv.Addr().Interface().(optionSetter).provide()
+		}
+
+		if err = d.decodeValue(fv); err != nil {
+			return err
+		}
+	}
+
+	if read != n {
+		return fmt.Errorf("opt: compact struct holds too many values for %s", t)
+	}
+
+	return nil
+}
+
+// decodeAny decodes the next value into the types encoding/json would use for
+// an interface{}, with integers decoding to int64 or uint64.
+func (d *compactDecoder) decodeAny() (value any, err error) {
+	b, err := d.peek()
+	if err != nil {
+		return nil, err
+	}
+
+	switch b {
+	case cborNull:
+		d.pos++
+		return nil, nil
+	case cborTrue, cborFalse:
+		d.pos++
+		return b == cborTrue, nil
+	case cborFloat32, cborFloat64:
+		return d.readFloat(reflect.TypeFor[float64]())
+	}
+
+	start := d.pos
+
+	major, n, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUint:
+		if n <= math.MaxInt64 {
+			return int64(n), nil
+		}
+
+		return n, nil
+	case cborNegInt:
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("opt: compact value at offset %d overflows int64", start)
+		}
+
+		return -int64(n) - 1, nil
+	case cborBytes:
+		b, err := d.next(n)
+		return bytes.Clone(b), err
+	case cborText:
+		b, err := d.next(n)
+		return string(b), err
+	case cborArray:
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, errCompactEOF
+		}
+
+		values := make([]any, n)
+		for i := range values {
+			if values[i], err = d.decodeAny(); err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+	case cborMap:
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, errCompactEOF
+		}
+
+		entries := make(map[string]any, n)
+		for range n {
+			key, err := d.decodeAny()
+			if err != nil {
+				return nil, err
+			}
+
+			s, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("opt: compact map key %v cannot be decoded into string", key)
+			}
+
+			if entries[s], err = d.decodeAny(); err != nil {
+				return nil, err
+			}
+		}
+
+		return entries, nil
+	}
+
+	return nil, fmt.Errorf("opt: invalid compact data at offset %d", start)
+}
+
+// fieldByIndexAlloc returns the field of the struct v at index, allocating
+// nil embedded pointers along the way.
+func fieldByIndexAlloc(v reflect.Value, index []int) (fv reflect.Value) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v
+}
@@ -0,0 +1,136 @@
+package opt_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testCompactEntry struct {
+	ID       int64                      `json:"id"`
+	Name     opt.Option[string]         `json:"name"`
+	Email    opt.Option[string]         `json:"email"`
+	Age      opt.Option[uint8]          `json:"age"`
+	Score    opt.Option[float64]        `json:"score"`
+	Ratio    opt.Option[float32]        `json:"ratio"`
+	Active   opt.Option[bool]           `json:"active"`
+	Offset   opt.Option[int]            `json:"offset"`
+	Tags     opt.Option[[]string]       `json:"tags"`
+	Avatar   opt.Option[[]byte]         `json:"avatar"`
+	Labels   opt.Option[map[string]int] `json:"labels"`
+	Parent   opt.Option[*int64]         `json:"parent"`
+	Created  opt.Option[time.Time]      `json:"created"`
+	Extra    opt.Option[any]            `json:"extra"`
+	Children []opt.Option[int]          `json:"children"`
+	Nested   *testCompactEntry          `json:"nested"`
+}
+
+var compactTestCases = map[string]testCompactEntry{
+	"Sparse": {ID: 1, Name: opt.Some("Ada")},
+	"Empty":  {},
+	"Full": {
+		ID:       2,
+		Name:     opt.Some(""),
+		Email:    opt.Some("ada@example.com"),
+		Age:      opt.Some(uint8(36)),
+		Score:    opt.Some(-1.5),
+		Ratio:    opt.Some(float32(0.25)),
+		Active:   opt.Some(false),
+		Offset:   opt.Some(-300),
+		Tags:     opt.Some([]string{"a", "b"}),
+		Avatar:   opt.Some([]byte{0xde, 0xad}),
+		Labels:   opt.Some(map[string]int{"x": 1}),
+		Parent:   opt.Some[*int64](nil),
+		Created:  opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Extra:    opt.Some[any](map[string]any{"list": []any{"x", 1.5, true, nil}}),
+		Children: []opt.Option[int]{opt.Some(1), opt.None[int](), opt.Some(3)},
+		Nested:   &testCompactEntry{ID: 3, Active: opt.Some(true)},
+	},
+}
+
+func Test_Compact(t *testing.T) {
+	for n, entry := range compactTestCases {
+		t.Run(n, func(t *testing.T) {
+			data, err := opt.MarshalCompact(entry)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %s", err)
+			}
+
+			jsonData, err := opt.Marshal(entry)
+			if err != nil {
+				t.Fatalf("Unexpected JSON marshal error: %s", err)
+			}
+
+			var result testCompactEntry
+			if err = opt.UnmarshalCompact(data, &result); err != nil {
+				t.Fatalf("Unexpected unmarshal error: %s", err)
+			}
+
+			snaps.MatchSnapshot(t, fmt.Sprintf("%x", data), len(data) < len(jsonData), result)
+		})
+	}
+
+	t.Run("Standalone Option", func(t *testing.T) {
+		data, err := opt.MarshalCompact(opt.Some("hello world"))
+		if err != nil {
+			t.Fatalf("Unexpected marshal error: %s", err)
+		}
+
+		var result opt.Option[string]
+		err = opt.UnmarshalCompact(data, &result)
+		snaps.MatchSnapshot(t, fmt.Sprintf("%x", data), result, fmt.Sprint(err))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := opt.MarshalCompact(struct{ C opt.Option[chan int] }{C: opt.Some(make(chan int))})
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		node := &testNode{Name: opt.Some("loop")}
+		node.Next = node
+
+		_, err := opt.MarshalCompact(node)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		data, _ := opt.MarshalCompact(compactTestCases["Sparse"])
+
+		var result testCompactEntry
+		err := opt.UnmarshalCompact(data[:len(data)-1], &result)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Trailing data", func(t *testing.T) {
+		data, _ := opt.MarshalCompact(compactTestCases["Sparse"])
+
+		var result testCompactEntry
+		err := opt.UnmarshalCompact(append(data, 0), &result)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Wrong type", func(t *testing.T) {
+		data, _ := opt.MarshalCompact(compactTestCases["Sparse"])
+
+		var result struct{ Name opt.Option[string] }
+		err := opt.UnmarshalCompact(data, &result)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		data, _ := opt.MarshalCompact(int64(300))
+
+		var result int8
+		err := opt.UnmarshalCompact(data, &result)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Not a pointer", func(t *testing.T) {
+		err := opt.UnmarshalCompact(nil, testCompactEntry{})
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+}
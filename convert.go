@@ -0,0 +1,250 @@
+package opt
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// convertValue converts src, a non-nil value handed over by a database driver
+// or a similar decoder, to T.
+// Values assignable to T are used as is, strings and byte slices are parsed
+// into numbers, booleans and encoding.TextUnmarshaler implementations such as
+// time.Time, numbers are converted between numeric kinds and the integers 0
+// and 1 are converted to booleans.
+func convertValue[T any](src any) (value T, err error) {
+	if v, ok := src.(T); ok {
+		return v, nil
+	}
+
+	dst := reflect.ValueOf(&value).Elem()
+	if err = assignValue(dst, reflect.ValueOf(src)); err != nil {
+		return value, err
+	}
+
+	return value, nil
+}
+
+// assignValue does the work for convertValue, storing src in dst.
+func assignValue(dst, src reflect.Value) (err error) {
+	dt, st := dst.Type(), src.Type()
+
+	if st.AssignableTo(dt) {
+		dst.Set(src)
+		return nil
+	}
+
+	var text []byte
+	isText := true
+	switch {
+	case st.Kind() == reflect.String:
+		text = []byte(src.String())
+	case st.Kind() == reflect.Slice && st.Elem().Kind() == reflect.Uint8:
+		text = src.Bytes()
+	default:
+		isText = false
+	}
+
+	if isText {
+		if u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText(text)
+		}
+
+		return parseText(dst, string(text))
+	}
+
+	if isNumberKind(st.Kind()) && isNumberKind(dt.Kind()) {
+		return convertNumber(dst, src)
+	}
+
+	// Databases without a boolean type hand booleans over as 0 or 1.
+	if dt.Kind() == reflect.Bool && isIntegerKind(st.Kind()) {
+		switch {
+		case src.CanInt() && (src.Int() == 0 || src.Int() == 1):
+			dst.SetBool(src.Int() == 1)
+			return nil
+		case src.CanUint() && src.Uint() <= 1:
+			dst.SetBool(src.Uint() == 1)
+			return nil
+		}
+	}
+
+	return conversionError(src.Interface(), dt)
+}
+
+// convertNumber stores the number src in dst, which holds a number of another
+// kind. As database/sql does, it fails rather than wrap values out of the range
+// of dst or drop the fractional part of floats converted to integers.
+func convertNumber(dst, src reflect.Value) (err error) {
+	dt := dst.Type()
+
+	if src.CanFloat() {
+		f := src.Float()
+
+		switch {
+		case dst.CanFloat():
+			if !math.IsInf(f, 0) && dst.OverflowFloat(f) {
+				return rangeError(src, dt)
+			}
+
+			dst.SetFloat(f)
+			return nil
+		case f != math.Trunc(f):
+			return fmt.Errorf("opt: cannot convert %v to %s: value has a fractional part", src, dt)
+		case dst.CanInt():
+			if f < math.MinInt64 || f >= math.MaxInt64 || dst.OverflowInt(int64(f)) {
+				return rangeError(src, dt)
+			}
+
+			dst.SetInt(int64(f))
+			return nil
+		default:
+			if f < 0 || f >= math.MaxUint64 || dst.OverflowUint(uint64(f)) {
+				return rangeError(src, dt)
+			}
+
+			dst.SetUint(uint64(f))
+			return nil
+		}
+	}
+
+	switch {
+	case dst.CanFloat():
+		dst.Set(src.Convert(dt))
+	case dst.CanInt():
+		if src.CanUint() && src.Uint() > math.MaxInt64 ||
+			src.CanInt() && dst.OverflowInt(src.Int()) ||
+			src.CanUint() && dst.OverflowInt(int64(src.Uint())) {
+			return rangeError(src, dt)
+		}
+
+		dst.Set(src.Convert(dt))
+	default:
+		if src.CanInt() && (src.Int() < 0 || dst.OverflowUint(uint64(src.Int()))) ||
+			src.CanUint() && dst.OverflowUint(src.Uint()) {
+			return rangeError(src, dt)
+		}
+
+		dst.Set(src.Convert(dt))
+	}
+
+	return nil
+}
+
+// parseText parses s into dst according to the kind of dst.
+func parseText(dst reflect.Value, s string) (err error) {
+	dt := dst.Type()
+
+	switch dt.Kind() {
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Slice:
+		if dt.Elem().Kind() != reflect.Uint8 {
+			return conversionError(s, dt)
+		}
+
+		dst.SetBytes([]byte(s))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, dt.Bits())
+		if err != nil {
+			return err
+		}
+
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, dt.Bits())
+		if err != nil {
+			return err
+		}
+
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, dt.Bits())
+		if err != nil {
+			return err
+		}
+
+		dst.SetFloat(f)
+	default:
+		return conversionError(s, dt)
+	}
+
+	return nil
+}
+
+// isNumberKind reports whether k is an integer or floating point kind.
+func isNumberKind(k reflect.Kind) (ok bool) {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// isIntegerKind reports whether k is a signed or unsigned integer kind.
+func isIntegerKind(k reflect.Kind) (ok bool) {
+	return isNumberKind(k) && k != reflect.Float32 && k != reflect.Float64
+}
+
+// conversionError returns the error reported when src cannot be converted to
+// the type t.
+func conversionError(src any, t reflect.Type) (err error) {
+	return fmt.Errorf("opt: cannot convert %T to %s", src, t)
+}
+
+// rangeError returns the error reported when the number src is out of the
+// range of the type t.
+func rangeError(src reflect.Value, t reflect.Type) (err error) {
+	return fmt.Errorf("opt: cannot convert %v to %s: value out of range", src, t)
+}
+
+// formatValue returns the text form of v, the inverse of the parsing done by
+// convertValue for strings.
+// Implementations of encoding.TextMarshaler are asked for their text, byte
+// slices are used as is and numbers and booleans are formatted with strconv.
+func formatValue(v any) (text string, err error) {
+	if tm, ok := v.(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", err
+		}
+
+		return string(b), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return "", nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(rv.Bytes()), nil
+		}
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()), nil
+	}
+
+	return fmt.Sprint(v), nil
+}
@@ -0,0 +1,34 @@
+package opt
+
+import (
+	"net/http"
+)
+
+// FromCookie returns an Option holding the value of the cookie named name sent
+// with r.
+// If r has the cookie, FromCookie returns Some of its value, even if it is
+// empty.
+// If r does not have the cookie, FromCookie returns None.
+func FromCookie(r *http.Request, name string) (o Option[string]) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return None[string]()
+	}
+
+	return Some(c.Value)
+}
+
+// FromCookieFunc returns an Option holding the value of the cookie named name
+// sent with r, parsed by parse:
+//
+//	session := opt.FromCookieFunc(r, "session", uuid.Parse)
+//
+// If r has the cookie and parse succeeds, FromCookieFunc returns Some of the
+// parsed value.
+// If r does not have the cookie, or parse returns an error, FromCookieFunc
+// returns None.
+func FromCookieFunc[T any](r *http.Request, name string, parse func(value string) (T, error)) (o Option[T]) {
+	return FlatMap(FromCookie(r, name), func(value string) Option[T] {
+		return Wrap(parse(value))
+	})
+}
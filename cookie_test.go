@@ -0,0 +1,47 @@
+package opt_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func testCookieRequest() (r *http.Request) {
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	r.AddCookie(&http.Cookie{Name: "empty", Value: ""})
+	r.AddCookie(&http.Cookie{Name: "count", Value: "3"})
+	return r
+}
+
+func Test_FromCookie(t *testing.T) {
+	testCases := map[string]string{
+		"Present": "session",
+		"Empty":   "empty",
+		"Missing": "missing",
+	}
+
+	for n, name := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, opt.FromCookie(testCookieRequest(), name))
+		})
+	}
+}
+
+func Test_FromCookieFunc(t *testing.T) {
+	testCases := map[string]string{
+		"Parsed":  "count",
+		"Invalid": "session",
+		"Missing": "missing",
+	}
+
+	for n, name := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, opt.FromCookieFunc(testCookieRequest(), name, strconv.Atoi))
+		})
+	}
+}
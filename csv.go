@@ -0,0 +1,36 @@
+package opt
+
+// MarshalCSV implements the gocsv.TypeMarshaller interface so an Option can be
+// written as a CSV cell by github.com/gocarina/gocsv.
+// If the value is provided, MarshalCSV returns its text form: the text of an
+// encoding.TextMarshaler, or the formatted string, number or boolean.
+// If the value is not provided, MarshalCSV returns an empty cell.
+func (o Option[T]) MarshalCSV() (cell string, err error) {
+	if !o.exists {
+		return "", nil
+	}
+
+	return formatValue(o.value)
+}
+
+// UnmarshalCSV implements the gocsv.TypeUnmarshaller interface so an Option
+// can be read from a CSV cell by github.com/gocarina/gocsv.
+// If the cell is empty, the value is not set and UnmarshalCSV returns nil.
+// This means a provided empty string does not survive a round trip.
+// Otherwise UnmarshalCSV parses the cell into T and sets exists to true.
+func (o *Option[T]) UnmarshalCSV(cell string) (err error) {
+	*o = Option[T]{}
+
+	if cell == "" {
+		return nil
+	}
+
+	value, err := convertValue[T](cell)
+	if err != nil {
+		return err
+	}
+
+	o.value = value
+	o.exists = true
+	return nil
+}
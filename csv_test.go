@@ -0,0 +1,62 @@
+package opt_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/gocarina/gocsv"
+)
+
+type testCSVRow struct {
+	ID      int                   `csv:"id"`
+	Name    opt.Option[string]    `csv:"name"`
+	Age     opt.Option[int]       `csv:"age"`
+	Score   opt.Option[float64]   `csv:"score"`
+	Active  opt.Option[bool]      `csv:"active"`
+	Created opt.Option[time.Time] `csv:"created"`
+}
+
+func Test_CSV(t *testing.T) {
+	t.Run("Marshal", func(t *testing.T) {
+		rows := []testCSVRow{
+			{ID: 1},
+			{
+				ID:      2,
+				Name:    opt.Some("Ada"),
+				Age:     opt.Some(36),
+				Score:   opt.Some(0.25),
+				Active:  opt.Some(false),
+				Created: opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+			},
+		}
+
+		result, err := gocsv.MarshalString(rows)
+		if err != nil {
+			t.Fatalf("Unexpected marshal error: %s", err)
+		}
+
+		snaps.MatchSnapshot(t, result)
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		data := "id,name,age,score,active,created\n" +
+			"1,,,,,\n" +
+			"2,Ada,36,0.25,false,2024-01-02T03:04:05Z\n"
+
+		var rows []testCSVRow
+		if err := gocsv.UnmarshalString(data, &rows); err != nil {
+			t.Fatalf("Unexpected unmarshal error: %s", err)
+		}
+
+		snaps.MatchSnapshot(t, rows)
+	})
+
+	t.Run("Unmarshal invalid", func(t *testing.T) {
+		var rows []testCSVRow
+		err := gocsv.UnmarshalString("id,age\n1,thirty\n", &rows)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+}
@@ -0,0 +1,101 @@
+package opt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCursor is returned by DecodeCursor when a token is malformed or
+// its signature does not match.
+var ErrInvalidCursor = errors.New("opt: invalid cursor")
+
+// cursorConfig holds the settings of EncodeCursor and DecodeCursor.
+type cursorConfig struct {
+	// key is the HMAC key tokens are signed with, or nil if they are not
+	// signed.
+	key []byte
+}
+
+// CursorOption configures EncodeCursor and DecodeCursor.
+type CursorOption func(c *cursorConfig)
+
+// WithCursorKey signs tokens with an HMAC-SHA256 of key so that clients cannot
+// forge or alter them. The same key must be given to EncodeCursor and
+// DecodeCursor.
+func WithCursorKey(key []byte) (option CursorOption) {
+	return func(c *cursorConfig) {
+		c.key = key
+	}
+}
+
+// EncodeCursor returns an opaque pagination token holding the cursor v,
+// typically a struct of Option fields.
+// The token is the URL-safe, unpadded base64 encoding of Marshal(v), so only
+// the fields that are provided take up space and DecodeCursor leaves the
+// others not provided.
+func EncodeCursor(v any, opts ...CursorOption) (token string, err error) {
+	c := newCursorConfig(opts)
+
+	data, err := Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	if c.key != nil {
+		data = append(data, c.sign(data)...)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor parses a token produced by EncodeCursor and stores the cursor
+// in the value pointed to by v.
+// If the token is not valid base64, or WithCursorKey is given and the
+// signature does not match, DecodeCursor returns an error wrapping
+// ErrInvalidCursor.
+func DecodeCursor(token string, v any, opts ...CursorOption) (err error) {
+	c := newCursorConfig(opts)
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	if c.key != nil {
+		if len(data) < sha256.Size {
+			return fmt.Errorf("%w: missing signature", ErrInvalidCursor)
+		}
+
+		payload, mac := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+		if !hmac.Equal(mac, c.sign(payload)) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidCursor)
+		}
+
+		data = payload
+	}
+
+	if err = Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return nil
+}
+
+// newCursorConfig returns the cursorConfig resulting from opts.
+func newCursorConfig(opts []CursorOption) (c cursorConfig) {
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
+
+// sign returns the HMAC-SHA256 of data under the configured key.
+func (c cursorConfig) sign(data []byte) (mac []byte) {
+	h := hmac.New(sha256.New, c.key)
+	h.Write(data)
+	return h.Sum(nil)
+}
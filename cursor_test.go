@@ -0,0 +1,87 @@
+package opt_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testCursor struct {
+	AfterID      opt.Option[int64]     `json:"after_id"`
+	AfterCreated opt.Option[time.Time] `json:"after_created"`
+	Query        opt.Option[string]    `json:"q"`
+}
+
+var testCursorKey = []byte("secret")
+
+func Test_Cursor(t *testing.T) {
+	cursor := testCursor{
+		AfterID:      opt.Some(int64(42)),
+		AfterCreated: opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+	}
+
+	t.Run("Unsigned", func(t *testing.T) {
+		token, err := opt.EncodeCursor(cursor)
+		if err != nil {
+			t.Fatalf("Unexpected encode error: %s", err)
+		}
+
+		var result testCursor
+		err = opt.DecodeCursor(token, &result)
+		snaps.MatchSnapshot(t, token, fmt.Sprint(err), result)
+	})
+
+	t.Run("Signed", func(t *testing.T) {
+		token, err := opt.EncodeCursor(cursor, opt.WithCursorKey(testCursorKey))
+		if err != nil {
+			t.Fatalf("Unexpected encode error: %s", err)
+		}
+
+		var result testCursor
+		err = opt.DecodeCursor(token, &result, opt.WithCursorKey(testCursorKey))
+		snaps.MatchSnapshot(t, token, fmt.Sprint(err), result)
+	})
+
+	t.Run("Empty cursor", func(t *testing.T) {
+		token, err := opt.EncodeCursor(testCursor{})
+		if err != nil {
+			t.Fatalf("Unexpected encode error: %s", err)
+		}
+
+		var result testCursor
+		err = opt.DecodeCursor(token, &result)
+		snaps.MatchSnapshot(t, token, fmt.Sprint(err), result)
+	})
+
+	t.Run("Wrong key", func(t *testing.T) {
+		token, _ := opt.EncodeCursor(cursor, opt.WithCursorKey(testCursorKey))
+
+		var result testCursor
+		err := opt.DecodeCursor(token, &result, opt.WithCursorKey([]byte("other")))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), errors.Is(err, opt.ErrInvalidCursor))
+	})
+
+	t.Run("Unsigned token with key", func(t *testing.T) {
+		token, _ := opt.EncodeCursor(testCursor{})
+
+		var result testCursor
+		err := opt.DecodeCursor(token, &result, opt.WithCursorKey(testCursorKey))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), errors.Is(err, opt.ErrInvalidCursor))
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		var result testCursor
+		err := opt.DecodeCursor("not a cursor!", &result)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), errors.Is(err, opt.ErrInvalidCursor))
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		var result testCursor
+		err := opt.DecodeCursor("bm90IGpzb24", &result)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), errors.Is(err, opt.ErrInvalidCursor))
+	})
+}
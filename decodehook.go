@@ -0,0 +1,84 @@
+package opt
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// DecodeHook returns a decode hook that populates Option fields for
+// mapstructure-based decoders such as github.com/go-viper/mapstructure/v2,
+// github.com/mitchellh/mapstructure and the config loaders built on them:
+//
+//	config := &mapstructure.DecoderConfig{
+//		DecodeHook: opt.DecodeHook(),
+//		Result:     &target,
+//	}
+//
+// The hook is a mapstructure.DecodeHookFuncValue, so this package does not
+// depend on any of them. It can be combined with other hooks by
+// mapstructure.ComposeDecodeHookFunc.
+// When the target is an Option, the hook converts the input to T and returns
+// an Option holding it, so that keys present in the input are recorded as
+// provided, even when their value is the zero value of T.
+// Values assignable to T are used as is, strings are parsed into numbers,
+// booleans and encoding.TextUnmarshaler implementations, and into durations by
+// time.ParseDuration, and numbers are converted between numeric kinds. Other input, such as a map decoded into a
+// struct, is converted through JSON, so struct fields are matched by their
+// JSON names.
+// Keys missing from the input are not passed to the hook, so their Options
+// are left untouched. A nil input decodes to an Option whose value is not
+// provided.
+func DecodeHook() (hook func(from, to reflect.Value) (value any, err error)) {
+	return decodeHook
+}
+
+// decodeHook implements the hook returned by DecodeHook.
+func decodeHook(from, to reflect.Value) (value any, err error) {
+	if !from.IsValid() {
+		return nil, nil
+	}
+
+	if !to.IsValid() || !IsOptionType(to.Type()) || from.Type() == to.Type() {
+		return from.Interface(), nil
+	}
+
+	for from.Kind() == reflect.Interface {
+		from = from.Elem()
+	}
+
+	o := reflect.New(to.Type())
+	if !from.IsValid() || (from.Kind() == reflect.Pointer && from.IsNil()) {
+		return o.Elem().Interface(), nil
+	}
+
+	dst := o.Interface().(optionSetter).provide()
+	if dst.Type() == durationType && from.Kind() == reflect.String {
+		d, err := time.ParseDuration(from.String())
+		if err != nil {
+			return nil, err
+		}
+
+		dst.SetInt(int64(d))
+		return o.Elem().Interface(), nil
+	}
+
+	if err = assignValue(dst, from); err != nil {
+		switch from.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		default:
+			return nil, err
+		}
+
+		data, err := json.Marshal(from.Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(data, dst.Addr().Interface()); err != nil {
+			return nil, err
+		}
+	}
+
+	return o.Elem().Interface(), nil
+}
@@ -0,0 +1,84 @@
+package opt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/go-viper/mapstructure/v2"
+)
+
+type testDecodeAddress struct {
+	City     string `json:"city"`
+	Postcode string `json:"postcode"`
+}
+
+type testDecodeConfig struct {
+	Port    opt.Option[int]               `mapstructure:"port"`
+	Host    opt.Option[string]            `mapstructure:"host"`
+	Debug   opt.Option[bool]              `mapstructure:"debug"`
+	Timeout opt.Option[time.Duration]     `mapstructure:"timeout"`
+	Started opt.Option[time.Time]         `mapstructure:"started"`
+	Tags    opt.Option[[]string]          `mapstructure:"tags"`
+	Address opt.Option[testDecodeAddress] `mapstructure:"address"`
+	Nick    opt.Tracked[string]           `mapstructure:"nick"`
+	Plain   int                           `mapstructure:"plain"`
+}
+
+// decode decodes input into a testDecodeConfig with opt.DecodeHook.
+func decode(input map[string]any) (config testDecodeConfig, err error) {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: opt.DecodeHook(),
+		Result:     &config,
+	})
+	if err != nil {
+		return config, err
+	}
+
+	err = decoder.Decode(input)
+	return config, err
+}
+
+func Test_DecodeHook(t *testing.T) {
+	testCases := map[string]map[string]any{
+		"Empty": {},
+		"Zero values": {
+			"port":  0,
+			"host":  "",
+			"debug": false,
+		},
+		"Values": {
+			"port":    8080,
+			"host":    "localhost",
+			"debug":   true,
+			"timeout": time.Second,
+			"tags":    []any{"a", "b"},
+			"address": map[string]any{"city": "London"},
+			"nick":    "ada",
+			"plain":   1,
+		},
+		"Strings": {
+			"port":    "8080",
+			"debug":   "true",
+			"timeout": "30s",
+			"started": "2024-01-02T03:04:05Z",
+		},
+		"Converted numbers": {
+			"port": 8080.0,
+		},
+		"Nil": {
+			"host": nil,
+		},
+		"Invalid": {
+			"port": "eighty",
+		},
+	}
+
+	for n, input := range testCases {
+		t.Run(n, func(t *testing.T) {
+			config, err := decode(input)
+			snaps.MatchSnapshot(t, config, err)
+		})
+	}
+}
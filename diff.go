@@ -0,0 +1,86 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Diff compares the structs old and new and returns a P holding the values of
+// new that differ from old, such as the payload of a change event:
+//
+//	type UserChanged struct {
+//		ID    int64              `json:"id"`
+//		Name  opt.Option[string] `json:"name"`
+//		Email opt.Option[string] `json:"email"`
+//	}
+//
+//	changes, err := opt.Diff[UserChanged](before, after)
+//
+// P is a struct whose fields are matched to those of T by JSON name. An Option
+// field of P is provided with the value of the field of new if it differs from
+// that of old, as compared by reflect.DeepEqual, and converted as by Scan.
+// Fields of T that are themselves Options are recorded in fields of type
+// Option[Option[V]], so that a value removed by new can be told apart from
+// one that did not change. A struct field of P holding Options is compared
+// field by field against the struct field of T, and any other field of P is
+// set from new whether or not it changed, so that P can carry identifiers.
+// Fields of T missing from P are not compared. If a field of P has no
+// matching field in T, Diff returns an error.
+func Diff[P, T any](old, new T) (diff P, err error) {
+	pt, tt := reflect.TypeFor[P](), reflect.TypeFor[T]()
+
+	if !isPatchStruct(pt) || tt.Kind() != reflect.Struct || isOptionType(tt) {
+		return diff, fmt.Errorf("opt: Diff requires a struct of Options and a struct, got %s and %s", pt, tt)
+	}
+
+	err = diffStruct(reflect.ValueOf(&diff).Elem(), reflect.ValueOf(old), reflect.ValueOf(new), "")
+	return diff, err
+}
+
+// diffStruct records in the struct dst the fields of the struct b that differ
+// from those of the struct a, found at path.
+func diffStruct(dst, a, b reflect.Value, path string) (err error) {
+	sources := map[string]field{}
+	for _, f := range cachedFields(a.Type()) {
+		sources[f.name] = f
+	}
+
+	for _, f := range cachedFields(dst.Type()) {
+		fieldPath := joinPath(path, f.name)
+
+		sf, ok := sources[f.name]
+		if !ok {
+			return fmt.Errorf("opt: Diff found no field %s in %s", fieldPath, a.Type())
+		}
+
+		av, aerr := a.FieldByIndexErr(sf.index)
+		bv, berr := b.FieldByIndexErr(sf.index)
+		if aerr != nil {
+			av = reflect.Zero(sf.typ)
+		}
+		if berr != nil {
+			bv = reflect.Zero(sf.typ)
+		}
+
+		fv := allocFieldByIndex(dst, f.index)
+
+		switch {
+		case f.option:
+			if reflect.DeepEqual(av.Interface(), bv.Interface()) {
+				continue
+			}
+		case isPatchStruct(f.typ) && sf.typ.Kind() == reflect.Struct && !isLeafType(sf.typ):
+			if err = diffStruct(fv, av, bv, fieldPath); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err = applyValue(fv, bv, fieldPath); err != nil {
+			return fmt.Errorf("opt: Diff %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package opt_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testDiffUser struct {
+	ID       int64              `json:"id"`
+	Name     string             `json:"name"`
+	Age      int32              `json:"age"`
+	Tags     []string           `json:"tags"`
+	Manager  opt.Option[string] `json:"manager"`
+	Address  testApplyAddress   `json:"address"`
+	Password string             `json:"password"`
+}
+
+type testDiffChanges struct {
+	ID      int64                          `json:"id"`
+	Name    opt.Option[string]             `json:"name"`
+	Age     opt.Option[int64]              `json:"age"`
+	Tags    opt.Option[[]string]           `json:"tags"`
+	Manager opt.Option[opt.Option[string]] `json:"manager"`
+	Address testApplyAddressPatch          `json:"address"`
+}
+
+func Test_Diff(t *testing.T) {
+	before := testDiffUser{
+		ID:       1,
+		Name:     "Ada",
+		Age:      36,
+		Tags:     []string{"admin"},
+		Manager:  opt.Some("Charles"),
+		Address:  testApplyAddress{City: "London", Postcode: "W1"},
+		Password: "hunter2",
+	}
+
+	t.Run("Changed", func(t *testing.T) {
+		after := before
+		after.Age = 37
+		after.Tags = []string{"admin", "owner"}
+		after.Manager = opt.None[string]()
+		after.Address.Postcode = "NW1"
+		after.Password = "hunter3"
+
+		changes, err := opt.Diff[testDiffChanges](before, after)
+		data, _ := opt.Marshal(changes)
+		snaps.MatchSnapshot(t, string(data), fmt.Sprint(err))
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		changes, err := opt.Diff[testDiffChanges](before, before)
+		data, _ := opt.Marshal(changes)
+		snaps.MatchSnapshot(t, string(data), fmt.Sprint(err))
+	})
+
+	t.Run("Missing field", func(t *testing.T) {
+		_, err := opt.Diff[struct {
+			Email opt.Option[string] `json:"email"`
+		}](before, before)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Conversion error", func(t *testing.T) {
+		after := before
+		after.Name = "Ada Lovelace"
+
+		_, err := opt.Diff[struct {
+			Name opt.Option[int] `json:"name"`
+		}](before, after)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		_, err := opt.Diff[testDiffChanges](1, 2)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+}
@@ -0,0 +1,101 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// EffectiveValue returns the value that applies once a default is taken into
+// account.
+// If the value is provided, EffectiveValue returns it and fromDefault is false.
+// If the value is not provided, EffectiveValue returns def and fromDefault is
+// true.
+func EffectiveValue[T any](o Option[T], def T) (value T, fromDefault bool) {
+	if o.exists {
+		return o.value, false
+	}
+
+	return def, true
+}
+
+// EffectiveField describes the value that applies to a single struct field
+// once defaults are taken into account.
+type EffectiveField struct {
+	// Path is the dotted path of JSON names leading to the field.
+	Path string
+
+	// Value is the value that applies to the field.
+	Value any
+
+	// FromDefault reports whether Value was taken from the defaults rather
+	// than from the input.
+	FromDefault bool
+}
+
+// EffectiveFields resolves every field of the struct v against the struct
+// defaults, which must be of the same type, and reports where each value came
+// from. Either may be given as a pointer to the struct.
+// If an Option field of v is provided, its value is used. If it is not
+// provided, the field of defaults is used instead and FromDefault is true; if
+// the default is an Option that was not provided either, Value is nil.
+// Fields that do not hold an Option always come from v, except for nested
+// structs holding Options, whose fields are resolved one by one.
+// The fields are returned in declaration order.
+func EffectiveFields(v, defaults any) (fields []EffectiveField, err error) {
+	rv, rd := indirectValue(reflect.ValueOf(v)), indirectValue(reflect.ValueOf(defaults))
+
+	if !rv.IsValid() || rv.Kind() != reflect.Struct || !rd.IsValid() || rd.Type() != rv.Type() {
+		return nil, fmt.Errorf("opt: EffectiveFields requires two structs of the same type, got %T and %T", v, defaults)
+	}
+
+	return appendEffectiveFields(nil, "", rv, rd), nil
+}
+
+// appendEffectiveFields appends the resolved fields of the struct v, with
+// defaults taken from the struct d, to fields.
+func appendEffectiveFields(fields []EffectiveField, path string, v, d reflect.Value) (appended []EffectiveField) {
+	for _, f := range cachedFields(v.Type()) {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+
+		fd, err := d.FieldByIndexErr(f.index)
+		if err != nil {
+			fd = reflect.Zero(f.typ)
+		}
+
+		fieldPath := joinPath(path, f.name)
+
+		switch {
+		case f.option:
+			if value, exists := fv.Interface().(option).AnyValue(); exists {
+				fields = append(fields, EffectiveField{Path: fieldPath, Value: value})
+				continue
+			}
+
+			value, exists := fd.Interface().(option).AnyValue()
+			if !exists {
+				value = nil
+			}
+
+			fields = append(fields, EffectiveField{Path: fieldPath, Value: value, FromDefault: true})
+		case f.typ.Kind() == reflect.Struct && !isLeafType(f.typ) && containsOption(f.typ):
+			fields = appendEffectiveFields(fields, fieldPath, fv, fd)
+		default:
+			fields = append(fields, EffectiveField{Path: fieldPath, Value: fv.Interface()})
+		}
+	}
+
+	return fields
+}
+
+// indirectValue follows v through pointers, returning the zero Value if a nil
+// pointer is reached.
+func indirectValue(v reflect.Value) (indirect reflect.Value) {
+	for v.IsValid() && v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	return v
+}
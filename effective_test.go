@@ -0,0 +1,72 @@
+package opt_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testConfigServer struct {
+	Host opt.Option[string] `json:"host"`
+	Port opt.Option[int]    `json:"port"`
+}
+
+type testConfig struct {
+	Name    string             `json:"name"`
+	Debug   opt.Option[bool]   `json:"debug"`
+	Workers opt.Option[int]    `json:"workers"`
+	Region  opt.Option[string] `json:"region"`
+	Server  testConfigServer   `json:"server"`
+}
+
+var testConfigDefaults = testConfig{
+	Name:    "default",
+	Debug:   opt.Some(false),
+	Workers: opt.Some(4),
+	Server: testConfigServer{
+		Host: opt.Some("localhost"),
+		Port: opt.Some(8080),
+	},
+}
+
+func Test_EffectiveValue(t *testing.T) {
+	t.Run("Provided", func(t *testing.T) {
+		value, fromDefault := opt.EffectiveValue(opt.Some(0), 10)
+		snaps.MatchSnapshot(t, value, fromDefault)
+	})
+
+	t.Run("Not provided", func(t *testing.T) {
+		value, fromDefault := opt.EffectiveValue(opt.None[int](), 10)
+		snaps.MatchSnapshot(t, value, fromDefault)
+	})
+}
+
+func Test_EffectiveFields(t *testing.T) {
+	t.Run("Mixed", func(t *testing.T) {
+		config := testConfig{
+			Name:    "api",
+			Workers: opt.Some(0),
+			Server:  testConfigServer{Port: opt.Some(9090)},
+		}
+
+		fields, err := opt.EffectiveFields(&config, testConfigDefaults)
+		snaps.MatchSnapshot(t, fields, fmt.Sprint(err))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		fields, err := opt.EffectiveFields(testConfig{}, &testConfigDefaults)
+		snaps.MatchSnapshot(t, fields, fmt.Sprint(err))
+	})
+
+	t.Run("Mismatched types", func(t *testing.T) {
+		fields, err := opt.EffectiveFields(testConfig{}, testConfigServer{})
+		snaps.MatchSnapshot(t, fields, fmt.Sprint(err))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		fields, err := opt.EffectiveFields(1, 2)
+		snaps.MatchSnapshot(t, fields, fmt.Sprint(err))
+	})
+}
@@ -0,0 +1,149 @@
+package opt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Either holds a value of one of two types, L or R, such as either an inline
+// value or a reference to one.
+// The zero Either holds the zero value of L.
+type Either[L, R any] struct {
+	// left holds the value of type L.
+	left L
+
+	// right holds the value of type R.
+	right R
+
+	// isRight indicates whether the Either holds right rather than left.
+	isRight bool
+}
+
+// Left returns an Either holding the provided value of type L.
+func Left[L, R any](value L) (e Either[L, R]) {
+	return Either[L, R]{
+		left: value,
+	}
+}
+
+// Right returns an Either holding the provided value of type R.
+func Right[L, R any](value R) (e Either[L, R]) {
+	return Either[L, R]{
+		right:   value,
+		isRight: true,
+	}
+}
+
+// IsLeft reports whether the Either holds a value of type L.
+func (e Either[L, R]) IsLeft() (isLeft bool) {
+	return !e.isRight
+}
+
+// IsRight reports whether the Either holds a value of type R.
+func (e Either[L, R]) IsRight() (isRight bool) {
+	return e.isRight
+}
+
+// Left returns the value of type L as an Option.
+// If the Either holds a value of type L, Left returns Some of it.
+// If the Either holds a value of type R, Left returns None.
+func (e Either[L, R]) Left() (o Option[L]) {
+	if e.isRight {
+		return None[L]()
+	}
+
+	return Some(e.left)
+}
+
+// Right returns the value of type R as an Option.
+// If the Either holds a value of type R, Right returns Some of it.
+// If the Either holds a value of type L, Right returns None.
+func (e Either[L, R]) Right() (o Option[R]) {
+	if !e.isRight {
+		return None[R]()
+	}
+
+	return Some(e.right)
+}
+
+// MarshalJSON marshals the value the Either holds, whichever its type.
+func (e Either[L, R]) MarshalJSON() (data []byte, err error) {
+	if e.isRight {
+		return json.Marshal(e.right)
+	}
+
+	return json.Marshal(e.left)
+}
+
+// UnmarshalJSON unmarshals a value of type L if the data decodes as one, and
+// a value of type R otherwise.
+// If the data decodes as neither, UnmarshalJSON returns an error wrapping
+// both decoding errors, joined by errors.Join, and the Either is left
+// unchanged.
+func (e *Either[L, R]) UnmarshalJSON(data []byte) (err error) {
+	var left L
+	leftErr := json.Unmarshal(data, &left)
+	if leftErr == nil {
+		*e = Left[L, R](left)
+		return nil
+	}
+
+	var right R
+	if rightErr := json.Unmarshal(data, &right); rightErr != nil {
+		return fmt.Errorf("opt: cannot unmarshal %.20s into %s or %s: %w", data, reflect.TypeFor[L](), reflect.TypeFor[R](), errors.Join(leftErr, rightErr))
+	}
+
+	*e = Right[L](right)
+	return nil
+}
+
+// String returns a string representation of the value the Either holds.
+func (e Either[L, R]) String() (str string) {
+	if e.isRight {
+		return fmt.Sprint(e.right)
+	}
+
+	return fmt.Sprint(e.left)
+}
+
+// MatchEither returns the result of left or right applied to the value the
+// Either holds, so that both cases are handled in one expression.
+// If the Either holds a value of type L, MatchEither returns left applied to it
+// and right is not called.
+// If the Either holds a value of type R, MatchEither returns right applied to
+// it and left is not called.
+func MatchEither[L, R, T any](e Either[L, R], left func(L) T, right func(R) T) (result T) {
+	if e.isRight {
+		return right(e.right)
+	}
+
+	return left(e.left)
+}
+
+// MapLeft returns the Either with fn applied to its value of type L.
+// If the Either holds a value of type L, MapLeft returns Left of fn applied to
+// it.
+// If the Either holds a value of type R, fn is not called and MapLeft returns
+// Right of the same value.
+func MapLeft[L, R, U any](e Either[L, R], fn func(L) U) (mapped Either[U, R]) {
+	if e.isRight {
+		return Right[U](e.right)
+	}
+
+	return Left[U, R](fn(e.left))
+}
+
+// MapRight returns the Either with fn applied to its value of type R.
+// If the Either holds a value of type R, MapRight returns Right of fn applied
+// to it.
+// If the Either holds a value of type L, fn is not called and MapRight returns
+// Left of the same value.
+func MapRight[L, R, U any](e Either[L, R], fn func(R) U) (mapped Either[L, U]) {
+	if !e.isRight {
+		return Left[L, U](e.left)
+	}
+
+	return Right[L](fn(e.right))
+}
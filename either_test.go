@@ -0,0 +1,74 @@
+package opt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testEitherAuthor struct {
+	Name string `json:"name"`
+}
+
+type testEitherPost struct {
+	Author opt.Either[testEitherAuthor, string] `json:"author"`
+}
+
+func Test_Either(t *testing.T) {
+	for n, e := range map[string]opt.Either[int, string]{
+		"Left":  opt.Left[int, string](42),
+		"Right": opt.Right[int]("forty-two"),
+		"Zero":  {},
+	} {
+		t.Run(n, func(t *testing.T) {
+			left, right := e.Left(), e.Right()
+			snaps.MatchSnapshot(t, e.IsLeft(), e.IsRight(), left.Exists(), left.Unwrap(), right.Exists(), right.Unwrap(), e.String())
+		})
+	}
+}
+
+func Test_MatchEither(t *testing.T) {
+	describe := func(e opt.Either[int, string]) string {
+		return opt.MatchEither(e,
+			func(i int) string { return "number " + strconv.Itoa(i) },
+			func(s string) string { return "word " + s },
+		)
+	}
+
+	snaps.MatchSnapshot(t, describe(opt.Left[int, string](42)), describe(opt.Right[int]("forty-two")))
+}
+
+func Test_MapEither(t *testing.T) {
+	double := func(i int) int { return i * 2 }
+
+	snaps.MatchSnapshot(t,
+		opt.MapLeft(opt.Left[int, string](21), double).String(),
+		opt.MapLeft(opt.Right[int]("word"), double).String(),
+		opt.MapRight(opt.Right[int]("word"), strings.ToUpper).String(),
+		opt.MapRight(opt.Left[int, string](21), strings.ToUpper).String(),
+	)
+}
+
+func Test_EitherJSON(t *testing.T) {
+	for n, data := range map[string]string{
+		"Inline":    `{"author": {"name": "Ada"}}`,
+		"Reference": `{"author": "user-1"}`,
+		"Neither":   `{"author": 42}`,
+	} {
+		t.Run(n, func(t *testing.T) {
+			var post testEitherPost
+
+			err := json.Unmarshal([]byte(data), &post)
+			encoded, _ := json.Marshal(post)
+
+			var typeErr *json.UnmarshalTypeError
+			snaps.MatchSnapshot(t, fmt.Sprint(err), errors.As(err, &typeErr), post.Author.IsRight(), string(encoded))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package opt
+
+import (
+	"bufio"
+	"io"
+	"reflect"
+)
+
+// defaultBufferSize is the size of the buffer an Encoder writes through unless
+// WithBufferSize is given.
+const defaultBufferSize = 32 * 1024
+
+// Encoder writes JSON encodings of values to an output stream.
+// Like Marshal, an Encoder omits struct fields holding an Option that was not
+// provided. Unlike Marshal, values are written to the stream as they are
+// encoded through a fixed size buffer, so the full payload is never held in
+// memory. Slices, arrays and maps are written element by element, and structs
+// holding Options field by field, but any other value is encoded whole by
+// encoding/json before it is written, so the largest such value is held in
+// memory at once.
+type Encoder struct {
+	// w is the destination io.Writer.
+	w io.Writer
+
+	// bufferSize is the size of the buffer used between encoding and w.
+	bufferSize int
+
+	// escapeHTML indicates whether <, > and & are escaped inside JSON strings.
+	escapeHTML bool
+
+	// protoJSON indicates whether values are written following the
+	// conventions of protojson, as by MarshalProtoJSON.
+	protoJSON bool
+}
+
+// EncoderOption configures an Encoder created by NewEncoder.
+type EncoderOption func(e *Encoder)
+
+// WithBufferSize sets the size of the buffer an Encoder writes through.
+// Sizes less than or equal to zero are ignored.
+func WithBufferSize(size int) (option EncoderOption) {
+	return func(e *Encoder) {
+		if size > 0 {
+			e.bufferSize = size
+		}
+	}
+}
+
+// WithEscapeHTML sets whether an Encoder escapes <, > and & inside JSON
+// strings. HTML escaping is on by default, matching Marshal and encoding/json.
+func WithEscapeHTML(on bool) (option EncoderOption) {
+	return func(e *Encoder) {
+		e.escapeHTML = on
+	}
+}
+
+// WithProtoJSON sets whether an Encoder writes values following the
+// conventions of protojson, as MarshalProtoJSON does. HTML escaping is off
+// while it is on, as it is in protojson.
+func WithProtoJSON(on bool) (option EncoderOption) {
+	return func(e *Encoder) {
+		e.protoJSON = on
+	}
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer, opts ...EncoderOption) (e *Encoder) {
+	e = &Encoder{
+		w:          w,
+		bufferSize: defaultBufferSize,
+		escapeHTML: true,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Encode writes the JSON encoding of v to the stream, followed by a newline.
+// Encode checks the type of v the same way Marshal does before anything is
+// written. If an error occurs part way through, the stream holds a partial
+// encoding of v.
+func (e *Encoder) Encode(v any) (err error) {
+	rv := reflect.ValueOf(v)
+
+	if rv.IsValid() {
+		if err = checkType(rv.Type()); err != nil {
+			return err
+		}
+	}
+
+	bw := bufio.NewWriterSize(e.w, e.bufferSize)
+	state := encodeState{w: bw, escapeHTML: e.escapeHTML && !e.protoJSON, protoJSON: e.protoJSON, stream: true}
+
+	if err = state.encodeValue(rv); err != nil {
+		return err
+	}
+
+	if err = bw.WriteByte('\n'); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
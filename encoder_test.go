@@ -0,0 +1,166 @@
+package opt_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// failingWriter accepts limit bytes and then fails every write.
+type failingWriter struct {
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (n int, err error) {
+	if len(p) > w.limit {
+		n = w.limit
+		w.limit = 0
+		return n, errors.New("write failed")
+	}
+
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+// countedValue counts the times it is marshaled in marshalCount.
+type countedValue struct{}
+
+var marshalCount int
+
+func (countedValue) MarshalJSON() (data []byte, err error) {
+	marshalCount++
+	return []byte("0"), nil
+}
+
+func Test_Encoder(t *testing.T) {
+	for n, c := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var payload testPayload
+
+			if err := json.Unmarshal(c.data, &payload); err != nil {
+				t.Fatalf("Unexpected unmarshal error: %s", err)
+			}
+
+			var buf bytes.Buffer
+			if err := opt.NewEncoder(&buf).Encode(payload); err != nil {
+				t.Fatalf("Unexpected encode error: %s", err)
+			}
+
+			marshaled, err := opt.Marshal(payload)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %s", err)
+			}
+
+			if buf.String() != string(marshaled)+"\n" {
+				t.Fatalf("Encode output %q does not match Marshal output %q", buf.String(), marshaled)
+			}
+		})
+	}
+
+	t.Run("Stream", func(t *testing.T) {
+		data := []byte(`[
+			{"primitive": "<a>"},
+			{"slice": [1, 2, 3]},
+			{},
+			{"pointer": null}
+		]`)
+
+		var payloads []testPayload
+		if err := json.Unmarshal(data, &payloads); err != nil {
+			t.Fatalf("Unexpected unmarshal error: %s", err)
+		}
+
+		var buf bytes.Buffer
+		enc := opt.NewEncoder(&buf, opt.WithBufferSize(8), opt.WithEscapeHTML(false))
+
+		if err := enc.Encode(payloads); err != nil {
+			t.Fatalf("Unexpected encode error: %s", err)
+		}
+
+		if err := enc.Encode(payloads[0]); err != nil {
+			t.Fatalf("Unexpected encode error: %s", err)
+		}
+
+		snaps.MatchSnapshot(t, buf.String())
+	})
+
+	t.Run("Write error", func(t *testing.T) {
+		payloads := make([]testPayload, 1000)
+
+		err := opt.NewEncoder(&failingWriter{limit: 64}, opt.WithBufferSize(16)).Encode(payloads)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := opt.NewEncoder(&buf).Encode(opt.Option[func()]{})
+		snaps.MatchSnapshot(t, fmt.Sprint(err), buf.Len())
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		node := &testNode{Name: opt.Some("loop")}
+		node.Next = node
+
+		loop := map[string]any{}
+		loop["loop"] = loop
+
+		nodeErr := opt.NewEncoder(io.Discard).Encode(node)
+		loopErr := opt.NewEncoder(io.Discard).Encode(loop)
+		snaps.MatchSnapshot(t, fmt.Sprint(nodeErr), fmt.Sprint(loopErr))
+	})
+
+	t.Run("Without Options", func(t *testing.T) {
+		values := map[string]any{
+			"Slice":       []int{1, 2, 3},
+			"Nil slice":   []string(nil),
+			"Array":       [2]string{"<a>", "b"},
+			"Bytes":       []byte("bytes"),
+			"Byte array":  [2]byte{1, 2},
+			"Map":         map[string][]int{"b": {2}, "a": nil, "<c>": {}},
+			"Int keys":    map[int]bool{10: true, 2: false},
+			"Text keys":   map[netip.Addr]int{netip.MustParseAddr("10.0.0.2"): 2, netip.MustParseAddr("10.0.0.1"): 1},
+			"Nil map":     map[string]int(nil),
+			"Pointers":    []*int{nil, new(int)},
+			"Interfaces":  []any{1, "<a>", []any{nil}},
+			"Nested":      [][]map[string]string{{{"k": "v"}}, nil},
+			"Struct elem": []struct{ A []int }{{A: []int{1}}},
+		}
+
+		for n, v := range values {
+			for _, escapeHTML := range []bool{true, false} {
+				var want bytes.Buffer
+				enc := json.NewEncoder(&want)
+				enc.SetEscapeHTML(escapeHTML)
+				if err := enc.Encode(v); err != nil {
+					t.Fatalf("Unexpected encoding/json error: %s", err)
+				}
+
+				var got bytes.Buffer
+				if err := opt.NewEncoder(&got, opt.WithEscapeHTML(escapeHTML)).Encode(v); err != nil {
+					t.Fatalf("Unexpected encode error: %s", err)
+				}
+
+				if got.String() != want.String() {
+					t.Fatalf("%s: Encode output %q does not match encoding/json output %q", n, got.String(), want.String())
+				}
+			}
+		}
+	})
+
+	t.Run("Stream without Options", func(t *testing.T) {
+		// Elements are encoded one by one, so a failed write stops the
+		// encoding rather than the whole slice being encoded up front.
+		marshalCount = 0
+
+		err := opt.NewEncoder(&failingWriter{limit: 64}, opt.WithBufferSize(16)).Encode(make([]countedValue, 1000))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), marshalCount)
+	})
+}
@@ -0,0 +1,34 @@
+package opt
+
+import (
+	"encoding/json"
+	"expvar"
+)
+
+// String implements expvar.Var so that an Atomic can be published with
+// expvar.Publish. Unlike Option.String, it returns valid JSON.
+// If the value is provided, String returns it marshaled to JSON.
+// If the value is not provided, or cannot be marshaled, String returns "null".
+func (a *Atomic[T]) String() (str string) {
+	data, err := json.Marshal(a.Load())
+	if err != nil {
+		return string(nullBytes)
+	}
+
+	return string(data)
+}
+
+// PublishAtomic returns a new Atomic holding o and publishes it as an expvar
+// under name, so that a runtime value that may not be known yet, such as the
+// time of the last successful sync, can be exposed at /debug/vars:
+//
+//	lastSync := opt.PublishAtomic("last_sync", opt.None[time.Time]())
+//	...
+//	lastSync.Store(opt.Some(time.Now()))
+//
+// Like expvar.Publish, PublishAtomic panics if name is already published.
+func PublishAtomic[T any](name string, o Option[T]) (a *Atomic[T]) {
+	a = NewAtomic(o)
+	expvar.Publish(name, a)
+	return a
+}
@@ -0,0 +1,37 @@
+package opt_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_Atomic_String(t *testing.T) {
+	for n, v := range map[string]expvar.Var{
+		"Some":     opt.NewAtomic(opt.Some("hello world")),
+		"Some int": opt.NewAtomic(opt.Some(42)),
+		"Time":     opt.NewAtomic(opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))),
+		"None":     opt.NewAtomic(opt.None[string]()),
+		"Zero":     &opt.Atomic[int]{},
+		"Invalid":  opt.NewAtomic(opt.Some(func() {})),
+	} {
+		t.Run(n, func(t *testing.T) {
+			str := v.String()
+			snaps.MatchSnapshot(t, str, json.Valid([]byte(str)))
+		})
+	}
+}
+
+func Test_PublishAtomic(t *testing.T) {
+	a := opt.PublishAtomic("opt_test_last_sync", opt.None[int]())
+	before := expvar.Get("opt_test_last_sync").String()
+
+	a.Store(opt.Some(42))
+	after := expvar.Get("opt_test_last_sync").String()
+
+	snaps.MatchSnapshot(t, before, after)
+}
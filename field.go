@@ -0,0 +1,130 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SetField provides the Option field named name of the struct v points to with
+// value, converted to the type of the Option as by Scan, so that a string read
+// from a file can be set on an Option[int] field. If value is nil, the Option
+// is set to not provided instead.
+// name is the JSON name of the field or its Go name. Fields of nested structs
+// are named by their dotted path, as in "address.city"; pointers to nested
+// structs are allocated as needed.
+func SetField(v any, name string, value any) (err error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("opt: SetField requires a non-nil pointer to a struct, got %T", v)
+	}
+
+	fv, err := findField(rv.Elem(), name, true)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		fv.SetZero()
+		return nil
+	}
+
+	// Convert into a scratch value so that the Option is left unchanged if
+	// the conversion fails.
+	o := reflect.New(fv.Type())
+	if err = assignValue(o.Interface().(optionSetter).provide(), reflect.ValueOf(value)); err != nil {
+		return fmt.Errorf("opt: SetField %s: %w", name, err)
+	}
+
+	fv.Set(o.Elem())
+	return nil
+}
+
+// GetField returns the value of the Option field named name of the struct v,
+// which may also be a pointer to a struct.
+// If the Option is provided, GetField returns its value and present is true.
+// If the Option is not provided, GetField returns nil and present is false.
+// Fields are named as they are by SetField; if a pointer to a nested struct on
+// the way to the field is nil, the field is reported as not provided.
+func GetField(v any, name string) (value any, present bool, err error) {
+	rv := indirectValue(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return nil, false, fmt.Errorf("opt: GetField requires a struct, got %T", v)
+	}
+
+	fv, err := findField(rv, name, false)
+	if err != nil || !fv.IsValid() {
+		return nil, false, err
+	}
+
+	if value, present = fv.Interface().(option).AnyValue(); !present {
+		return nil, false, nil
+	}
+
+	return value, true, nil
+}
+
+// findField returns the Option field of the struct v with the dotted path
+// name. If alloc is true, nil pointers to nested structs are allocated;
+// otherwise findField returns the zero Value when it reaches one.
+func findField(v reflect.Value, name string, alloc bool) (fv reflect.Value, err error) {
+	fv = v
+	for i, part := range strings.Split(name, ".") {
+		if i > 0 {
+			for fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					if !alloc {
+						return reflect.Value{}, nil
+					}
+
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+
+				fv = fv.Elem()
+			}
+		}
+
+		if fv.Kind() != reflect.Struct || isOptionType(fv.Type()) {
+			return reflect.Value{}, fmt.Errorf("opt: no field %s in %s", name, v.Type())
+		}
+
+		f, ok := lookupField(fv.Type(), part)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("opt: no field %s in %s", name, v.Type())
+		}
+
+		if !alloc {
+			if fv, err = fv.FieldByIndexErr(f.index); err != nil {
+				return reflect.Value{}, nil
+			}
+		} else {
+			fv = allocFieldByIndex(fv, f.index)
+		}
+	}
+
+	if !isOptionType(fv.Type()) {
+		return reflect.Value{}, fmt.Errorf("opt: field %s of %s is not an Option", name, v.Type())
+	}
+
+	return fv, nil
+}
+
+// lookupField returns the field of the struct type t with the JSON name or Go
+// name name.
+func lookupField(t reflect.Type, name string) (f field, ok bool) {
+	fields := cachedFields(t)
+
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+
+	for _, f := range fields {
+		if t.FieldByIndex(f.index).Name == name {
+			return f, true
+		}
+	}
+
+	return field{}, false
+}
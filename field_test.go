@@ -0,0 +1,74 @@
+package opt_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testField struct {
+	ID       int                    `json:"id"`
+	Email    opt.Option[string]     `json:"email"`
+	Age      opt.Option[int]        `json:"age"`
+	Joined   opt.Option[time.Time]  `json:"joined"`
+	Address  testApplyAddressPatch  `json:"address"`
+	Shipping *testApplyAddressPatch `json:"shipping"`
+}
+
+func Test_SetField(t *testing.T) {
+	var v testField
+
+	errs := []error{
+		opt.SetField(&v, "email", "x@y.z"),
+		opt.SetField(&v, "Age", "36"),
+		opt.SetField(&v, "joined", "2024-01-02T03:04:05Z"),
+		opt.SetField(&v, "address.city", "London"),
+		opt.SetField(&v, "shipping.postcode", []byte("NW1")),
+	}
+
+	t.Run("Set", func(t *testing.T) {
+		data, _ := opt.Marshal(v)
+		snaps.MatchSnapshot(t, fmt.Sprint(errs), string(data))
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		err := opt.SetField(&v, "email", nil)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), v.Email.Exists())
+	})
+
+	t.Run("Conversion error", func(t *testing.T) {
+		err := opt.SetField(&v, "age", "old")
+		snaps.MatchSnapshot(t, fmt.Sprint(err), v.Age.Unwrap())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		snaps.MatchSnapshot(t,
+			fmt.Sprint(opt.SetField(&v, "missing", 1)),
+			fmt.Sprint(opt.SetField(&v, "id", 1)),
+			fmt.Sprint(opt.SetField(&v, "email.domain", 1)),
+			fmt.Sprint(opt.SetField(v, "email", "x@y.z")),
+		)
+	})
+}
+
+func Test_GetField(t *testing.T) {
+	v := testField{
+		Email:   opt.Some("x@y.z"),
+		Address: testApplyAddressPatch{City: opt.Some("London")},
+	}
+
+	for _, name := range []string{"email", "Email", "age", "address.city", "shipping.city", "missing", "id"} {
+		t.Run(name, func(t *testing.T) {
+			value, present, err := opt.GetField(&v, name)
+			snaps.MatchSnapshot(t, value, present, fmt.Sprint(err))
+		})
+	}
+
+	t.Run("Not a struct", func(t *testing.T) {
+		_, _, err := opt.GetField("hello world", "email")
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+}
@@ -0,0 +1,54 @@
+package opt
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// ParseFlag returns Some of the command-line flag value s parsed into T, for
+// flag packages that bind Options.
+// Durations are parsed by time.ParseDuration, and slices other than byte
+// slices are split on commas with each element parsed on its own. Other
+// values are converted as DecodeHook converts strings.
+func ParseFlag[T any](s string) (o Option[T], err error) {
+	if err = parseFlag(o.provide(), s); err != nil {
+		return None[T](), err
+	}
+
+	return o, nil
+}
+
+// parseFlag does the work for ParseFlag, storing s parsed into dst.
+func parseFlag(dst reflect.Value, s string) (err error) {
+	dt := dst.Type()
+
+	switch {
+	case dt == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		dst.SetInt(int64(d))
+		return nil
+	case dt.Kind() == reflect.Slice && dt.Elem().Kind() != reflect.Uint8 && !reflect.PointerTo(dt).Implements(textUnmarshalerType):
+		elems := []string{}
+		if s != "" {
+			elems = strings.Split(s, ",")
+		}
+
+		dst.Set(reflect.MakeSlice(dt, len(elems), len(elems)))
+		for i, elem := range elems {
+			if err = parseFlag(dst.Index(i), elem); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return assignValue(dst, reflect.ValueOf(s))
+}
@@ -0,0 +1,35 @@
+package opt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_ParseFlag(t *testing.T) {
+	t.Run("Values", func(t *testing.T) {
+		port, portErr := opt.ParseFlag[int]("8080")
+		timeout, timeoutErr := opt.ParseFlag[time.Duration]("1m30s")
+		backoff, backoffErr := opt.ParseFlag[[]time.Duration]("1s,2s")
+		ids, idsErr := opt.ParseFlag[[]int]("1,2")
+		tags, tagsErr := opt.ParseFlag[[]string]("")
+		started, startedErr := opt.ParseFlag[time.Time]("2024-01-02T03:04:05Z")
+
+		snaps.MatchSnapshot(t,
+			port, portErr,
+			timeout, timeoutErr,
+			backoff, backoffErr,
+			ids, idsErr,
+			tags, tagsErr,
+			started, startedErr,
+		)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		port, portErr := opt.ParseFlag[int]("eighty")
+		backoff, backoffErr := opt.ParseFlag[[]time.Duration]("1s,soon")
+		snaps.MatchSnapshot(t, port, portErr.Error(), backoff, backoffErr.Error())
+	})
+}
@@ -0,0 +1,47 @@
+package opt
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Format implements fmt.Formatter.
+// If the value is provided, the verbs %v and %q, and any other verb along
+// with its flags, width and precision, format the value itself, so %q quotes
+// a string value. %+v writes the value as by %+v wrapped in "Some(...)", and
+// %#v writes the same as GoString.
+// If the value is not provided, %+v writes "None", %#v writes the same as
+// GoString, and every other verb writes "<empty>", as String does.
+func (o Option[T]) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		io.WriteString(f, o.GoString())
+	case verb == 'v' && f.Flag('+'):
+		if !o.exists {
+			io.WriteString(f, "None")
+			return
+		}
+
+		fmt.Fprintf(f, "Some(%+v)", o.value)
+	case !o.exists:
+		io.WriteString(f, "<empty>")
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), o.value)
+	}
+}
+
+// GoString implements fmt.GoStringer, returning the Go expression that builds
+// the Option, so that test failures and debugger output show both its
+// presence and its value and can be pasted back into code.
+// If the value is provided, GoString returns an expression such as
+// opt.Some("hello"), holding the value as formatted by %#v.
+// If the value is not provided, GoString returns an expression such as
+// opt.None[string]().
+func (o Option[T]) GoString() (str string) {
+	if !o.exists {
+		return fmt.Sprintf("opt.None[%s]()", reflect.TypeFor[T]())
+	}
+
+	return fmt.Sprintf("opt.Some(%#v)", o.value)
+}
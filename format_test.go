@@ -0,0 +1,43 @@
+package opt_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_Format(t *testing.T) {
+	formats := []string{"%v", "%+v", "%#v", "%s", "%q", "%6.2f", "%x", "%d"}
+
+	for n, o := range map[string]any{
+		"String":      opt.Some("hello"),
+		"Float":       opt.Some(3.14159),
+		"Int":         opt.Some(255),
+		"Struct":      opt.Some(testStruct{Make: "Audi", Model: "A5"}),
+		"Pointer":     opt.Some[*int](nil),
+		"None":        opt.None[string](),
+		"None struct": opt.None[testStruct](),
+		"Nested":      opt.Some(opt.Some("hello")),
+	} {
+		t.Run(n, func(t *testing.T) {
+			results := make([]string, len(formats))
+			for i, format := range formats {
+				results[i] = format + " " + fmt.Sprintf(format, o)
+			}
+
+			snaps.MatchSnapshot(t, results)
+		})
+	}
+}
+
+func Test_GoString(t *testing.T) {
+	snaps.MatchSnapshot(t,
+		opt.Some("hello").GoString(),
+		opt.Some(testStruct{Make: "Audi"}).GoString(),
+		opt.Some(opt.None[int]()).GoString(),
+		opt.None[[]string]().GoString(),
+		opt.None[map[string]any]().GoString(),
+	)
+}
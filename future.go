@@ -0,0 +1,72 @@
+package opt
+
+import (
+	"context"
+	"sync"
+)
+
+// Future is an Option whose value arrives later, or never, from another
+// goroutine. It starts unresolved and can be resolved exactly once.
+// A Future must be created with NewFuture and must not be copied.
+type Future[T any] struct {
+	// once guards the resolution.
+	once sync.Once
+
+	// done is closed once the Future is resolved.
+	done chan struct{}
+
+	// result holds the Option the Future was resolved with. It must only be
+	// read once done is closed.
+	result Option[T]
+}
+
+// NewFuture returns an unresolved Future.
+func NewFuture[T any]() (f *Future[T]) {
+	return &Future[T]{
+		done: make(chan struct{}),
+	}
+}
+
+// Resolve resolves the Future with o, waking every goroutine awaiting it. A
+// Future resolved with None records that its value will never arrive.
+// Resolve reports whether it resolved the Future; if the Future was already
+// resolved, it is left unchanged and Resolve returns false.
+func (f *Future[T]) Resolve(o Option[T]) (resolved bool) {
+	f.once.Do(func() {
+		f.result = o
+		close(f.done)
+		resolved = true
+	})
+
+	return resolved
+}
+
+// Await waits for the Future to be resolved and returns the Option it was
+// resolved with.
+// If ctx is done before the Future is resolved, Await returns None. Use
+// ctx.Err to tell a cancelled wait apart from a Future resolved with None.
+func (f *Future[T]) Await(ctx context.Context) (o Option[T]) {
+	select {
+	case <-f.done:
+		return f.result
+	case <-ctx.Done():
+		return None[T]()
+	}
+}
+
+// Done returns a channel that is closed once the Future is resolved, for use
+// in select statements.
+func (f *Future[T]) Done() (done <-chan struct{}) {
+	return f.done
+}
+
+// Option returns the Option the Future was resolved with, without waiting.
+// If the Future is not resolved yet, Option returns None.
+func (f *Future[T]) Option() (o Option[T]) {
+	select {
+	case <-f.done:
+		return f.result
+	default:
+		return None[T]()
+	}
+}
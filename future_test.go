@@ -0,0 +1,54 @@
+package opt_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_Future(t *testing.T) {
+	t.Run("Resolved later", func(t *testing.T) {
+		f := opt.NewFuture[string]()
+		before := f.Option()
+
+		go f.Resolve(opt.Some("hello world"))
+
+		o := f.Await(context.Background())
+		<-f.Done()
+		snaps.MatchSnapshot(t, before.Exists(), o.Exists(), o.Unwrap(), f.Option().Unwrap())
+	})
+
+	t.Run("Resolved once", func(t *testing.T) {
+		f := opt.NewFuture[int]()
+
+		resolved := []bool{
+			f.Resolve(opt.Some(1)),
+			f.Resolve(opt.Some(2)),
+			f.Resolve(opt.None[int]()),
+		}
+
+		snaps.MatchSnapshot(t, resolved, f.Await(context.Background()).Unwrap())
+	})
+
+	t.Run("Never", func(t *testing.T) {
+		f := opt.NewFuture[int]()
+		f.Resolve(opt.None[int]())
+
+		ctx := context.Background()
+		o := f.Await(ctx)
+		snaps.MatchSnapshot(t, o.Exists(), ctx.Err())
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		f := opt.NewFuture[int]()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		o := f.Await(ctx)
+		snaps.MatchSnapshot(t, o.Exists(), ctx.Err().Error())
+	})
+}
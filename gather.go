@@ -0,0 +1,57 @@
+package opt
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Gather runs funcs concurrently and returns their results in the order the
+// funcs were given.
+// At most runtime.GOMAXPROCS(0) funcs run at once; use GatherLimit to choose
+// the bound.
+// See GatherLimit for how cancellation of ctx is handled.
+func Gather[T any](ctx context.Context, funcs ...func(ctx context.Context) Option[T]) (results []Option[T]) {
+	return GatherLimit(ctx, runtime.GOMAXPROCS(0), funcs...)
+}
+
+// GatherLimit runs funcs concurrently, with at most limit running at once, and
+// returns their results in the order the funcs were given.
+// A limit less than or equal to zero runs every func at once.
+// Each func is passed ctx. Once ctx is done, funcs that have not yet started
+// are skipped and their results are not provided. GatherLimit always waits for
+// the funcs that did start to return.
+func GatherLimit[T any](ctx context.Context, limit int, funcs ...func(ctx context.Context) Option[T]) (results []Option[T]) {
+	results = make([]Option[T], len(funcs))
+
+	if limit <= 0 || limit > len(funcs) {
+		limit = len(funcs)
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i, fn := range funcs {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			results[i] = fn(ctx)
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,96 @@
+package opt_test
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// lookup returns a func that sleeps for delay and then provides value, unless
+// value is negative.
+func lookup(value int, delay time.Duration) func(ctx context.Context) opt.Option[int] {
+	return func(ctx context.Context) opt.Option[int] {
+		time.Sleep(delay)
+
+		if value < 0 {
+			return opt.None[int]()
+		}
+
+		return opt.Some(value)
+	}
+}
+
+func Test_Gather(t *testing.T) {
+	t.Run("Positional", func(t *testing.T) {
+		results := opt.Gather(context.Background(),
+			lookup(1, 30*time.Millisecond),
+			lookup(-1, 0),
+			lookup(3, 10*time.Millisecond),
+			lookup(4, 0),
+		)
+
+		snaps.MatchSnapshot(t, results)
+	})
+
+	t.Run("No funcs", func(t *testing.T) {
+		results := opt.Gather[int](context.Background())
+		snaps.MatchSnapshot(t, len(results))
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+
+		funcs := make([]func(ctx context.Context) opt.Option[string], 10)
+		for i := range funcs {
+			funcs[i] = func(ctx context.Context) opt.Option[string] {
+				n := running.Add(1)
+				defer running.Add(-1)
+
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+
+				time.Sleep(5 * time.Millisecond)
+				return opt.Some(strconv.Itoa(i))
+			}
+		}
+
+		results := opt.GatherLimit(context.Background(), 3, funcs...)
+		snaps.MatchSnapshot(t, results, maxRunning.Load() <= 3)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var started atomic.Int32
+		funcs := make([]func(ctx context.Context) opt.Option[int], 5)
+		for i := range funcs {
+			funcs[i] = func(ctx context.Context) opt.Option[int] {
+				started.Add(1)
+				cancel()
+				<-ctx.Done()
+				return opt.Some(i)
+			}
+		}
+
+		results := opt.GatherLimit(ctx, 1, funcs...)
+		snaps.MatchSnapshot(t, results, started.Load())
+	})
+
+	t.Run("Cancelled before start", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results := opt.Gather(ctx, lookup(1, 0), lookup(2, 0))
+		snaps.MatchSnapshot(t, results)
+	})
+}
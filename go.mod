@@ -2,7 +2,14 @@ module github.com/fletcharoo/opt
 
 go 1.23.2
 
-require github.com/gkampitakis/go-snaps v0.5.7
+require (
+	github.com/gkampitakis/go-snaps v0.5.7
+	github.com/go-viper/mapstructure/v2 v2.5.0
+	github.com/gocarina/gocsv v0.0.0-20260926200228-b2c6eb8fefab
+	github.com/google/go-querystring v1.2.0
+	github.com/graph-gophers/graphql-go v1.7.2
+	google.golang.org/protobuf v1.36.12
+)
 
 require (
 	github.com/gkampitakis/ciinfo v0.3.0 // indirect
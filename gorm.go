@@ -0,0 +1,50 @@
+package opt
+
+import (
+	"reflect"
+	"time"
+)
+
+// gormDataTyper is the GORM schema.GormDataTypeInterface interface.
+type gormDataTyper interface {
+	GormDataType() string
+}
+
+// GormDataType implements the GORM schema.GormDataTypeInterface interface so
+// that GORM migrates an Option field to a nullable column of the type it
+// would use for T.
+// If T implements the interface itself, its data type is returned. Otherwise
+// GormDataType returns one of GORM's general data types: "bool", "int",
+// "uint", "float", "string", "time" or "bytes", falling back to "string" for
+// values stored through a serializer.
+func (o Option[T]) GormDataType() (dataType string) {
+	if dt, ok := any(&o.value).(gormDataTyper); ok {
+		return dt.GormDataType()
+	}
+
+	t := reflect.TypeFor[T]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytes"
+		}
+	case reflect.Struct:
+		if t.ConvertibleTo(reflect.TypeFor[time.Time]()) {
+			return "time"
+		}
+	}
+
+	return "string"
+}
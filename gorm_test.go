@@ -0,0 +1,30 @@
+package opt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// testGormJSON reports its own GORM data type.
+type testGormJSON map[string]any
+
+func (testGormJSON) GormDataType() string { return "json" }
+
+func Test_GormDataType(t *testing.T) {
+	snaps.MatchSnapshot(t, map[string]string{
+		"bool":       opt.Option[bool]{}.GormDataType(),
+		"int":        opt.Option[int32]{}.GormDataType(),
+		"uint":       opt.Option[uint]{}.GormDataType(),
+		"float":      opt.Option[float64]{}.GormDataType(),
+		"string":     opt.Option[string]{}.GormDataType(),
+		"named":      opt.Option[testStatus]{}.GormDataType(),
+		"pointer":    opt.Option[*int]{}.GormDataType(),
+		"bytes":      opt.Option[[]byte]{}.GormDataType(),
+		"time":       opt.Option[time.Time]{}.GormDataType(),
+		"slice":      opt.Option[[]string]{}.GormDataType(),
+		"data typer": opt.Option[testGormJSON]{}.GormDataType(),
+	})
+}
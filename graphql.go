@@ -0,0 +1,79 @@
+package opt
+
+import (
+	"reflect"
+	"time"
+)
+
+// graphQLUnmarshaler is the decode.Unmarshaler interface of
+// github.com/graph-gophers/graphql-go, implemented by custom scalar types.
+type graphQLUnmarshaler interface {
+	ImplementsGraphQLType(name string) bool
+	UnmarshalGraphQL(input any) error
+}
+
+// ImplementsGraphQLType implements the decode.Unmarshaler interface of
+// github.com/graph-gophers/graphql-go, reporting whether an Option can be
+// given for an argument or input field of the GraphQL scalar type name.
+// If T is a custom scalar type, ImplementsGraphQLType defers to T.
+// Otherwise strings implement String and ID, integers Int, floats Float,
+// booleans Boolean and time.Time implements Time.
+func (o Option[T]) ImplementsGraphQLType(name string) (ok bool) {
+	if u, isUnmarshaler := any(new(T)).(graphQLUnmarshaler); isUnmarshaler {
+		return u.ImplementsGraphQLType(name)
+	}
+
+	t := reflect.TypeFor[T]()
+	if t == reflect.TypeFor[time.Time]() {
+		return name == "Time"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return name == "String" || name == "ID"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return name == "Int"
+	case reflect.Float32, reflect.Float64:
+		return name == "Float"
+	case reflect.Bool:
+		return name == "Boolean"
+	}
+
+	return false
+}
+
+// UnmarshalGraphQL implements the decode.Unmarshaler interface of
+// github.com/graph-gophers/graphql-go so that resolvers can take Options for
+// nullable arguments and input fields.
+// If input is nil, which graphql-go passes for both null and missing values,
+// UnmarshalGraphQL sets o to None.
+// Otherwise UnmarshalGraphQL converts input to T, deferring to T if it is a
+// custom scalar type, and sets exists to true.
+// If input cannot be converted, UnmarshalGraphQL returns an error and leaves o
+// unchanged.
+func (o *Option[T]) UnmarshalGraphQL(input any) (err error) {
+	if input == nil {
+		*o = Option[T]{}
+		return nil
+	}
+
+	var value T
+	if u, ok := any(&value).(graphQLUnmarshaler); ok {
+		err = u.UnmarshalGraphQL(input)
+	} else {
+		err = assignValue(reflect.ValueOf(&value).Elem(), reflect.ValueOf(input))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	o.value = value
+	o.exists = true
+	return nil
+}
+
+// Nullable marks an Option as accepting null, which lets graphql-go give it
+// for nullable arguments and input fields without a pointer.
+func (o *Option[T]) Nullable() {}
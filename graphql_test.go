@@ -0,0 +1,65 @@
+package opt_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const testGraphQLSchema = `
+	type Query {
+		users(name: String, limit: Int, active: Boolean, after: ID): String!
+	}
+`
+
+type testGraphQLResolver struct{}
+
+type testGraphQLArgs struct {
+	Name   opt.Option[string]
+	Limit  opt.Option[int32]
+	Active opt.Option[bool]
+	After  opt.Option[graphql.ID]
+}
+
+func (*testGraphQLResolver) Users(args testGraphQLArgs) (s string) {
+	data, _ := json.Marshal(args)
+	return string(data)
+}
+
+func Test_Option_UnmarshalGraphQL(t *testing.T) {
+	schema := graphql.MustParseSchema(testGraphQLSchema, &testGraphQLResolver{})
+
+	testCases := map[string]string{
+		"Missing":     `{ users }`,
+		"Null":        `{ users(name: null, limit: null) }`,
+		"Zero values": `{ users(name: "", limit: 0, active: false) }`,
+		"Values":      `{ users(name: "ada", limit: 10, active: true, after: "42") }`,
+	}
+
+	for n, query := range testCases {
+		t.Run(n, func(t *testing.T) {
+			resp := schema.Exec(context.Background(), query, "", nil)
+			snaps.MatchSnapshot(t, string(resp.Data), resp.Errors)
+		})
+	}
+
+	t.Run("Variables", func(t *testing.T) {
+		query := `query ($limit: Int) { users(limit: $limit) }`
+		resp := schema.Exec(context.Background(), query, "", map[string]any{"limit": 5})
+		snaps.MatchSnapshot(t, string(resp.Data), resp.Errors)
+	})
+}
+
+func Test_Option_ImplementsGraphQLType(t *testing.T) {
+	snaps.MatchSnapshot(t,
+		opt.Option[string]{}.ImplementsGraphQLType("ID"),
+		opt.Option[int]{}.ImplementsGraphQLType("Int"),
+		opt.Option[int]{}.ImplementsGraphQLType("String"),
+		opt.Option[graphql.Time]{}.ImplementsGraphQLType("Time"),
+		opt.Option[[]string]{}.ImplementsGraphQLType("String"),
+	)
+}
@@ -0,0 +1,38 @@
+package opt
+
+import (
+	"net/http"
+)
+
+// FromHeader returns an Option holding the first value of the header key in h.
+// The key is canonicalized as it is by http.Header.Get.
+// If h holds the header, FromHeader returns Some of its first value, even if it
+// is empty.
+// If h does not hold the header, or h is nil, FromHeader returns None.
+func FromHeader(h http.Header, key string) (o Option[string]) {
+	values := h[http.CanonicalHeaderKey(key)]
+	if len(values) == 0 {
+		return None[string]()
+	}
+
+	return Some(values[0])
+}
+
+// DecodeHeaders decodes the headers h into the struct target points to. Each
+// exported field is decoded from the header named by its header tag, or by the
+// field name if it has none, canonicalized as it is by http.Header.Get. Fields
+// tagged "-" are skipped, and the fields of untagged embedded structs are
+// promoted.
+// An Option field is provided if its header is present, even if its value is
+// empty, and is left untouched if it is absent. Other fields are set only if
+// their header is present.
+// Values are converted as they are by DecodeQuery, so slices receive every
+// value of their header, and other types the first.
+// If a value cannot be converted, DecodeHeaders returns an error naming the
+// header, and the fields decoded before it are left in place.
+func DecodeHeaders(h http.Header, target any) (err error) {
+	return decodeStrings("DecodeHeaders", target, "header", func(name string) (found []string, ok bool) {
+		found, ok = h[http.CanonicalHeaderKey(name)]
+		return found, ok
+	})
+}
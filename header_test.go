@@ -0,0 +1,79 @@
+package opt_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_FromHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Request-Id", "abc")
+	h.Set("X-Empty", "")
+
+	testCases := map[string]struct {
+		h   http.Header
+		key string
+	}{
+		"Present":       {h: h, key: "X-Request-Id"},
+		"Canonicalized": {h: h, key: "x-request-id"},
+		"Empty":         {h: h, key: "X-Empty"},
+		"Missing":       {h: h, key: "X-Missing"},
+		"Nil header":    {h: nil, key: "X-Request-Id"},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, opt.FromHeader(tc.h, tc.key))
+		})
+	}
+}
+
+type testHeaders struct {
+	RequestID opt.Option[string]    `header:"x-request-id"`
+	Retries   opt.Option[int]       `header:"X-Retries"`
+	Deadline  opt.Option[time.Time] `header:"X-Deadline"`
+	Accept    opt.Option[[]string]  `header:"Accept"`
+	Trace     opt.Option[string]    `header:"X-Trace"`
+	Tenant    string                `header:"X-Tenant"`
+	Ignored   opt.Option[string]    `header:"-"`
+}
+
+func Test_DecodeHeaders(t *testing.T) {
+	testCases := map[string]map[string][]string{
+		"Empty": {},
+		"Present": {
+			"X-Request-Id": {"abc"},
+			"X-Retries":    {"0"},
+			"X-Deadline":   {"2024-01-02T03:04:05Z"},
+			"Accept":       {"text/html", "application/json"},
+			"X-Trace":      {""},
+			"X-Tenant":     {"acme"},
+			"Ignored":      {"x"},
+		},
+		"Invalid": {"X-Request-Id": {"abc"}, "X-Retries": {"three"}},
+	}
+
+	for n, values := range testCases {
+		t.Run(n, func(t *testing.T) {
+			h := http.Header{}
+			for key, vs := range values {
+				for _, v := range vs {
+					h.Add(key, v)
+				}
+			}
+
+			var headers testHeaders
+			err := opt.DecodeHeaders(h, &headers)
+			snaps.MatchSnapshot(t, headers, err)
+		})
+	}
+
+	t.Run("Not a pointer", func(t *testing.T) {
+		err := opt.DecodeHeaders(http.Header{}, testHeaders{})
+		snaps.MatchSnapshot(t, err)
+	})
+}
@@ -0,0 +1,175 @@
+// Package logwalk walks the values logged by the logging integrations of opt,
+// naming the fields of structs by their JSON names and stopping at pointer
+// cycles. Walk, Presence and MarshalCompact stop at pointer cycles with Seen
+// as well.
+package logwalk
+
+import (
+	"iter"
+	"reflect"
+	"strings"
+)
+
+// Walker decides which values are logged as structs of their fields.
+type Walker struct {
+	// IsOption reports whether t is an Option type. Options are never walked
+	// as structs, and embedded Options are not promoted.
+	IsOption func(t reflect.Type) (ok bool)
+
+	// Leaves holds the types whose values are logged as they are.
+	Leaves []reflect.Type
+
+	// Interfaces holds the interfaces implemented by the types whose values
+	// choose their own representation in logs.
+	Interfaces []reflect.Type
+
+	// Addressable makes a type choose its own representation when only
+	// pointers to it implement one of Interfaces.
+	Addressable bool
+}
+
+// DescribesItself reports whether values of t choose their own representation
+// in logs.
+func (w Walker) DescribesItself(t reflect.Type) (ok bool) {
+	for _, leaf := range w.Leaves {
+		if t == leaf {
+			return true
+		}
+	}
+
+	for _, iface := range w.Interfaces {
+		if t.Implements(iface) || (w.Addressable && reflect.PointerTo(t).Implements(iface)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Struct returns the value v holds through pointers and interfaces, which is
+// the zero Value if any of them is nil, and reports whether it is a struct to
+// be logged as its fields: one that is not an Option and, like v, does not
+// describe itself.
+func (w Walker) Struct(v reflect.Value) (rv reflect.Value, ok bool) {
+	for rv = v; rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface; rv = rv.Elem() {
+		if rv.IsNil() {
+			return reflect.Value{}, false
+		}
+	}
+
+	if !rv.IsValid() || rv.Kind() != reflect.Struct || w.IsOption(rv.Type()) {
+		return rv, false
+	}
+
+	return rv, !w.DescribesItself(v.Type()) && !w.DescribesItself(rv.Type())
+}
+
+// Fields returns the JSON names and values of the exported fields of the
+// struct v, promoting the fields of embedded structs as encoding/json does.
+func (w Walker) Fields(v reflect.Value) (fields iter.Seq2[string, reflect.Value]) {
+	return func(yield func(string, reflect.Value) bool) {
+		w.fields(v, yield)
+	}
+}
+
+// fields yields the fields of the struct v, reporting false once yield does.
+func (w Walker) fields(v reflect.Value, yield func(string, reflect.Value) bool) (more bool) {
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+
+		name, tagged := JSONName(sf)
+		if name == "-" {
+			continue
+		}
+
+		if sf.Anonymous && !tagged && sf.Type.Kind() == reflect.Struct && !w.IsOption(sf.Type) {
+			if !w.fields(v.Field(i), yield) {
+				return false
+			}
+
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if !yield(name, v.Field(i)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// JSONName returns the JSON name of sf and whether it is set by a tag.
+func JSONName(sf reflect.StructField) (name string, tagged bool) {
+	tag, ok := sf.Tag.Lookup("json")
+	if !ok {
+		return sf.Name, false
+	}
+
+	if name, _, _ = strings.Cut(tag, ","); name == "" {
+		return sf.Name, false
+	}
+
+	return name, true
+}
+
+// JoinKey returns name keyed under prefix with a dot.
+func JoinKey(prefix, name string) (key string) {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
+
+// Cycle is logged in place of a struct reached again through a pointer cycle.
+const Cycle = "<cycle>"
+
+// Seen holds the structs being walked that were reached through pointers, so
+// that a pointer cycle is not followed forever. A nil Seen contains nothing
+// and must be made before entering a struct.
+type Seen map[visit]bool
+
+// visit identifies a struct by its address and type, since a struct and its
+// first field share an address.
+type visit struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// Contains reports whether the struct v is being walked, in which case v was
+// reached again through a pointer cycle and must not be walked.
+func (s Seen) Contains(v reflect.Value) (ok bool) {
+	key, ok := visitOf(v)
+	return ok && s[key]
+}
+
+// Enter marks the struct v as being walked. Leave must be called once it is.
+func (s Seen) Enter(v reflect.Value) {
+	if key, ok := visitOf(v); ok {
+		s[key] = true
+	}
+}
+
+// Leave marks the struct v as walked, so that it is walked again if it is
+// reached through another path that is not a cycle.
+func (s Seen) Leave(v reflect.Value) {
+	if key, ok := visitOf(v); ok {
+		delete(s, key)
+	}
+}
+
+// visitOf returns the visit identifying v, and false if v is not addressable.
+// A struct that is not addressable was not reached through a pointer, so it
+// cannot be part of a cycle.
+func visitOf(v reflect.Value) (key visit, ok bool) {
+	if !v.CanAddr() {
+		return visit{}, false
+	}
+
+	return visit{addr: v.Addr().Pointer(), typ: v.Type()}, true
+}
@@ -0,0 +1,104 @@
+package logwalk_test
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/internal/logwalk"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+var walker = logwalk.Walker{
+	IsOption:   opt.IsOptionType,
+	Leaves:     []reflect.Type{reflect.TypeFor[time.Time]()},
+	Interfaces: []reflect.Type{reflect.TypeFor[fmt.Stringer]()},
+}
+
+type testBase struct {
+	ID int `json:"id"`
+}
+
+type testStringer struct{}
+
+func (testStringer) String() (s string) {
+	return "stringer"
+}
+
+type testUser struct {
+	testBase
+	Name     string             `json:"name"`
+	Nickname opt.Option[string] `json:"nickname,omitempty"`
+	Address  struct{}
+	Password string `json:"-"`
+	internal string
+}
+
+func Test_Fields(t *testing.T) {
+	var names []string
+	for name, fv := range walker.Fields(reflect.ValueOf(testUser{Name: "Ada"})) {
+		names = append(names, fmt.Sprintf("%s %s", name, fv.Type()))
+	}
+
+	snaps.MatchSnapshot(t, names)
+}
+
+func Test_Struct(t *testing.T) {
+	user := &testUser{}
+	var nilUser *testUser
+
+	testCases := map[string]any{
+		"Struct":      testUser{},
+		"Pointer":     &user,
+		"Nil pointer": nilUser,
+		"Interface":   []any{user},
+		"Option":      opt.Some(1),
+		"Leaf":        time.Time{},
+		"Stringer":    testStringer{},
+		"Not struct":  42,
+	}
+
+	for n, v := range testCases {
+		t.Run(n, func(t *testing.T) {
+			rv := reflect.ValueOf(v)
+			if rv.Kind() == reflect.Slice {
+				rv = rv.Index(0)
+			}
+
+			rv, ok := walker.Struct(rv)
+			snaps.MatchSnapshot(t, rv.IsValid(), ok)
+		})
+	}
+}
+
+func Test_Seen(t *testing.T) {
+	user := &testUser{}
+	rv := reflect.ValueOf(user).Elem()
+	seen := logwalk.Seen{}
+
+	before := seen.Contains(rv)
+	seen.Enter(rv)
+	entered := seen.Contains(rv)
+	base := seen.Contains(rv.Field(0))
+	seen.Leave(rv)
+	left := seen.Contains(rv)
+
+	// A struct that is not addressable cannot be reached again.
+	seen.Enter(reflect.ValueOf(*user))
+	copied := seen.Contains(reflect.ValueOf(*user))
+
+	snaps.MatchSnapshot(t, before, entered, base, left, copied)
+}
+
+func Test_JoinKey(t *testing.T) {
+	snaps.MatchSnapshot(t, logwalk.JoinKey("", "name"), logwalk.JoinKey("user", "name"))
+}
@@ -0,0 +1,27 @@
+package opt
+
+import "iter"
+
+// All returns an iterator over the value, so that an Option can be ranged
+// over or handed to code built on iterators.
+// If the value is provided, the iterator yields it once.
+// If the value is not provided, the iterator yields nothing.
+func (o Option[T]) All() (seq iter.Seq[T]) {
+	return func(yield func(T) bool) {
+		if o.exists {
+			yield(o.value)
+		}
+	}
+}
+
+// FromSeq returns the first value yielded by seq, which is not iterated
+// beyond it.
+// If seq yields a value, FromSeq returns Some of the first one.
+// If seq yields nothing, FromSeq returns None.
+func FromSeq[T any](seq iter.Seq[T]) (o Option[T]) {
+	for v := range seq {
+		return Some(v)
+	}
+
+	return None[T]()
+}
@@ -0,0 +1,54 @@
+package opt_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_All(t *testing.T) {
+	t.Run("Some", func(t *testing.T) {
+		snaps.MatchSnapshot(t, slices.Collect(opt.Some("hello world").All()))
+	})
+
+	t.Run("None", func(t *testing.T) {
+		snaps.MatchSnapshot(t, slices.Collect(opt.None[string]().All()))
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		var values []int
+		for v := range opt.Some(42).All() {
+			values = append(values, v)
+		}
+
+		snaps.MatchSnapshot(t, values)
+	})
+}
+
+func Test_FromSeq(t *testing.T) {
+	t.Run("First", func(t *testing.T) {
+		yielded := 0
+		o := opt.FromSeq(func(yield func(int) bool) {
+			for i := 1; i <= 3; i++ {
+				yielded++
+				if !yield(i) {
+					return
+				}
+			}
+		})
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap(), yielded)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		o := opt.FromSeq(maps.Keys(map[string]int{}))
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		o := opt.FromSeq(opt.Some("hello world").All())
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+}
@@ -0,0 +1,212 @@
+package opt
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// PatchOperation is a single operation of a JSON Patch (RFC 6902).
+type PatchOperation struct {
+	// Op is the operation: "add", "remove" or "replace".
+	Op string `json:"op"`
+
+	// Path is the JSON Pointer (RFC 6901) of the member the operation
+	// applies to.
+	Path string `json:"path"`
+
+	// Value is the JSON encoding of the new value of the member. It is nil
+	// for "remove".
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch returns the JSON Patch (RFC 6902) operations turning the JSON
+// encoding of from into that of to, both encoded with Marshal.
+// Fields holding an Option that was not provided are missing from the
+// encoding, so a field provided in from but not in to is removed. Objects are
+// compared member by member, in sorted order, whereas arrays and other values
+// are replaced as a whole when they differ.
+func JSONPatch(from, to any) (ops []PatchOperation, err error) {
+	a, err := decodeDocument(from)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := decodeDocument(to)
+	if err != nil {
+		return nil, err
+	}
+
+	ops = []PatchOperation{}
+	return ops, diffDocuments(&ops, "", a, b)
+}
+
+// JSONPatchFromMerge returns the JSON Patch (RFC 6902) operations with the
+// same effect on the JSON encoding of original as applying the JSON Merge
+// Patch (RFC 7386) of patch produced by MergePatch.
+// Unlike with JSONPatch, a field of patch holding an Option that was not
+// provided leaves the member of original unchanged, and a provided Option
+// encoding as null removes it. original may be any value, including a
+// json.RawMessage holding a document that is already encoded.
+func JSONPatchFromMerge(original, patch any) (ops []PatchOperation, err error) {
+	a, err := decodeDocument(original)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := MergePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := decodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ops = []PatchOperation{}
+	return ops, mergeOperations(&ops, "", a, b)
+}
+
+// decodeDocument returns the JSON encoding of v decoded into generic values.
+func decodeDocument(v any) (doc any, err error) {
+	data, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJSON(data)
+}
+
+// decodeJSON decodes data into generic values, keeping numbers as they are
+// written.
+func decodeJSON(data []byte) (doc any, err error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err = dec.Decode(&doc)
+	return doc, err
+}
+
+// diffDocuments appends the operations turning a into b, found at path.
+func diffDocuments(ops *[]PatchOperation, path string, a, b any) (err error) {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+
+	if !aok || !bok {
+		if !reflect.DeepEqual(a, b) {
+			return appendOperation(ops, "replace", path, b)
+		}
+
+		return nil
+	}
+
+	for _, k := range unionKeys(am, bm) {
+		av, inA := am[k]
+		bv, inB := bm[k]
+		p := path + "/" + escapePointer(k)
+
+		switch {
+		case !inB:
+			*ops = append(*ops, PatchOperation{Op: "remove", Path: p})
+		case !inA:
+			err = appendOperation(ops, "add", p, bv)
+		default:
+			err = diffDocuments(ops, p, av, bv)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeOperations appends the operations applying the merge patch b to a,
+// found at path.
+func mergeOperations(ops *[]PatchOperation, path string, a, b any) (err error) {
+	bm, ok := b.(map[string]any)
+	if !ok {
+		if !reflect.DeepEqual(a, b) {
+			return appendOperation(ops, "replace", path, b)
+		}
+
+		return nil
+	}
+
+	am, ok := a.(map[string]any)
+	if !ok {
+		return appendOperation(ops, "replace", path, stripNulls(bm))
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(bm)) {
+		av, inA := am[k]
+		bv := bm[k]
+		p := path + "/" + escapePointer(k)
+
+		switch {
+		case bv == nil:
+			if inA {
+				*ops = append(*ops, PatchOperation{Op: "remove", Path: p})
+			}
+		case !inA:
+			err = appendOperation(ops, "add", p, stripNulls(bv))
+		default:
+			err = mergeOperations(ops, p, av, bv)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendOperation appends the operation op setting the member at path to
+// value.
+func appendOperation(ops *[]PatchOperation, op, path string, value any) (err error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	*ops = append(*ops, PatchOperation{Op: op, Path: path, Value: data})
+	return nil
+}
+
+// stripNulls returns v with the null members of its objects removed, as they
+// are when a merge patch is applied to a missing member.
+func stripNulls(v any) (stripped any) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+
+	out := make(map[string]any, len(m))
+	for k, mv := range m {
+		if mv != nil {
+			out[k] = stripNulls(mv)
+		}
+	}
+
+	return out
+}
+
+// unionKeys returns the keys of a and b, sorted.
+func unionKeys(a, b map[string]any) (keys []string) {
+	keys = slices.AppendSeq(slices.Collect(maps.Keys(a)), maps.Keys(b))
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// pointerEscaper escapes the reference tokens of a JSON Pointer.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// escapePointer escapes the object key k for use in a JSON Pointer.
+func escapePointer(k string) (token string) {
+	return pointerEscaper.Replace(k)
+}
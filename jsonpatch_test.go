@@ -0,0 +1,97 @@
+package opt_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testJSONPatch struct {
+	Name    opt.Option[string]                `json:"name"`
+	Email   opt.Option[string]                `json:"email"`
+	Manager opt.Option[*string]               `json:"manager"`
+	Address opt.Option[testMergePatchAddress] `json:"address"`
+	Tags    opt.Option[[]string]              `json:"tags"`
+	Paths   map[string]int                    `json:"paths,omitempty"`
+}
+
+// marshalOperations returns ops encoded as JSON, as json.RawMessage does not
+// snapshot readably.
+func marshalOperations(ops []opt.PatchOperation, err error) (data string) {
+	if err != nil {
+		return fmt.Sprint(err)
+	}
+
+	b, _ := json.Marshal(ops)
+	return string(b)
+}
+
+func Test_JSONPatch(t *testing.T) {
+	from := testJSONPatch{
+		Name:    opt.Some("Ada"),
+		Email:   opt.Some("ada@example.com"),
+		Address: opt.Some(testMergePatchAddress{City: opt.Some("London"), Postcode: opt.Some("W1")}),
+		Tags:    opt.Some([]string{"admin"}),
+		Paths:   map[string]int{"a/b": 1, "c~d": 2},
+	}
+
+	t.Run("Diff", func(t *testing.T) {
+		to := testJSONPatch{
+			Name:    opt.Some("Ada Lovelace"),
+			Manager: opt.Some[*string](nil),
+			Address: opt.Some(testMergePatchAddress{City: opt.Some("Paris")}),
+			Tags:    opt.Some([]string{"admin", "owner"}),
+			Paths:   map[string]int{"a/b": 3, "c~d": 2},
+		}
+
+		snaps.MatchSnapshot(t, marshalOperations(opt.JSONPatch(from, to)))
+	})
+
+	t.Run("Equal", func(t *testing.T) {
+		snaps.MatchSnapshot(t, marshalOperations(opt.JSONPatch(from, &from)))
+	})
+
+	t.Run("Root", func(t *testing.T) {
+		snaps.MatchSnapshot(t, marshalOperations(opt.JSONPatch(opt.Some(1), []int{1})))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		snaps.MatchSnapshot(t, marshalOperations(opt.JSONPatch(from, opt.Some(make(chan int)))))
+	})
+}
+
+func Test_JSONPatchFromMerge(t *testing.T) {
+	original := json.RawMessage(`{"name":"Ada","nickname":"Countess","manager":"Charles","address":{"city":"London","postcode":"W1"},"tags":["admin"]}`)
+
+	t.Run("Patch", func(t *testing.T) {
+		patch := testMergePatch{
+			Name:     opt.Some("Ada"),
+			Nickname: opt.Some(opt.None[string]()),
+			Manager:  opt.Some[*string](nil),
+			Address:  opt.Some(testMergePatchAddress{Postcode: opt.Some("NW1")}),
+			Tags:     opt.Some([]string{"owner"}),
+		}
+
+		snaps.MatchSnapshot(t, marshalOperations(opt.JSONPatchFromMerge(original, patch)))
+	})
+
+	t.Run("Missing members", func(t *testing.T) {
+		patch := testMergePatch{
+			Nickname: opt.Some(opt.None[string]()),
+			Address:  opt.Some(testMergePatchAddress{City: opt.Some("Paris")}),
+		}
+
+		snaps.MatchSnapshot(t, marshalOperations(opt.JSONPatchFromMerge(json.RawMessage(`{"address":"unknown"}`), patch)))
+	})
+
+	t.Run("Empty patch", func(t *testing.T) {
+		snaps.MatchSnapshot(t, marshalOperations(opt.JSONPatchFromMerge(original, testMergePatch{})))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		snaps.MatchSnapshot(t, marshalOperations(opt.JSONPatchFromMerge(original, "hello world")))
+	})
+}
@@ -0,0 +1,10 @@
+package opt
+
+// JSONSchemaAlias is the alias hook of github.com/invopop/jsonschema,
+// returning a value whose type is described in place of the Option. An
+// Option[T] is therefore described by the schema of T rather than as an empty
+// object. Describing Option fields as nullable and not required needs the
+// struct holding them, which the optjsonschema package handles.
+func (o Option[T]) JSONSchemaAlias() (alias any) {
+	return (*T)(nil)
+}
@@ -0,0 +1,67 @@
+package opt
+
+import "sync"
+
+// Lazy is an Option whose value is determined by a function the first time it
+// is needed, for values that are optional and expensive to look up.
+// The function is called at most once, even when the Lazy is used from
+// several goroutines at the same time, and its result is kept.
+// A Lazy must not be copied after first use.
+type Lazy[T any] struct {
+	// once guards the call to fn.
+	once sync.Once
+
+	// fn determines the value, and whether it is provided.
+	fn func() (T, bool)
+
+	// result holds the Option produced by fn once it has been called.
+	result Option[T]
+}
+
+// NewLazy returns a Lazy whose value is determined by fn.
+// If fn reports false, the value of the Lazy is not provided.
+func NewLazy[T any](fn func() (T, bool)) (l *Lazy[T]) {
+	return &Lazy[T]{
+		fn: fn,
+	}
+}
+
+// Option returns the value as an Option, calling the function of the Lazy if
+// it has not been called yet.
+func (l *Lazy[T]) Option() (o Option[T]) {
+	l.once.Do(func() {
+		if l.fn == nil {
+			return
+		}
+
+		value, ok := l.fn()
+		if ok {
+			l.result = Some(value)
+		}
+
+		// Let the function and what it refers to be collected.
+		l.fn = nil
+	})
+
+	return l.result
+}
+
+// Exists reports whether the value was provided, calling the function of the
+// Lazy if it has not been called yet.
+func (l *Lazy[T]) Exists() (exists bool) {
+	return l.Option().exists
+}
+
+// Unwrap returns the value, calling the function of the Lazy if it has not
+// been called yet.
+// If the value is not provided, Unwrap returns the zero value of the type.
+func (l *Lazy[T]) Unwrap() (value T) {
+	return l.Option().Unwrap()
+}
+
+// UnwrapDefault returns the value, or returns the defaultValue if the value
+// is not provided, calling the function of the Lazy if it has not been called
+// yet.
+func (l *Lazy[T]) UnwrapDefault(defaultValue T) (value T) {
+	return l.Option().UnwrapDefault(defaultValue)
+}
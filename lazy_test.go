@@ -0,0 +1,49 @@
+package opt_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_Lazy(t *testing.T) {
+	t.Run("Provided", func(t *testing.T) {
+		var calls atomic.Int32
+		l := opt.NewLazy(func() (string, bool) {
+			calls.Add(1)
+			return "hello world", true
+		})
+
+		before := calls.Load()
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.Unwrap()
+			}()
+		}
+		wg.Wait()
+
+		snaps.MatchSnapshot(t, before, l.Exists(), l.Unwrap(), calls.Load())
+	})
+
+	t.Run("Not provided", func(t *testing.T) {
+		calls := 0
+		l := opt.NewLazy(func() (int, bool) {
+			calls++
+			return 42, false
+		})
+
+		snaps.MatchSnapshot(t, l.Exists(), l.Unwrap(), l.UnwrapDefault(7), l.Option().Exists(), calls)
+	})
+
+	t.Run("Zero", func(t *testing.T) {
+		var l opt.Lazy[int]
+		snaps.MatchSnapshot(t, l.Exists(), l.Unwrap())
+	})
+}
@@ -0,0 +1,747 @@
+package opt
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	marshalerType     = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	optionIfaceType   = reflect.TypeFor[option]()
+)
+
+// option is implemented by every Option[T] and lets the struct walker inspect
+// an Option without knowing T.
+type option interface {
+	Wrapper
+	elemType() reflect.Type
+	reflectValue() reflect.Value
+}
+
+// elemType returns the reflect.Type of T.
+func (o Option[T]) elemType() (t reflect.Type) {
+	return reflect.TypeFor[T]()
+}
+
+// reflectValue returns a copy of the value as a reflect.Value of type T.
+func (o Option[T]) reflectValue() (value reflect.Value) {
+	return reflect.ValueOf(&o.value).Elem()
+}
+
+// optionSetter is implemented by every *Option[T] and lets the struct walker
+// fill in an Option without knowing T.
+type optionSetter interface {
+	provide() reflect.Value
+}
+
+// provide marks the value as provided and returns it as a settable
+// reflect.Value.
+func (o *Option[T]) provide() (value reflect.Value) {
+	o.exists = true
+	return reflect.ValueOf(&o.value).Elem()
+}
+
+// isOptionType reports whether t is an instantiation of Option.
+func isOptionType(t reflect.Type) (ok bool) {
+	return t.Kind() == reflect.Struct && t.Implements(optionIfaceType)
+}
+
+// optionElemType returns T for a t that is an Option[T].
+func optionElemType(t reflect.Type) (elem reflect.Type) {
+	return reflect.Zero(t).Interface().(option).elemType()
+}
+
+// UnsupportedTypeError is returned by Marshal when an Option field holds a type
+// that cannot be encoded, such as a channel or a func.
+type UnsupportedTypeError struct {
+	// Path is the dotted path of JSON names leading to the offending field.
+	// Slice and array elements are written as "[*]" and map values as ".*".
+	Path string
+
+	// Type is the type that cannot be encoded.
+	Type reflect.Type
+}
+
+// Error implements the error interface.
+func (e *UnsupportedTypeError) Error() (msg string) {
+	msg = "opt: unsupported type " + e.Type.String() + " in Option"
+	if e.Path != "" {
+		msg += " at " + e.Path
+	}
+
+	return msg
+}
+
+// Marshal returns the JSON encoding of v.
+// Marshal behaves like json.Marshal except that struct fields holding an
+// Option that was not provided are omitted from the output entirely rather
+// than being encoded as null.
+// Before encoding, Marshal checks every Option reachable from the type of v
+// and returns an *UnsupportedTypeError if any of them hold a type that cannot
+// be encoded as JSON.
+func Marshal(v any) (data []byte, err error) {
+	rv := reflect.ValueOf(v)
+
+	if rv.IsValid() {
+		if err = checkType(rv.Type()); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	e := encodeState{w: &buf, escapeHTML: true}
+	if err = e.encodeValue(rv); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writer is the subset of *bytes.Buffer and *bufio.Writer used while encoding.
+type writer interface {
+	io.Writer
+	io.ByteWriter
+}
+
+// encodeState holds the output and settings of a single encoding.
+type encodeState struct {
+	// w receives the encoded output.
+	w writer
+
+	// escapeHTML indicates whether <, > and & are escaped inside JSON strings.
+	escapeHTML bool
+
+	// protoJSON indicates whether values are written following the
+	// conventions of protojson, as by MarshalProtoJSON.
+	protoJSON bool
+
+	// emitUnpopulated indicates whether absent Options and empty fields are
+	// written in protojson mode, as set by ProtoJSONOptions.EmitUnpopulated.
+	emitUnpopulated bool
+
+	// stream indicates whether slices, arrays and maps holding no Options are
+	// written element by element rather than encoded whole by encoding/json,
+	// so that they are not held in memory, as an Encoder does.
+	stream bool
+
+	// scratch holds the output of encoding/json for leaf values.
+	scratch bytes.Buffer
+
+	// ptrLevel is the number of pointers, slices and maps being encoded.
+	ptrLevel uint
+
+	// ptrSeen holds the pointers, slices and maps being encoded once
+	// ptrLevel passes startDetectingCyclesAfter.
+	ptrSeen map[ptrVisit]struct{}
+}
+
+// startDetectingCyclesAfter is the depth of nested pointers, slices and maps
+// after which encodeState looks for cycles, as in encoding/json, so that
+// values nested less deeply do not pay for it.
+const startDetectingCyclesAfter = 1000
+
+// ptrVisit identifies a pointer, slice or map being encoded. Slices are told
+// apart by their length as well, since a slice and a shorter slice of the same
+// array are different values.
+type ptrVisit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter records that the pointer, slice or map v is being encoded. It returns
+// a *json.UnsupportedValueError if v is already being encoded, which means
+// that v holds itself. Each call that succeeds is paired with a call to leave.
+func (e *encodeState) enter(v reflect.Value) (err error) {
+	if e.ptrLevel++; e.ptrLevel <= startDetectingCyclesAfter {
+		return nil
+	}
+
+	visit := ptrVisit{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		visit.len = v.Len()
+	}
+
+	if _, ok := e.ptrSeen[visit]; ok {
+		e.ptrLevel--
+		return &json.UnsupportedValueError{Value: v, Str: "encountered a cycle via " + v.Type().String()}
+	}
+
+	if e.ptrSeen == nil {
+		e.ptrSeen = map[ptrVisit]struct{}{}
+	}
+
+	e.ptrSeen[visit] = struct{}{}
+	return nil
+}
+
+// leave records that the pointer, slice or map v has been encoded.
+func (e *encodeState) leave(v reflect.Value) {
+	if e.ptrLevel > startDetectingCyclesAfter {
+		visit := ptrVisit{ptr: v.Pointer(), typ: v.Type()}
+		if v.Kind() == reflect.Slice {
+			visit.len = v.Len()
+		}
+
+		delete(e.ptrSeen, visit)
+	}
+
+	e.ptrLevel--
+}
+
+// maxScratchSize is the capacity above which scratch is released after a leaf
+// value is written, rather than holding on to the memory of a large value
+// until the encoding ends.
+const maxScratchSize = 64 * 1024
+
+// encodeValue writes the JSON encoding of v.
+func (e *encodeState) encodeValue(v reflect.Value) (err error) {
+	if !v.IsValid() {
+		e.w.Write(nullBytes)
+		return nil
+	}
+
+	t := v.Type()
+
+	if isOptionType(t) {
+		value, exists := v.Interface().(option).AnyValue()
+		if !exists {
+			e.w.Write(nullBytes)
+			return nil
+		}
+
+		return e.encodeValue(reflect.ValueOf(value))
+	}
+
+	if e.protoJSON {
+		return e.encodeProtoValue(v)
+	}
+
+	if isLeafType(t) || (!containsOption(t) && !(e.stream && isStreamedType(t))) {
+		return e.encodeLeaf(v)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.w.Write(nullBytes)
+			return nil
+		}
+
+		return e.encodeElem(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && v.IsNil() {
+			e.w.Write(nullBytes)
+			return nil
+		}
+
+		return e.encodeArray(v)
+	case reflect.Map:
+		if v.IsNil() {
+			e.w.Write(nullBytes)
+			return nil
+		}
+
+		return e.encodeMap(v)
+	}
+
+	return e.encodeLeaf(v)
+}
+
+// encodeElem writes the value the non-nil pointer or interface v holds.
+func (e *encodeState) encodeElem(v reflect.Value) (err error) {
+	if v.Kind() == reflect.Pointer {
+		if err = e.enter(v); err != nil {
+			return err
+		}
+		defer e.leave(v)
+	}
+
+	return e.encodeValue(v.Elem())
+}
+
+// encodeLeaf writes the JSON encoding of v produced by encoding/json.
+func (e *encodeState) encodeLeaf(v reflect.Value) (err error) {
+	e.scratch.Reset()
+
+	enc := json.NewEncoder(&e.scratch)
+	enc.SetEscapeHTML(e.escapeHTML)
+	if err = enc.Encode(v.Interface()); err != nil {
+		return err
+	}
+
+	// json.Encoder terminates each value with a newline.
+	_, err = e.w.Write(bytes.TrimSuffix(e.scratch.Bytes(), []byte{'\n'}))
+
+	if e.scratch.Cap() > maxScratchSize {
+		e.scratch = bytes.Buffer{}
+	}
+
+	return err
+}
+
+// encodeStruct writes the fields of the struct v, skipping absent Options.
+func (e *encodeState) encodeStruct(v reflect.Value) (err error) {
+	e.w.WriteByte('{')
+
+	first := true
+	for _, f := range cachedFields(v.Type()) {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// A nil embedded pointer hides its promoted fields, as it does in
+			// encoding/json.
+			continue
+		}
+
+		if f.option {
+			if _, exists := fv.Interface().(option).AnyValue(); !exists {
+				continue
+			}
+		} else if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		if !first {
+			e.w.WriteByte(',')
+		}
+		first = false
+
+		e.w.Write(f.key)
+		e.w.WriteByte(':')
+
+		if err = e.encodeValue(fv); err != nil {
+			return err
+		}
+	}
+
+	e.w.WriteByte('}')
+	return nil
+}
+
+// encodeArray writes the elements of the slice or array v.
+func (e *encodeState) encodeArray(v reflect.Value) (err error) {
+	if v.Kind() == reflect.Slice {
+		if err = e.enter(v); err != nil {
+			return err
+		}
+		defer e.leave(v)
+	}
+
+	e.w.WriteByte('[')
+
+	for i := range v.Len() {
+		if i > 0 {
+			// Stop early if the underlying writer has failed rather than
+			// encoding the rest of a potentially large slice.
+			if err = e.w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+
+		if err = e.encodeValue(v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	e.w.WriteByte(']')
+	return nil
+}
+
+// encodeMap writes the entries of the map v, sorted by key as they are in
+// encoding/json.
+func (e *encodeState) encodeMap(v reflect.Value) (err error) {
+	if err = e.enter(v); err != nil {
+		return err
+	}
+	defer e.leave(v)
+
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry{key: key, value: iter.Value()})
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	e.w.WriteByte('{')
+
+	for i, kv := range entries {
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+
+		if err = e.encodeLeaf(reflect.ValueOf(kv.key)); err != nil {
+			return err
+		}
+
+		e.w.WriteByte(':')
+
+		if err = e.encodeValue(kv.value); err != nil {
+			return err
+		}
+	}
+
+	e.w.WriteByte('}')
+	return nil
+}
+
+// mapKey returns the JSON object key for the map key k.
+func mapKey(k reflect.Value) (key string, err error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+
+	return "", &json.UnsupportedTypeError{Type: k.Type()}
+}
+
+// isStreamedType reports whether values of t are written element by element
+// while streaming even if they hold no Options. Byte slices are left to
+// encoding/json, which writes them as base64 strings.
+func isStreamedType(t reflect.Type) (ok bool) {
+	switch t.Kind() {
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Uint8
+	case reflect.Array, reflect.Map:
+		return true
+	}
+
+	return false
+}
+
+// isLeafType reports whether values of t encode themselves and should be
+// handed straight to encoding/json.
+func isLeafType(t reflect.Type) (ok bool) {
+	if isOptionType(t) {
+		return false
+	}
+
+	return t.Implements(marshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(marshalerType) ||
+		reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// isEmptyValue reports whether v is considered empty by the omitempty tag
+// option.
+func isEmptyValue(v reflect.Value) (empty bool) {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+
+	return false
+}
+
+// field describes how a single struct field is encoded.
+type field struct {
+	// name is the JSON name of the field.
+	name string
+
+	// key is the quoted JSON name of the field.
+	key []byte
+
+	// tagged indicates whether the name was given by the json tag.
+	tagged bool
+
+	// index is the index sequence for reflect.Value.FieldByIndex.
+	index []int
+
+	// typ is the type of the field.
+	typ reflect.Type
+
+	// omitEmpty indicates whether the omitempty tag option was given.
+	omitEmpty bool
+
+	// option indicates whether the field holds an Option.
+	option bool
+
+	// aliases holds the alternative JSON names given by the opt tag that
+	// Unmarshal accepts for the field.
+	aliases []string
+}
+
+// fieldCache maps a struct reflect.Type to its []field.
+var fieldCache sync.Map
+
+// cachedFields returns the encodable fields of the struct type t.
+func cachedFields(t reflect.Type) (fields []field) {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+
+	f, _ := fieldCache.LoadOrStore(t, dominantFields(typeFields(t, nil)))
+	return f.([]field)
+}
+
+// dominantFields returns fields without those hidden by another field of the
+// same name, following the encoding/json rules: the field nested least deeply
+// wins, then the only one of those with a json tag, and if there is still
+// more than one, none of them are kept.
+func dominantFields(fields []field) (dominant []field) {
+	byName := map[string][]field{}
+	for _, f := range fields {
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	for _, f := range fields {
+		if winner, ok := dominantField(byName[f.name]); ok && slices.Equal(winner.index, f.index) {
+			dominant = append(dominant, f)
+		}
+	}
+
+	return dominant
+}
+
+// dominantField returns the field of fields, which share a name, that hides
+// the others, and reports whether there is one.
+func dominantField(fields []field) (f field, ok bool) {
+	depth := len(fields[0].index)
+	for _, c := range fields[1:] {
+		depth = min(depth, len(c.index))
+	}
+
+	var shallowest []field
+	for _, c := range fields {
+		if len(c.index) == depth {
+			shallowest = append(shallowest, c)
+		}
+	}
+
+	if len(shallowest) == 1 {
+		return shallowest[0], true
+	}
+
+	var tagged []field
+	for _, c := range shallowest {
+		if c.tagged {
+			tagged = append(tagged, c)
+		}
+	}
+
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+
+	return field{}, false
+}
+
+// typeFields returns the fields of the struct type t following the
+// encoding/json naming rules, including those hidden by fields of the same
+// name, which dominantFields removes. Untagged embedded structs have their
+// fields promoted.
+func typeFields(t reflect.Type, index []int) (fields []field) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		ft := sf.Type
+
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldIndex := append(slices.Clone(index), i)
+
+		if sf.Anonymous && name == "" {
+			et := ft
+			if et.Kind() == reflect.Pointer {
+				et = et.Elem()
+			}
+
+			// Like encoding/json, fields of an embedded struct of an unexported
+			// type are promoted unless it is embedded through a pointer.
+			promoted := sf.IsExported() || ft.Kind() != reflect.Pointer
+			if promoted && et.Kind() == reflect.Struct && !isOptionType(et) {
+				fields = append(fields, typeFields(et, fieldIndex)...)
+				continue
+			}
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		tagged := name != ""
+		if !tagged {
+			name = sf.Name
+		}
+
+		key, _ := json.Marshal(name)
+
+		fields = append(fields, field{
+			name:      name,
+			key:       key,
+			tagged:    tagged,
+			index:     fieldIndex,
+			typ:       ft,
+			omitEmpty: slices.Contains(strings.Split(opts, ","), "omitempty"),
+			option:    isOptionType(ft),
+			aliases:   tagAliases(sf.Tag.Get("opt")),
+		})
+	}
+
+	return fields
+}
+
+// containsCache maps a reflect.Type to whether an Option is reachable from it.
+var containsCache sync.Map
+
+// containsOption reports whether an Option can be reached from t, in which case
+// values of t must be walked rather than handed to encoding/json.
+func containsOption(t reflect.Type) (ok bool) {
+	if c, ok := containsCache.Load(t); ok {
+		return c.(bool)
+	}
+
+	c, _ := containsCache.LoadOrStore(t, reachesOption(t, map[reflect.Type]bool{}))
+	return c.(bool)
+}
+
+// reachesOption does the work for containsOption.
+func reachesOption(t reflect.Type, seen map[reflect.Type]bool) (ok bool) {
+	if isOptionType(t) {
+		return true
+	}
+
+	if seen[t] || isLeafType(t) {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return reachesOption(t.Elem(), seen)
+	case reflect.Interface:
+		// The dynamic type may hold an Option.
+		return true
+	case reflect.Struct:
+		for _, f := range cachedFields(t) {
+			if reachesOption(f.typ, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkCache maps a reflect.Type to the error, if any, returned by checkType.
+var checkCache sync.Map
+
+// checkType verifies that every Option reachable from t holds a type that
+// can be encoded.
+func checkType(t reflect.Type) (err error) {
+	if c, ok := checkCache.Load(t); ok {
+		err, _ = c.(error)
+		return err
+	}
+
+	err = checkOptions(t, "", false, map[checkKey]bool{})
+	checkCache.Store(t, err)
+	return err
+}
+
+// checkKey identifies a type visited by checkOptions.
+type checkKey struct {
+	typ      reflect.Type
+	inOption bool
+}
+
+// checkOptions does the work for checkType.
+// inOption indicates whether t was reached through an Option.
+func checkOptions(t reflect.Type, path string, inOption bool, seen map[checkKey]bool) (err error) {
+	if isOptionType(t) {
+		return checkOptions(optionElemType(t), path, true, seen)
+	}
+
+	key := checkKey{typ: t, inOption: inOption}
+	if seen[key] || isLeafType(t) {
+		return nil
+	}
+	seen[key] = true
+
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		if inOption {
+			return &UnsupportedTypeError{Path: path, Type: t}
+		}
+	case reflect.Pointer:
+		return checkOptions(t.Elem(), path, inOption, seen)
+	case reflect.Slice, reflect.Array:
+		return checkOptions(t.Elem(), path+"[*]", inOption, seen)
+	case reflect.Map:
+		if inOption && !isValidMapKey(t.Key()) {
+			return &UnsupportedTypeError{Path: path, Type: t}
+		}
+
+		return checkOptions(t.Elem(), path+".*", inOption, seen)
+	case reflect.Struct:
+		for _, f := range cachedFields(t) {
+			if err = checkOptions(f.typ, joinPath(path, f.name), inOption, seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isValidMapKey reports whether encoding/json can encode maps keyed by t.
+func isValidMapKey(t reflect.Type) (ok bool) {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return t.Implements(textMarshalerType)
+}
+
+// joinPath appends name to the dotted path.
+func joinPath(path, name string) (joined string) {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
@@ -0,0 +1,180 @@
+package opt_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testNestedPayload struct {
+	Name    string                  `json:"name"`
+	Inner   testPayload             `json:"inner"`
+	Items   []testPayload           `json:"items"`
+	ByKey   map[string]testPayload  `json:"byKey"`
+	Nested  opt.Option[testPayload] `json:"nested"`
+	Skipped string                  `json:"-"`
+	Empty   string                  `json:"empty,omitempty"`
+}
+
+func Test_Marshal(t *testing.T) {
+	for n, c := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var payload testPayload
+
+			if err := json.Unmarshal(c.data, &payload); err != nil {
+				t.Fatalf("Unexpected unmarshal error: %s", err)
+			}
+
+			result, err := opt.Marshal(payload)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %s", err)
+			}
+
+			snaps.MatchSnapshot(t, string(result))
+		})
+	}
+
+	t.Run("Nested", func(t *testing.T) {
+		data := []byte(`
+			{
+				"name": "nested",
+				"inner": {"primitive": "inner"},
+				"items": [{"slice": [1]}, {}],
+				"byKey": {"b": {"pointer": null}, "a": {"map": {}}},
+				"nested": {"struct": {"Make": "Toyota"}}
+			}
+		`)
+
+		var payload testNestedPayload
+		if err := json.Unmarshal(data, &payload); err != nil {
+			t.Fatalf("Unexpected unmarshal error: %s", err)
+		}
+
+		result, err := opt.Marshal(payload)
+		if err != nil {
+			t.Fatalf("Unexpected marshal error: %s", err)
+		}
+
+		snaps.MatchSnapshot(t, string(result))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		result, err := opt.Marshal(nil)
+		if err != nil {
+			t.Fatalf("Unexpected marshal error: %s", err)
+		}
+
+		snaps.MatchSnapshot(t, string(result))
+	})
+}
+
+type testEmbeddedA struct {
+	ID    opt.Option[int] `json:"id"`
+	Count opt.Option[int]
+	Name  string
+}
+
+type testEmbeddedB struct {
+	Count opt.Option[int]
+	Name  string `json:"Name"`
+}
+
+func Test_Marshal_Embedded(t *testing.T) {
+	a := testEmbeddedA{ID: opt.Some(1), Count: opt.Some(1), Name: "a"}
+	b := testEmbeddedB{Count: opt.Some(2), Name: "b"}
+
+	values := map[string]any{
+		"Same depth": struct {
+			testEmbeddedA
+			testEmbeddedB
+		}{a, b},
+		"Shallower": struct {
+			testEmbeddedB
+			Name string
+		}{b, "outer"},
+	}
+
+	for n, v := range values {
+		t.Run(n, func(t *testing.T) {
+			result, err := opt.Marshal(v)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %s", err)
+			}
+
+			expected, err := json.Marshal(v)
+			if err != nil {
+				t.Fatalf("Unexpected json.Marshal error: %s", err)
+			}
+
+			if string(result) != string(expected) {
+				t.Fatalf("Expected %s, got %s", expected, result)
+			}
+
+			snaps.MatchSnapshot(t, string(result))
+		})
+	}
+}
+
+func Test_Marshal_Unsupported(t *testing.T) {
+	type withChan struct {
+		Events opt.Option[chan int] `json:"events"`
+	}
+
+	type withFunc struct {
+		Hook opt.Option[map[string]func()] `json:"hook"`
+	}
+
+	type deep struct {
+		Outer opt.Option[struct {
+			Inner []withChan `json:"inner"`
+		}] `json:"outer"`
+	}
+
+	type plainChan struct {
+		Events chan int `json:"-"`
+	}
+
+	cases := map[string]any{
+		"Chan":       withChan{},
+		"Func":       &withFunc{},
+		"Deep":       deep{},
+		"Option":     opt.Option[complex128]{},
+		"Plain chan": plainChan{},
+	}
+
+	for n, v := range cases {
+		t.Run(n, func(t *testing.T) {
+			_, err := opt.Marshal(v)
+
+			var typeErr *opt.UnsupportedTypeError
+			if err != nil && !errors.As(err, &typeErr) {
+				t.Fatalf("Unexpected error type %T: %s", err, err)
+			}
+
+			snaps.MatchSnapshot(t, fmt.Sprint(err))
+		})
+	}
+}
+
+type testNode struct {
+	Name opt.Option[string] `json:"name"`
+	Next *testNode          `json:"next"`
+}
+
+func Test_Marshal_Cycle(t *testing.T) {
+	node := &testNode{Name: opt.Some("loop")}
+	node.Next = node
+
+	_, err := opt.Marshal(node)
+
+	var valueErr *json.UnsupportedValueError
+	if !errors.As(err, &valueErr) {
+		t.Fatalf("Expected *json.UnsupportedValueError, got %T: %v", err, err)
+	}
+
+	snaps.MatchSnapshot(t, err.Error())
+}
@@ -0,0 +1,26 @@
+package opt
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergePatch returns a JSON Merge Patch (RFC 7386) holding the fields of the
+// struct v that are provided, ready to be sent as the body of an HTTP PATCH
+// request with the application/merge-patch+json media type.
+// Fields holding an Option that was not provided are left out, so they are
+// left unchanged by the patch. To remove a member, provide an Option whose
+// value encodes as null, such as Some[*T](nil) or Some(None[T]()): a field of
+// type Option[Option[T]] can be left unchanged, removed, or set to a value.
+// Nested structs are written as nested patches holding only their provided
+// fields, which merge into the target member by member, whereas slices and
+// other values replace the target member as a whole. v may also be a pointer
+// to a struct.
+func MergePatch(v any) (patch []byte, err error) {
+	rv := indirectValue(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct || isOptionType(rv.Type()) {
+		return nil, fmt.Errorf("opt: MergePatch requires a struct, got %T", v)
+	}
+
+	return Marshal(rv.Interface())
+}
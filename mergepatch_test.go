@@ -0,0 +1,52 @@
+package opt_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testMergePatchAddress struct {
+	City     opt.Option[string] `json:"city"`
+	Postcode opt.Option[string] `json:"postcode"`
+}
+
+type testMergePatch struct {
+	Name     opt.Option[string]                `json:"name"`
+	Nickname opt.Option[opt.Option[string]]    `json:"nickname"`
+	Manager  opt.Option[*string]               `json:"manager"`
+	Address  opt.Option[testMergePatchAddress] `json:"address"`
+	Tags     opt.Option[[]string]              `json:"tags"`
+}
+
+func Test_MergePatch(t *testing.T) {
+	t.Run("Set", func(t *testing.T) {
+		patch, err := opt.MergePatch(testMergePatch{
+			Name:     opt.Some("Ada"),
+			Nickname: opt.Some(opt.Some("Countess")),
+			Address:  opt.Some(testMergePatchAddress{City: opt.Some("London")}),
+			Tags:     opt.Some([]string{"admin"}),
+		})
+		snaps.MatchSnapshot(t, string(patch), fmt.Sprint(err))
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		patch, err := opt.MergePatch(&testMergePatch{
+			Nickname: opt.Some(opt.None[string]()),
+			Manager:  opt.Some[*string](nil),
+		})
+		snaps.MatchSnapshot(t, string(patch), fmt.Sprint(err))
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		patch, err := opt.MergePatch(testMergePatch{})
+		snaps.MatchSnapshot(t, string(patch), fmt.Sprint(err))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		patch, err := opt.MergePatch(opt.Some("hello world"))
+		snaps.MatchSnapshot(t, patch == nil, fmt.Sprint(err))
+	})
+}
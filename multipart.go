@@ -0,0 +1,80 @@
+package opt
+
+import (
+	"mime/multipart"
+	"reflect"
+)
+
+// fileHeaderType is the reflect.Type of *multipart.FileHeader.
+var fileHeaderType = reflect.TypeFor[*multipart.FileHeader]()
+
+// DecodeMultipart decodes the multipart form into the struct target points
+// to, such as the form parsed by http.Request.ParseMultipartForm:
+//
+//	if err := r.ParseMultipartForm(32 << 20); err != nil {
+//		return err
+//	}
+//
+//	err := opt.DecodeMultipart(r.MultipartForm, &profile)
+//
+// Each exported field is decoded from the part named by its form tag, or by
+// the field name if it has none. Fields tagged "-" are skipped, and the fields
+// of untagged embedded structs are promoted.
+// Fields of type *multipart.FileHeader or []*multipart.FileHeader, or Options
+// of them, are decoded from the file parts, and other fields from the values,
+// converted as they are by DecodeQuery.
+// An Option field is provided if its part was submitted, even if its value is
+// empty, so that a cleared input decodes to Some(""), and is left untouched if
+// it was not submitted. Browsers submit an empty file input as an empty value
+// rather than a file part, so it leaves a file field untouched.
+// Other fields are set only if their part was submitted.
+// If a value cannot be converted, DecodeMultipart returns an error naming the
+// part, and the fields decoded before it are left in place.
+func DecodeMultipart(form *multipart.Form, target any) (err error) {
+	if form == nil {
+		form = &multipart.Form{}
+	}
+
+	return decodeFields("DecodeMultipart", target, "form", func(dst reflect.Value, name string) (err error) {
+		if isFileType(dst.Type()) {
+			files, ok := form.File[name]
+			if !ok || len(files) == 0 {
+				return nil
+			}
+
+			assignFiles(dst, files)
+			return nil
+		}
+
+		values, ok := form.Value[name]
+		if !ok {
+			return nil
+		}
+
+		return assignStrings(dst, values)
+	})
+}
+
+// isFileType reports whether t is decoded from file parts by DecodeMultipart.
+func isFileType(t reflect.Type) (ok bool) {
+	if isOptionType(t) {
+		t = optionElemType(t)
+	}
+
+	return t == fileHeaderType || t.Kind() == reflect.Slice && t.Elem() == fileHeaderType
+}
+
+// assignFiles stores files in dst, a field for which isFileType is true.
+func assignFiles(dst reflect.Value, files []*multipart.FileHeader) {
+	elem := dst
+	if isOptionType(dst.Type()) {
+		elem = dst.Addr().Interface().(optionSetter).provide()
+	}
+
+	if elem.Type() == fileHeaderType {
+		elem.Set(reflect.ValueOf(files[0]))
+		return
+	}
+
+	elem.Set(reflect.ValueOf(files))
+}
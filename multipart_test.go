@@ -0,0 +1,115 @@
+package opt_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testProfileForm struct {
+	Name        opt.Option[string]                  `form:"name"`
+	Bio         opt.Option[string]                  `form:"bio"`
+	Age         opt.Option[int]                     `form:"age"`
+	Avatar      opt.Option[*multipart.FileHeader]   `form:"avatar"`
+	Attachments opt.Option[[]*multipart.FileHeader] `form:"attachments"`
+	Banner      *multipart.FileHeader               `form:"banner"`
+}
+
+// testMultipartForm returns the form read from a body with fields and files,
+// each file holding its name as its content.
+func testMultipartForm(t *testing.T, fields [][2]string, files [][2]string) (form *multipart.Form) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, f := range fields {
+		if err := w.WriteField(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, f := range files {
+		part, err := w.CreateFormFile(f[0], f[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = part.Write([]byte(f[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return form
+}
+
+// fileNames returns the names of the files in p, for snapshots.
+func (p testProfileForm) fileNames() (names map[string]any) {
+	names = map[string]any{}
+	if p.Avatar.Exists() {
+		names["avatar"] = p.Avatar.Unwrap().Filename
+	}
+
+	if p.Attachments.Exists() {
+		var list []string
+		for _, a := range p.Attachments.Unwrap() {
+			list = append(list, a.Filename)
+		}
+
+		names["attachments"] = list
+	}
+
+	if p.Banner != nil {
+		names["banner"] = p.Banner.Filename
+	}
+
+	return names
+}
+
+func Test_DecodeMultipart(t *testing.T) {
+	testCases := map[string]struct {
+		fields [][2]string
+		files  [][2]string
+	}{
+		"Empty": {},
+		"Cleared": {
+			fields: [][2]string{{"name", "Ada"}, {"bio", ""}, {"avatar", ""}},
+		},
+		"Files": {
+			fields: [][2]string{{"age", "36"}},
+			files: [][2]string{
+				{"avatar", "me.png"},
+				{"attachments", "a.txt"},
+				{"attachments", "b.txt"},
+				{"banner", "banner.png"},
+			},
+		},
+		"Invalid": {
+			fields: [][2]string{{"name", "Ada"}, {"age", "old"}},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			form := testMultipartForm(t, tc.fields, tc.files)
+
+			var profile testProfileForm
+			err := opt.DecodeMultipart(form, &profile)
+			snaps.MatchSnapshot(t, profile.Name, profile.Bio, profile.Age, profile.fileNames(), err)
+		})
+	}
+
+	t.Run("Not a pointer", func(t *testing.T) {
+		err := opt.DecodeMultipart(&multipart.Form{}, testProfileForm{})
+		snaps.MatchSnapshot(t, err)
+	})
+}
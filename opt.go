@@ -23,6 +23,69 @@ type Option[T any] struct {
 	exists bool
 }
 
+// Some returns an Option holding the provided value.
+func Some[T any](value T) (o Option[T]) {
+	return Option[T]{
+		value:  value,
+		exists: true,
+	}
+}
+
+// None returns an Option whose value is not provided.
+func None[T any]() (o Option[T]) {
+	return Option[T]{}
+}
+
+// FromMap returns an Option holding the value of m for the key k.
+// If m holds k, FromMap returns Some of its value, even if it is the zero
+// value.
+// If m does not hold k, or m is nil, FromMap returns None.
+func FromMap[K comparable, V any](m map[K]V, k K) (o Option[V]) {
+	value, ok := m[k]
+	return Option[V]{
+		value:  value,
+		exists: ok,
+	}
+}
+
+// FromIndex returns an Option holding the element of s at index i.
+// If i is within the bounds of s, FromIndex returns Some of the element.
+// If i is negative or not less than len(s), FromIndex returns None.
+func FromIndex[T any](s []T, i int) (o Option[T]) {
+	if i < 0 || i >= len(s) {
+		return None[T]()
+	}
+
+	return Some(s[i])
+}
+
+// FromAssert returns an Option holding v asserted to the type T.
+// If v holds a T, FromAssert returns Some of it.
+// If v does not hold a T, or v is nil, FromAssert returns None.
+func FromAssert[T any](v any) (o Option[T]) {
+	value, ok := v.(T)
+	return Option[T]{
+		value:  value,
+		exists: ok,
+	}
+}
+
+// Wrap returns an Option holding value unless err is not nil, so that the
+// results of functions such as strconv.Atoi can be used as Options when the
+// error itself does not matter:
+//
+//	port := opt.Wrap(strconv.Atoi(s))
+//
+// If err is nil, Wrap returns Some of value.
+// If err is not nil, Wrap returns None.
+func Wrap[T any](value T, err error) (o Option[T]) {
+	if err != nil {
+		return None[T]()
+	}
+
+	return Some(value)
+}
+
 // MarshalJSON marshals the Option to JSON.
 // If the value is provided, MarshalJSON marshals the value.
 // If the value is not provided, MarshalJSON returns "null".
@@ -64,8 +127,18 @@ func (o *Option[T]) UnmarshalJSON(data []byte) (err error) {
 // String returns a string representation of the value.
 // If the value is not provided, String returns "<empty>".
 func (o Option[T]) String() (str string) {
+	return o.StringOr("<empty>")
+}
+
+// StringOr returns a string representation of the value, or placeholder if
+// the value is not provided, for output that needs another representation of
+// a missing value than String gives, such as "-" or "".
+// If the value is provided, StringOr returns the value formatted as by
+// fmt.Sprint.
+// If the value is not provided, StringOr returns placeholder.
+func (o Option[T]) StringOr(placeholder string) (str string) {
 	if !o.exists {
-		return "<empty>"
+		return placeholder
 	}
 
 	return fmt.Sprint(o.value)
@@ -76,6 +149,14 @@ func (o Option[T]) Exists() (exists bool) {
 	return o.exists
 }
 
+// IsZero reports whether the value was not provided.
+// It lets encoders that skip zero values, such as the omitzero option of
+// encoding/json and the nullzero tag of Bun, treat a provided zero value as
+// set.
+func (o Option[T]) IsZero() (zero bool) {
+	return !o.exists
+}
+
 // Unwrap returns the value.
 // If the value is not provided, Unwrap returns the zero value of the type.
 func (o Option[T]) Unwrap() (value T) {
@@ -102,3 +183,53 @@ func (o Option[T]) UnwrapDefault(defaultValue T) (value T) {
 
 	return o.value
 }
+
+// Or returns the Option, or other if the value is not provided, so that
+// fallbacks can be chained:
+//
+//	theme := request.Theme.Or(preferences.Theme).Or(opt.Some(defaultTheme))
+//
+// If the value is provided, Or returns the Option.
+// If the value is not provided, Or returns other.
+func (o Option[T]) Or(other Option[T]) (result Option[T]) {
+	if o.exists {
+		return o
+	}
+
+	return other
+}
+
+// OrElse returns the Option, or the result of fn if the value is not
+// provided. Unlike Or, the fallback is only computed when it is needed.
+// If the value is provided, OrElse returns the Option and fn is not called.
+// If the value is not provided, OrElse returns the result of fn.
+func (o Option[T]) OrElse(fn func() Option[T]) (result Option[T]) {
+	if o.exists {
+		return o
+	}
+
+	return fn()
+}
+
+// IfPresent calls fn with the value and returns the Option, so that calls can
+// be chained.
+// If the value is provided, fn is called with the value.
+// If the value is not provided, fn is not called.
+func (o Option[T]) IfPresent(fn func(T)) (self Option[T]) {
+	if o.exists {
+		fn(o.value)
+	}
+
+	return o
+}
+
+// IfAbsent calls fn and returns the Option, so that calls can be chained.
+// If the value is provided, fn is not called.
+// If the value is not provided, fn is called.
+func (o Option[T]) IfAbsent(fn func()) (self Option[T]) {
+	if !o.exists {
+		fn()
+	}
+
+	return o
+}
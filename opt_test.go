@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/fletcharoo/opt"
@@ -312,3 +313,148 @@ func test_String(t *testing.T, payload testPayload) {
 		snaps.MatchSnapshot(t, fmt.Sprint(payload.Slice))
 	})
 }
+
+func Test_Constructors(t *testing.T) {
+	t.Run("Some", func(t *testing.T) {
+		o := opt.Some("hello world")
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+
+	t.Run("Some zero", func(t *testing.T) {
+		o := opt.Some(0)
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+
+	t.Run("None", func(t *testing.T) {
+		o := opt.None[string]()
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+}
+
+func Test_FromMap(t *testing.T) {
+	m := map[string]int{"one": 1, "zero": 0}
+
+	for _, k := range []string{"one", "zero", "missing"} {
+		t.Run(k, func(t *testing.T) {
+			o := opt.FromMap(m, k)
+			snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+		})
+	}
+
+	t.Run("Nil map", func(t *testing.T) {
+		o := opt.FromMap(map[string]int(nil), "one")
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+}
+
+func Test_FromIndex(t *testing.T) {
+	row := []string{"Ada", "", "London"}
+
+	for n, i := range map[string]int{
+		"First":    0,
+		"Empty":    1,
+		"Last":     2,
+		"Past end": 3,
+		"Negative": -1,
+	} {
+		t.Run(n, func(t *testing.T) {
+			o := opt.FromIndex(row, i)
+			snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+		})
+	}
+}
+
+func Test_FromAssert(t *testing.T) {
+	config := map[string]any{"port": 8080, "host": "localhost", "debug": nil}
+
+	for _, k := range []string{"port", "host", "debug", "missing"} {
+		t.Run(k, func(t *testing.T) {
+			o := opt.FromAssert[int](config[k])
+			snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+		})
+	}
+
+	t.Run("Interface", func(t *testing.T) {
+		o := opt.FromAssert[fmt.Stringer](opt.Some("hello world"))
+		snaps.MatchSnapshot(t, o.Exists(), fmt.Sprint(o.Unwrap()))
+	})
+}
+
+func Test_Wrap(t *testing.T) {
+	for _, s := range []string{"42", "0", "forty-two"} {
+		t.Run(s, func(t *testing.T) {
+			o := opt.Wrap(strconv.Atoi(s))
+			snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+		})
+	}
+}
+
+func Test_StringOr(t *testing.T) {
+	snaps.MatchSnapshot(t,
+		opt.Some(42).StringOr("-"),
+		opt.Some("").StringOr("-"),
+		opt.None[int]().StringOr("-"),
+		opt.None[int]().StringOr(""),
+		opt.None[int]().String(),
+	)
+}
+
+func Test_IsZero(t *testing.T) {
+	snaps.MatchSnapshot(t,
+		opt.Some("hello world").IsZero(),
+		opt.Some(0).IsZero(),
+		opt.None[int]().IsZero(),
+	)
+}
+
+func Test_Or(t *testing.T) {
+	t.Run("Some", func(t *testing.T) {
+		o := opt.Some("request").Or(opt.Some("preference"))
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+
+	t.Run("Chain", func(t *testing.T) {
+		o := opt.None[string]().Or(opt.None[string]()).Or(opt.Some("default"))
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+
+	t.Run("None", func(t *testing.T) {
+		o := opt.None[string]().Or(opt.None[string]())
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap())
+	})
+}
+
+func Test_OrElse(t *testing.T) {
+	calls := 0
+	fallback := func() opt.Option[string] {
+		calls++
+		return opt.Some("default")
+	}
+
+	t.Run("Some", func(t *testing.T) {
+		calls = 0
+		o := opt.Some("request").OrElse(fallback)
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap(), calls)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		calls = 0
+		o := opt.None[string]().OrElse(fallback)
+		snaps.MatchSnapshot(t, o.Exists(), o.Unwrap(), calls)
+	})
+}
+
+func Test_IfPresent(t *testing.T) {
+	for n, o := range map[string]opt.Option[string]{
+		"Some": opt.Some("hello world"),
+		"None": opt.None[string](),
+	} {
+		t.Run(n, func(t *testing.T) {
+			var calls []string
+			result := o.
+				IfPresent(func(s string) { calls = append(calls, "present: "+s) }).
+				IfAbsent(func() { calls = append(calls, "absent") })
+			snaps.MatchSnapshot(t, calls, result.Exists(), result.Unwrap())
+		})
+	}
+}
@@ -0,0 +1,576 @@
+// Package optavro provides Avro encoding for structs holding Option fields using
+// github.com/hamba/avro.
+//
+// An Option[T] maps to the Avro union ["null", T] with a null default. An
+// Option that was not provided is written as null, and reading null leaves the
+// Option not provided.
+//
+// Field names are taken from the avro struct tag, falling back to the Go field
+// name as hamba/avro does. Values are decoded through encoding/json, so the
+// json struct tags of the target must name every field uniquely.
+package optavro
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/hamba/avro/v2"
+)
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
+)
+
+// Schema returns the Avro record schema for the struct type of v, which may be
+// a struct or a pointer to a struct.
+// Nested struct types become nested records named after the Go type.
+func Schema(v any) (schema avro.Schema, err error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("optavro: cannot generate a record schema for %v", t)
+	}
+
+	return SchemaOf(t)
+}
+
+// SchemaOf returns the Avro schema for the type t.
+func SchemaOf(t reflect.Type) (schema avro.Schema, err error) {
+	g := schemaGenerator{defined: map[reflect.Type]bool{}}
+
+	def, err := g.schemaOf(t)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(def)
+	if err != nil {
+		return nil, err
+	}
+
+	return avro.ParseBytesWithCache(data, "", &avro.SchemaCache{})
+}
+
+// schemaGenerator builds the JSON form of an Avro schema from Go types.
+type schemaGenerator struct {
+	// defined holds the struct types whose records have already been defined
+	// and must be referred to by name.
+	defined map[reflect.Type]bool
+}
+
+// schemaOf returns the JSON form of the Avro schema for t.
+func (g schemaGenerator) schemaOf(t reflect.Type) (def any, err error) {
+	if opt.IsOptionType(t) {
+		return g.nullable(opt.ElemType(t))
+	}
+
+	switch t {
+	case timeType:
+		return map[string]any{"type": "long", "logicalType": "timestamp-micros"}, nil
+	case durationType:
+		return map[string]any{"type": "long", "logicalType": "time-micros"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return "int", nil
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return "long", nil
+	case reflect.Float32:
+		return "float", nil
+	case reflect.Float64:
+		return "double", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Pointer:
+		return g.nullable(t.Elem())
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytes", nil
+		}
+
+		items, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			break
+		}
+
+		values, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "map", "values": values}, nil
+	case reflect.Struct:
+		return g.record(t)
+	}
+
+	return nil, fmt.Errorf("optavro: unsupported type %s", t)
+}
+
+// nullable returns the JSON form of the Avro union of null and the schema for
+// t.
+func (g schemaGenerator) nullable(t reflect.Type) (def any, err error) {
+	inner, err := g.schemaOf(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if union, ok := inner.([]any); ok {
+		// Nested Options and pointers collapse into a single union.
+		return union, nil
+	}
+
+	return []any{"null", inner}, nil
+}
+
+// record returns the JSON form of the Avro record schema for the struct type t.
+func (g schemaGenerator) record(t reflect.Type) (def any, err error) {
+	if t.Name() == "" {
+		return nil, fmt.Errorf("optavro: anonymous struct %s cannot be named as a record", t)
+	}
+
+	if g.defined[t] {
+		return t.Name(), nil
+	}
+	g.defined[t] = true
+
+	fields := []any{}
+	for _, f := range recordFields(t) {
+		fieldDef, err := g.schemaOf(f.typ)
+		if err != nil {
+			return nil, fmt.Errorf("%w in field %s.%s", err, t.Name(), f.goName)
+		}
+
+		field := map[string]any{"name": f.avroName, "type": fieldDef}
+		if _, ok := fieldDef.([]any); ok {
+			field["default"] = nil
+		}
+
+		fields = append(fields, field)
+	}
+
+	return map[string]any{"type": "record", "name": t.Name(), "fields": fields}, nil
+}
+
+// field describes a struct field mapped to a record field.
+type field struct {
+	// index is the index of the field in the struct.
+	index int
+
+	// typ is the type of the field.
+	typ reflect.Type
+
+	// goName is the Go name of the field.
+	goName string
+
+	// avroName is the name of the record field.
+	avroName string
+
+	// jsonName is the name encoding/json uses for the field.
+	jsonName string
+}
+
+// recordFields returns the exported fields of the struct type t.
+func recordFields(t reflect.Type) (fields []field) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		avroName := sf.Tag.Get("avro")
+		if avroName == "-" {
+			continue
+		}
+
+		if avroName == "" {
+			avroName = sf.Name
+		}
+
+		jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if jsonName == "" {
+			jsonName = sf.Name
+		}
+
+		fields = append(fields, field{
+			index:    i,
+			typ:      sf.Type,
+			goName:   sf.Name,
+			avroName: avroName,
+			jsonName: jsonName,
+		})
+	}
+
+	return fields
+}
+
+// Marshal returns the Avro encoding of v using schema.
+func Marshal(schema avro.Schema, v any) (data []byte, err error) {
+	native, err := toNative(reflect.ValueOf(v), schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return avro.Marshal(schema, native)
+}
+
+// Unmarshal decodes the Avro encoded data into v, which must be a pointer,
+// using schema.
+func Unmarshal(schema avro.Schema, data []byte, v any) (err error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("optavro: Unmarshal requires a non-nil pointer")
+	}
+
+	var native any
+	if err = avro.Unmarshal(schema, data, &native); err != nil {
+		return err
+	}
+
+	value, err := fromNative(native, schema, rv.Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, v)
+}
+
+// toNative converts v into the generic form hamba/avro encodes with schema.
+func toNative(v reflect.Value, schema avro.Schema) (native any, err error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		schema = ref.Schema()
+	}
+
+	t := v.Type()
+
+	if opt.IsOptionType(t) {
+		value, exists := opt.ValueOf(v)
+		if !exists {
+			return nil, nil
+		}
+
+		return toNativeUnion(value, schema)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		if schema.Type() == avro.Union {
+			return toNativeUnion(v.Elem(), schema)
+		}
+
+		return toNative(v.Elem(), schema)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		return toNative(v.Elem(), schema)
+	}
+
+	switch s := schema.(type) {
+	case *avro.UnionSchema:
+		return toNativeUnion(v, schema)
+	case *avro.RecordSchema:
+		if t.Kind() != reflect.Struct {
+			break
+		}
+
+		record := map[string]any{}
+		for _, f := range recordFields(t) {
+			fieldSchema := recordFieldSchema(s, f.avroName)
+			if fieldSchema == nil {
+				continue
+			}
+
+			if record[f.avroName], err = toNative(v.Field(f.index), fieldSchema); err != nil {
+				return nil, err
+			}
+		}
+
+		return record, nil
+	case *avro.ArraySchema:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			break
+		}
+
+		items := make([]any, v.Len())
+		for i := range v.Len() {
+			if items[i], err = toNative(v.Index(i), s.Items()); err != nil {
+				return nil, err
+			}
+		}
+
+		return items, nil
+	case *avro.MapSchema:
+		if t.Kind() != reflect.Map {
+			break
+		}
+
+		values := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			if values[iter.Key().String()], err = toNative(iter.Value(), s.Values()); err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+	case *avro.PrimitiveSchema:
+		return toNativePrimitive(v, s)
+	}
+
+	return nil, fmt.Errorf("optavro: cannot encode %s as Avro %s", t, schema.Type())
+}
+
+// toNativeUnion converts the non-null value v into the form hamba/avro encodes
+// with the nullable union schema.
+func toNativeUnion(v reflect.Value, schema avro.Schema) (native any, err error) {
+	branch, err := nonNullBranch(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.IsOptionType(v.Type()) || v.Kind() == reflect.Pointer {
+		// Nested Options and pointers share the union of the outer value.
+		return toNative(v, schema)
+	}
+
+	value, err := toNative(v, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{typeName(branch): value}, nil
+}
+
+// toNativePrimitive converts v into the Go type hamba/avro expects for the
+// primitive schema.
+func toNativePrimitive(v reflect.Value, schema *avro.PrimitiveSchema) (native any, err error) {
+	t := v.Type()
+
+	if t == timeType || t == durationType {
+		return v.Interface(), nil
+	}
+
+	switch schema.Type() {
+	case avro.Boolean:
+		if t.Kind() == reflect.Bool {
+			return v.Bool(), nil
+		}
+	case avro.Int:
+		switch {
+		case v.CanInt():
+			return int32(v.Int()), nil
+		case v.CanUint():
+			return int32(v.Uint()), nil
+		}
+	case avro.Long:
+		switch {
+		case v.CanInt():
+			return v.Int(), nil
+		case v.CanUint():
+			return int64(v.Uint()), nil
+		}
+	case avro.Float:
+		if v.CanFloat() {
+			return float32(v.Float()), nil
+		}
+	case avro.Double:
+		if v.CanFloat() {
+			return v.Float(), nil
+		}
+	case avro.String:
+		if t.Kind() == reflect.String {
+			return v.String(), nil
+		}
+	case avro.Bytes:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("optavro: cannot encode %s as Avro %s", t, schema.Type())
+}
+
+// fromNative converts native, as decoded by hamba/avro with schema, into a
+// value that encoding/json encodes in the form expected by the type t.
+// A nil result for a record field means the field is left out so that
+// Options are not provided.
+func fromNative(native any, schema avro.Schema, t reflect.Type) (value any, err error) {
+	if native == nil {
+		return nil, nil
+	}
+
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		schema = ref.Schema()
+	}
+
+	for opt.IsOptionType(t) || t.Kind() == reflect.Pointer {
+		if opt.IsOptionType(t) {
+			t = opt.ElemType(t)
+		} else {
+			t = t.Elem()
+		}
+	}
+
+	switch s := schema.(type) {
+	case *avro.UnionSchema:
+		branch, err := nonNullBranch(schema)
+		if err != nil {
+			return nil, err
+		}
+
+		// hamba/avro leaves values of complex branches wrapped in a map keyed
+		// by the branch name.
+		if wrapped, ok := native.(map[string]any); ok && len(wrapped) == 1 && isComplex(branch) {
+			if inner, ok := wrapped[typeName(branch)]; ok {
+				native = inner
+			}
+		}
+
+		return fromNative(native, branch, t)
+	case *avro.RecordSchema:
+		values, ok := native.(map[string]any)
+		if !ok || t.Kind() != reflect.Struct {
+			break
+		}
+
+		record := map[string]any{}
+		for _, f := range recordFields(t) {
+			fieldSchema := recordFieldSchema(s, f.avroName)
+			if fieldSchema == nil {
+				continue
+			}
+
+			fieldValue, err := fromNative(values[f.avroName], fieldSchema, f.typ)
+			if err != nil {
+				return nil, err
+			}
+
+			if fieldValue != nil {
+				record[f.jsonName] = fieldValue
+			}
+		}
+
+		return record, nil
+	case *avro.ArraySchema:
+		items, ok := native.([]any)
+		if !ok || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+			break
+		}
+
+		values := make([]any, len(items))
+		for i, item := range items {
+			if values[i], err = fromNative(item, s.Items(), t.Elem()); err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+	case *avro.MapSchema:
+		entries, ok := native.(map[string]any)
+		if !ok || t.Kind() != reflect.Map {
+			break
+		}
+
+		values := make(map[string]any, len(entries))
+		for k, entry := range entries {
+			if values[k], err = fromNative(entry, s.Values(), t.Elem()); err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+	default:
+		return native, nil
+	}
+
+	return nil, fmt.Errorf("optavro: cannot decode Avro %s into %s", schema.Type(), t)
+}
+
+// recordFieldSchema returns the schema of the field called name in the record
+// schema, or nil if the record has no such field.
+func recordFieldSchema(schema *avro.RecordSchema, name string) (fieldSchema avro.Schema) {
+	for _, f := range schema.Fields() {
+		if f.Name() == name {
+			return f.Type()
+		}
+	}
+
+	return nil
+}
+
+// nonNullBranch returns the schema of the branch of the nullable union schema
+// that is not null.
+func nonNullBranch(schema avro.Schema) (branch avro.Schema, err error) {
+	union, ok := schema.(*avro.UnionSchema)
+	if !ok || !union.Nullable() {
+		return nil, fmt.Errorf("optavro: Option requires a nullable union schema, got %s", schema.Type())
+	}
+
+	for _, s := range union.Types() {
+		if s.Type() != avro.Null {
+			return s, nil
+		}
+	}
+
+	return nil, fmt.Errorf("optavro: union %s has no non-null branch", schema)
+}
+
+// typeName returns the name hamba/avro uses for schema as a union branch.
+func typeName(schema avro.Schema) (name string) {
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		schema = ref.Schema()
+	}
+
+	if named, ok := schema.(avro.NamedSchema); ok {
+		return named.FullName()
+	}
+
+	name = string(schema.Type())
+	if lts, ok := schema.(avro.LogicalTypeSchema); ok && lts.Logical() != nil {
+		name += "." + string(lts.Logical().Type())
+	}
+
+	return name
+}
+
+// isComplex reports whether schema is a record, enum, fixed, array or map.
+func isComplex(schema avro.Schema) (ok bool) {
+	switch schema.Type() {
+	case avro.Record, avro.Ref, avro.Enum, avro.Fixed, avro.Array, avro.Map:
+		return true
+	}
+
+	return false
+}
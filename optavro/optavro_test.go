@@ -0,0 +1,134 @@
+package optavro_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optavro"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testVehicle struct {
+	Make  string `avro:"make" json:"make"`
+	Model string `avro:"model" json:"model"`
+}
+
+type testEvent struct {
+	ID        int64                        `avro:"id" json:"id"`
+	Name      opt.Option[string]           `avro:"name" json:"name"`
+	Count     opt.Option[int32]            `avro:"count" json:"count"`
+	Score     opt.Option[float64]          `avro:"score" json:"score"`
+	Active    opt.Option[bool]             `avro:"active" json:"active"`
+	Payload   opt.Option[[]byte]           `avro:"payload" json:"payload"`
+	Tags      opt.Option[[]string]         `avro:"tags" json:"tags"`
+	Labels    opt.Option[map[string]int64] `avro:"labels" json:"labels"`
+	Vehicle   opt.Option[testVehicle]      `avro:"vehicle" json:"vehicle"`
+	Previous  opt.Option[testVehicle]      `avro:"previous" json:"previous"`
+	Timestamp opt.Option[time.Time]        `avro:"timestamp" json:"timestamp"`
+	Pointer   *string                      `avro:"pointer" json:"pointer"`
+}
+
+func Test_Schema(t *testing.T) {
+	t.Run("Struct", func(t *testing.T) {
+		schema, err := optavro.Schema(testEvent{})
+		if err != nil {
+			t.Fatalf("Unexpected schema error: %s", err)
+		}
+
+		snaps.MatchSnapshot(t, schema.String())
+	})
+
+	t.Run("Pointer", func(t *testing.T) {
+		schema, err := optavro.Schema(&testVehicle{})
+		if err != nil {
+			t.Fatalf("Unexpected schema error: %s", err)
+		}
+
+		snaps.MatchSnapshot(t, schema.String())
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		type withChan struct {
+			Events opt.Option[chan int]
+		}
+
+		_, err := optavro.Schema(withChan{})
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		_, err := optavro.Schema("hello")
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+}
+
+func Test_RoundTrip(t *testing.T) {
+	schema, err := optavro.Schema(testEvent{})
+	if err != nil {
+		t.Fatalf("Unexpected schema error: %s", err)
+	}
+
+	pointer := "pointer"
+	cases := map[string]testEvent{
+		"Empty": {ID: 1},
+		"Full": {
+			ID:        2,
+			Name:      opt.Some("login"),
+			Count:     opt.Some(int32(3)),
+			Score:     opt.Some(0.5),
+			Active:    opt.Some(false),
+			Payload:   opt.Some([]byte("hello world")),
+			Tags:      opt.Some([]string{"a", "b"}),
+			Labels:    opt.Some(map[string]int64{"attempt": 2}),
+			Vehicle:   opt.Some(testVehicle{Make: "Toyota", Model: "Hilux"}),
+			Previous:  opt.Some(testVehicle{Make: "Ford"}),
+			Timestamp: opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)),
+			Pointer:   &pointer,
+		},
+		"Zero values": {
+			ID:     3,
+			Name:   opt.Some(""),
+			Count:  opt.Some(int32(0)),
+			Active: opt.Some(false),
+		},
+	}
+
+	for n, event := range cases {
+		t.Run(n, func(t *testing.T) {
+			data, err := optavro.Marshal(schema, event)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %s", err)
+			}
+
+			var decoded testEvent
+			if err = optavro.Unmarshal(schema, data, &decoded); err != nil {
+				t.Fatalf("Unexpected unmarshal error: %s", err)
+			}
+
+			snaps.MatchSnapshot(t, data, decoded)
+		})
+	}
+
+	t.Run("Pointer", func(t *testing.T) {
+		data, err := optavro.Marshal(schema, &testEvent{ID: 4, Name: opt.Some("pointer")})
+		if err != nil {
+			t.Fatalf("Unexpected marshal error: %s", err)
+		}
+
+		snaps.MatchSnapshot(t, data)
+	})
+
+	t.Run("Unmarshal non-pointer", func(t *testing.T) {
+		err := optavro.Unmarshal(schema, nil, testEvent{})
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+}
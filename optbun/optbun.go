@@ -0,0 +1,74 @@
+// Package optbun lets github.com/uptrace/bun models hold Option fields of any
+// type bun can store.
+//
+// opt.Option implements sql.Scanner and driver.Valuer, so Options of scalar
+// types can be model fields as they are, and an Option that was not provided
+// is written as NULL. Its IsZero method reports whether the value was not
+// provided, so on a field tagged with nullzero only an Option that was not
+// provided is written as NULL, while a provided zero value is written as is.
+//
+// The Option type of this package appends its value with the appender bun uses
+// for T, so Options of maps, structs and other types bun writes as JSON can be
+// model fields too.
+package optbun
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/fletcharoo/opt"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+)
+
+// Option wraps an opt.Option and implements schema.QueryAppender and
+// sql.Scanner in terms of the value's own bun representation.
+type Option[T any] struct {
+	opt.Option[T]
+}
+
+// Wrap returns o as an Option.
+func Wrap[T any](o opt.Option[T]) (wrapped Option[T]) {
+	return Option[T]{Option: o}
+}
+
+// AppendQuery implements the schema.QueryAppender interface.
+// If the value is provided, AppendQuery appends the value as bun would append
+// a T.
+// If the value is not provided, AppendQuery appends NULL.
+func (o Option[T]) AppendQuery(fmter schema.Formatter, b []byte) (appended []byte, err error) {
+	if !o.Exists() {
+		return dialect.AppendNull(b), nil
+	}
+
+	value := o.Unwrap()
+	return fmter.AppendValue(b, reflect.ValueOf(&value).Elem()), nil
+}
+
+// Scan implements the sql.Scanner interface.
+// If the column is NULL, the value is not set and Scan returns nil.
+// Otherwise Scan scans the column as opt.Option does, and failing that
+// decodes it as JSON, the form bun writes maps and structs in.
+func (o *Option[T]) Scan(src any) (err error) {
+	if err = o.Option.Scan(src); err == nil {
+		return nil
+	}
+
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return err
+	}
+
+	var value T
+	if err = json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	o.Option = opt.Some(value)
+	return nil
+}
@@ -0,0 +1,95 @@
+package optbun_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optbun"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+// nopConnector lets bun build queries without a database.
+type nopConnector struct{}
+
+func (nopConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("no database")
+}
+
+func (nopConnector) Driver() driver.Driver { return nil }
+
+type testPrefs struct {
+	Theme string `json:"theme"`
+}
+
+type testUser struct {
+	bun.BaseModel `bun:"table:users"`
+
+	ID       int64                    `bun:"id,pk"`
+	Name     opt.Option[string]       `bun:"name"`
+	Age      opt.Option[int]          `bun:"age,nullzero"`
+	Prefs    optbun.Option[testPrefs] `bun:"prefs"`
+	Tags     optbun.Option[[]string]  `bun:"tags"`
+	Nickname string                   `bun:"nickname,nullzero"`
+}
+
+func newDB() *bun.DB {
+	return bun.NewDB(sql.OpenDB(nopConnector{}), sqlitedialect.New())
+}
+
+func Test_Insert(t *testing.T) {
+	t.Run("Provided", func(t *testing.T) {
+		user := testUser{
+			ID:    1,
+			Name:  opt.Some(""),
+			Age:   opt.Some(0),
+			Prefs: optbun.Wrap(opt.Some(testPrefs{Theme: "dark"})),
+			Tags:  optbun.Wrap(opt.Some([]string{"a", "b"})),
+		}
+
+		snaps.MatchSnapshot(t, newDB().NewInsert().Model(&user).String())
+	})
+
+	t.Run("Not provided", func(t *testing.T) {
+		snaps.MatchSnapshot(t, newDB().NewInsert().Model(&testUser{ID: 1}).String())
+	})
+}
+
+func Test_Scan(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		var o optbun.Option[testPrefs]
+		err := o.Scan([]byte(`{"theme":"dark"}`))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o.Option)
+	})
+
+	t.Run("NULL", func(t *testing.T) {
+		o := optbun.Wrap(opt.Some([]string{"stale"}))
+		err := o.Scan(nil)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o.Option)
+	})
+
+	t.Run("Scalar", func(t *testing.T) {
+		var o optbun.Option[int]
+		err := o.Scan(int64(42))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o.Option)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		var o optbun.Option[testPrefs]
+		err := o.Scan("not json")
+		snaps.MatchSnapshot(t, err != nil, o.Option)
+	})
+}
@@ -0,0 +1,196 @@
+// Package optcaarlosenv parses environment variables into structs with Option
+// fields using github.com/caarlos0/env, recording which variables were set:
+//
+//	type Config struct {
+//		Name    opt.Option[string]        `env:"NAME"`
+//		Port    opt.Option[int]           `env:"PORT"`
+//		Timeout opt.Option[time.Duration] `env:"TIMEOUT"`
+//	}
+//
+//	var config Config
+//	err := optcaarlosenv.Parse(&config)
+//
+// An Option is not provided if its variable is unset, and is provided if it is
+// set, even to the empty string, which env itself does not tell apart from an
+// unset variable.
+package optcaarlosenv
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/fletcharoo/opt"
+)
+
+// emptyValue stands in for the empty values of variables during the pass that
+// finds Options set to the empty string, as env skips empty values.
+const emptyValue = "\x00optcaarlosenv:empty\x00"
+
+// Parse parses the environment into the struct v points to, as env.Parse does,
+// populating its Option fields as ParseWithOptions does.
+func Parse(v any) (err error) {
+	return ParseWithOptions(v, env.Options{})
+}
+
+// ParseWithOptions parses the environment into the struct v points to, as
+// env.ParseWithOptions does, populating its Option fields as well.
+// A variable that is set populates its Option with the value converted to T:
+// by a parser of opts.FuncMap for T if there is one, and otherwise as
+// opt.DecodeHook converts strings, parsing durations with time.ParseDuration
+// and splitting slices on commas.
+// A variable that is set to the empty string populates its Option only if the
+// empty string converts to T, such as for strings, so that an empty variable
+// does not fail the parsing of an Option[int].
+// A variable that is unset leaves its Option untouched, unless it has a
+// default given by the envDefault tag.
+func ParseWithOptions(v any, opts env.Options) (err error) {
+	if opts.Environment == nil {
+		opts.Environment = env.ToMap(os.Environ())
+	}
+
+	types := map[reflect.Type]bool{}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		optionTypes(types, rv.Type().Elem())
+	}
+
+	funcMap := make(map[reflect.Type]env.ParserFunc, len(opts.FuncMap)+len(types))
+	for t, parse := range opts.FuncMap {
+		funcMap[t] = parse
+	}
+
+	for t := range types {
+		funcMap[t] = optionParser(t, opts.FuncMap[opt.ElemType(t)])
+	}
+
+	// The first pass parses the variables that are not empty. The second
+	// parses the environment again into a copy of v, with the empty values
+	// replaced by emptyValue so that env hands them over to the parsers of
+	// Options, and copies the Options it populated into v.
+	empty := opts
+	empty.Environment = make(map[string]string, len(opts.Environment))
+	empty.OnSet = nil
+	for k, value := range opts.Environment {
+		if value == "" {
+			value = emptyValue
+		}
+
+		empty.Environment[k] = value
+	}
+
+	opts.FuncMap = funcMap
+	if err = env.ParseWithOptions(v, opts); err != nil {
+		return err
+	}
+
+	empty.FuncMap = funcMap
+	rv := reflect.ValueOf(v).Elem()
+	scratch := reflect.New(rv.Type())
+
+	// Errors are reported by the first pass, and the second only fails for
+	// fields that are not Options, which emptyValue does not suit.
+	_ = env.ParseWithOptions(scratch.Interface(), empty)
+
+	copyOptions(rv, scratch.Elem())
+	return nil
+}
+
+// optionTypes adds the Option types of the fields of the struct type t, and of
+// the structs it nests, to types.
+func optionTypes(types map[reflect.Type]bool, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || opt.IsOptionType(t) {
+		return
+	}
+
+	for i := range t.NumField() {
+		ft := t.Field(i).Type
+		if opt.IsOptionType(ft) {
+			types[ft] = true
+			continue
+		}
+
+		if !types[ft] {
+			optionTypes(types, ft)
+		}
+	}
+}
+
+// optionParser returns the env.ParserFunc of the Option type t, converting
+// values with parse if it is not nil.
+func optionParser(t reflect.Type, parse env.ParserFunc) (parser env.ParserFunc) {
+	hook := opt.DecodeHook()
+	elem := opt.ElemType(t)
+
+	return func(s string) (value any, err error) {
+		empty := s == emptyValue
+		if empty {
+			s = ""
+		}
+
+		o, err := convert(hook, t, elem, s, parse)
+		if err != nil && empty {
+			return reflect.Zero(t).Interface(), nil
+		}
+
+		return o, err
+	}
+}
+
+// convert returns the Option of type t holding s converted to elem.
+func convert(hook func(from, to reflect.Value) (any, error), t, elem reflect.Type, s string, parse env.ParserFunc) (o any, err error) {
+	var from any = s
+	switch {
+	case parse != nil:
+		if from, err = parse(s); err != nil {
+			return nil, err
+		}
+	case elem == reflect.TypeFor[time.Duration]():
+		if from, err = time.ParseDuration(s); err != nil {
+			return nil, err
+		}
+	case elem.Kind() == reflect.Slice && elem.Elem().Kind() != reflect.Uint8:
+		from = []string{}
+		if s != "" {
+			from = strings.Split(s, ",")
+		}
+	}
+
+	return hook(reflect.ValueOf(from), reflect.New(t).Elem())
+}
+
+// copyOptions copies the Options provided in src into the Options of dst
+// that are not provided, walking the structs both hold.
+func copyOptions(dst, src reflect.Value) {
+	for i := range dst.NumField() {
+		df, sf := dst.Field(i), src.Field(i)
+		if !df.CanSet() {
+			continue
+		}
+
+		if opt.IsOptionType(df.Type()) {
+			if _, exists := opt.ValueOf(df); exists {
+				continue
+			}
+
+			if _, exists := opt.ValueOf(sf); exists {
+				df.Set(sf)
+			}
+
+			continue
+		}
+
+		for df.Kind() == reflect.Pointer && sf.Kind() == reflect.Pointer && !df.IsNil() && !sf.IsNil() {
+			df, sf = df.Elem(), sf.Elem()
+		}
+
+		if df.Kind() == reflect.Struct {
+			copyOptions(df, sf)
+		}
+	}
+}
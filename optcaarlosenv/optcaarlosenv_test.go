@@ -0,0 +1,103 @@
+package optcaarlosenv_test
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optcaarlosenv"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testDatabase struct {
+	Host opt.Option[string] `env:"HOST"`
+	Port opt.Option[int]    `env:"PORT"`
+}
+
+type testConfig struct {
+	Name     opt.Option[string]        `env:"NAME"`
+	Port     opt.Option[int]           `env:"PORT"`
+	Debug    opt.Option[bool]          `env:"DEBUG"`
+	Timeout  opt.Option[time.Duration] `env:"TIMEOUT"`
+	Tags     opt.Option[[]string]      `env:"TAGS"`
+	Region   opt.Option[string]        `env:"REGION" envDefault:"eu"`
+	Nick     opt.Tracked[string]       `env:"NICK"`
+	Database testDatabase              `envPrefix:"DB_"`
+	Replica  *testDatabase             `envPrefix:"REPLICA_"`
+	Workers  int                       `env:"WORKERS"`
+}
+
+func Test_ParseWithOptions(t *testing.T) {
+	testCases := map[string]map[string]string{
+		"Unset": {},
+		"Empty": {
+			"NAME":    "",
+			"PORT":    "",
+			"TAGS":    "",
+			"REGION":  "",
+			"NICK":    "",
+			"DB_HOST": "",
+			"WORKERS": "",
+		},
+		"Values": {
+			"NAME":         "service",
+			"PORT":         "0",
+			"DEBUG":        "false",
+			"TIMEOUT":      "5s",
+			"TAGS":         "a,b",
+			"REGION":       "us",
+			"NICK":         "ada",
+			"DB_HOST":      "localhost",
+			"DB_PORT":      "5432",
+			"REPLICA_HOST": "replica",
+			"WORKERS":      "4",
+		},
+	}
+
+	for n, environment := range testCases {
+		t.Run(n, func(t *testing.T) {
+			config := testConfig{Replica: &testDatabase{}}
+			err := optcaarlosenv.ParseWithOptions(&config, env.Options{Environment: environment})
+			snaps.MatchSnapshot(t, config, err)
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		var config testConfig
+		err := optcaarlosenv.ParseWithOptions(&config, env.Options{Environment: map[string]string{"PORT": "eighty"}})
+		snaps.MatchSnapshot(t, err != nil)
+	})
+
+	t.Run("FuncMap", func(t *testing.T) {
+		var config testConfig
+		err := optcaarlosenv.ParseWithOptions(&config, env.Options{
+			Environment: map[string]string{"PORT": "0x50"},
+			FuncMap: map[reflect.Type]env.ParserFunc{
+				reflect.TypeFor[int](): func(v string) (any, error) {
+					i, err := strconv.ParseInt(v, 0, 0)
+					return int(i), err
+				},
+			},
+		})
+		snaps.MatchSnapshot(t, config.Port, err)
+	})
+}
+
+func Test_Parse(t *testing.T) {
+	t.Setenv("NAME", "")
+	t.Setenv("PORT", "8080")
+
+	var config testConfig
+	err := optcaarlosenv.Parse(&config)
+	snaps.MatchSnapshot(t, config.Name, config.Port, config.Debug, err)
+}
@@ -0,0 +1,22 @@
+module github.com/fletcharoo/opt/optcli
+
+go 1.23.2
+
+require (
+	github.com/fletcharoo/opt v0.1.0
+	github.com/gkampitakis/go-snaps v0.5.7
+	github.com/urfave/cli/v3 v3.11.0
+)
+
+require (
+	github.com/gkampitakis/ciinfo v0.3.0 // indirect
+	github.com/gkampitakis/go-diff v1.3.2 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/maruel/natural v1.1.1 // indirect
+	github.com/rogpeppe/go-internal v1.12.0 // indirect
+	github.com/tidwall/gjson v1.17.0 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.1 // indirect
+	github.com/tidwall/sjson v1.2.5 // indirect
+)
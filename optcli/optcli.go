@@ -0,0 +1,130 @@
+// Package optcli defines flags of github.com/urfave/cli/v3 backed by Options,
+// so that an Option is provided exactly when its flag was given on the command
+// line or by one of its sources, such as an environment variable:
+//
+//	var port opt.Option[int]
+//
+//	cmd := &cli.Command{
+//		Flags: []cli.Flag{
+//			optcli.Flag("port", &port, "port to listen on"),
+//		},
+//	}
+//
+// For github.com/urfave/cli/v2, whose GenericFlag takes any flag.Value, use
+// Target:
+//
+//	&cli.GenericFlag{Name: "port", Value: optcli.Target(&port)}
+package optcli
+
+import (
+	"encoding"
+	"reflect"
+
+	"github.com/fletcharoo/opt"
+	"github.com/urfave/cli/v3"
+)
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// Value implements flag.Value and flag.Getter for an opt.Option, setting the
+// Option when its flag is given. A *Value points at the Option it binds, as
+// returned by Target, so setting the flag fills in that Option.
+type Value[T any] struct {
+	// option is the Option set by the flag.
+	option *opt.Option[T]
+
+	// changed indicates whether the flag was given, so that the values of a
+	// slice flag given again are appended rather than replacing them.
+	changed bool
+}
+
+// Target returns a *Value binding o, for use as a flag.Value that fills in o
+// directly.
+func Target[T any](o *opt.Option[T]) (target *Value[T]) {
+	return &Value[T]{option: o}
+}
+
+// Option returns the Option v binds.
+func (v *Value[T]) Option() (unwrapped opt.Option[T]) {
+	return *v.option
+}
+
+// String implements the flag.Value interface.
+// If the value is provided, String returns it formatted as by fmt.Sprint.
+// If the value is not provided, String returns "".
+func (v *Value[T]) String() (str string) {
+	if v == nil || v.option == nil {
+		return ""
+	}
+
+	return v.Option().StringOr("")
+}
+
+// Set implements the flag.Value interface, parsing s into T as opt.ParseFlag
+// does and marking the value as provided.
+// When T is a slice split on commas by opt.ParseFlag, the values of a flag
+// given more than once are appended, as in the slice flags of cli, so that
+// --tags a --tags b sets []string{"a", "b"}. The first value given replaces
+// any default.
+func (v *Value[T]) Set(s string) (err error) {
+	o, err := opt.ParseFlag[T](s)
+	if err != nil {
+		return err
+	}
+
+	if v.changed && isSliceType(reflect.TypeFor[T]()) {
+		appended := reflect.AppendSlice(reflect.ValueOf(v.option.Unwrap()), reflect.ValueOf(o.Unwrap()))
+		o = opt.Some(appended.Interface().(T))
+	}
+
+	*v.option = o
+	v.changed = true
+	return nil
+}
+
+// Get implements the flag.Getter interface, returning the opt.Option.
+func (v *Value[T]) Get() (value any) {
+	return v.Option()
+}
+
+// IsBoolFlag reports whether T is a boolean, so that the flag of an
+// Option[bool] can be given without a value, as --name, to set it to true.
+func (v *Value[T]) IsBoolFlag() (ok bool) {
+	return reflect.TypeFor[T]().Kind() == reflect.Bool
+}
+
+// isSliceType reports whether t is a slice that opt.ParseFlag splits on
+// commas, that is a slice other than a byte slice that does not implement
+// encoding.TextUnmarshaler.
+func isSliceType(t reflect.Type) (ok bool) {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 &&
+		!reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// Creator is the cli.ValueCreator of the flags returned by Flag.
+type Creator[T any] struct{}
+
+// Create implements the cli.ValueCreator interface, setting the Option p
+// points to to val and returning it as a *Value.
+func (Creator[T]) Create(val opt.Option[T], p *opt.Option[T], _ cli.NoConfig) (value cli.Value) {
+	*p = val
+	return Target(p)
+}
+
+// ToString implements the cli.ValueCreator interface.
+func (Creator[T]) ToString(val opt.Option[T]) (str string) {
+	return val.StringOr("")
+}
+
+// Flag returns a flag with the specified name and usage that sets the Option
+// dst points to when it is given. Further settings, such as aliases, sources
+// or the category, can be set on the returned flag.
+// The Option is set to None before the flag is parsed, unless the Value of the
+// returned flag is set to a default.
+func Flag[T any](name string, dst *opt.Option[T], usage string) (flag *cli.FlagBase[opt.Option[T], cli.NoConfig, Creator[T]]) {
+	return &cli.FlagBase[opt.Option[T], cli.NoConfig, Creator[T]]{
+		Name:        name,
+		Usage:       usage,
+		Destination: dst,
+	}
+}
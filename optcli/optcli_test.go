@@ -0,0 +1,113 @@
+package optcli_test
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optcli"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/urfave/cli/v3"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testFlags struct {
+	Name    opt.Option[string]
+	Port    opt.Option[int]
+	Debug   opt.Option[bool]
+	Timeout opt.Option[time.Duration]
+	Tags    opt.Option[[]string]
+	Backoff opt.Option[[]time.Duration]
+	Region  opt.Option[string]
+}
+
+// run runs a command defining the fields of flags with args.
+func run(flags *testFlags, args ...string) (err error) {
+	region := optcli.Flag("region", &flags.Region, "region to deploy to")
+	region.Sources = cli.EnvVars("OPTCLI_REGION")
+
+	cmd := &cli.Command{
+		Name:      "test",
+		Writer:    io.Discard,
+		ErrWriter: io.Discard,
+		Flags: []cli.Flag{
+			optcli.Flag("name", &flags.Name, "name of the service"),
+			optcli.Flag("port", &flags.Port, "port to listen on"),
+			optcli.Flag("debug", &flags.Debug, "enable debugging"),
+			optcli.Flag("timeout", &flags.Timeout, "request timeout"),
+			optcli.Flag("tags", &flags.Tags, "tags to apply"),
+			optcli.Flag("backoff", &flags.Backoff, "delays between retries"),
+			region,
+		},
+		Action: func(context.Context, *cli.Command) error { return nil },
+	}
+
+	return cmd.Run(context.Background(), append([]string{"test"}, args...))
+}
+
+func Test_Flag(t *testing.T) {
+	testCases := map[string][]string{
+		"None":        {},
+		"Zero values": {"--name", "", "--port=0", "--debug=false"},
+		"Values": {
+			"--name", "service",
+			"--port", "8080",
+			"--debug",
+			"--timeout", "1m30s",
+			"--tags", "a,b",
+			"--backoff", "1s,2s",
+		},
+		"Repeated": {
+			"--tags", "a",
+			"--tags", "b,c",
+			"--port", "8080",
+			"--port", "9090",
+		},
+		"Invalid": {"--port", "eighty"},
+	}
+
+	for n, args := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var flags testFlags
+			err := run(&flags, args...)
+			snaps.MatchSnapshot(t, flags, err != nil)
+		})
+	}
+
+	t.Run("Environment", func(t *testing.T) {
+		t.Setenv("OPTCLI_REGION", "eu")
+
+		var flags testFlags
+		err := run(&flags)
+		snaps.MatchSnapshot(t, flags.Region, err)
+	})
+}
+
+func Test_Target(t *testing.T) {
+	var port opt.Option[int]
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(optcli.Target(&port), "port", "port to listen on")
+
+	err := fs.Parse([]string{"-port", "8080"})
+	snaps.MatchSnapshot(t, port, err)
+}
+
+func Test_Target_Repeated(t *testing.T) {
+	var tags opt.Option[[]string]
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(optcli.Target(&tags), "tags", "tags to apply")
+
+	err := fs.Parse([]string{"-tags", "a", "-tags", "b"})
+	snaps.MatchSnapshot(t, tags, err)
+}
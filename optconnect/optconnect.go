@@ -0,0 +1,109 @@
+// Package optconnect lets connectrpc.com/connect services exchange
+// hand-written message types holding Option fields as JSON.
+//
+// Connect's own JSON codec only accepts generated protobuf messages. Codec
+// encodes other values with opt.Marshal and opt.Unmarshal, so that absent
+// Options are left out or sent as null and decode back to None, and hands
+// protobuf messages to protojson as Connect does. Register it on handlers and
+// clients with WithCodec:
+//
+//	handler := connect.NewUnaryHandler(
+//		"/users.v1.UserService/UpdateUser", updateUser, optconnect.WithCodec(),
+//	)
+//
+//	client := connect.NewClient[updateUserRequest, user](
+//		http.DefaultClient, url+"/users.v1.UserService/UpdateUser",
+//		optconnect.WithCodec(),
+//	)
+package optconnect
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"github.com/fletcharoo/opt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec is a connect.Codec encoding messages as JSON. Protobuf messages are
+// encoded with protojson, and any other value with opt.Marshal and
+// opt.Unmarshal.
+type Codec struct {
+	name string
+}
+
+// NewCodec returns a Codec registered under name, which is the content
+// subtype it serves, such as "json".
+func NewCodec(name string) (c *Codec) {
+	return &Codec{name: name}
+}
+
+// WithCodec returns a connect.Option replacing Connect's JSON codecs with
+// Codecs. Handlers use them for both the "json" and "json; charset=utf-8"
+// content subtypes, and clients send "json".
+func WithCodec() (o connect.Option) {
+	return connect.WithOptions(
+		connect.WithCodec(NewCodec("json; charset=utf-8")),
+		connect.WithCodec(NewCodec("json")),
+	)
+}
+
+// Name returns the content subtype c is registered under.
+func (c *Codec) Name() (name string) {
+	return c.name
+}
+
+// Marshal returns the JSON encoding of message.
+func (c *Codec) Marshal(message any) (data []byte, err error) {
+	if m, ok := message.(proto.Message); ok {
+		return protojson.Marshal(m)
+	}
+
+	return opt.Marshal(message)
+}
+
+// MarshalStable returns the JSON encoding of message, compacted so that equal
+// messages always encode to the same bytes. Connect uses it for requests sent
+// with HTTP GET.
+func (c *Codec) MarshalStable(message any) (data []byte, err error) {
+	if data, err = c.Marshal(message); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err = json.Compact(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the JSON encoding data into message. Unknown fields of
+// protobuf messages are discarded, as Connect does, so that clients and
+// servers may run different versions of a schema.
+func (c *Codec) Unmarshal(data []byte, message any) (err error) {
+	if len(data) == 0 {
+		return errors.New("optconnect: zero-length payload is not a valid JSON object")
+	}
+
+	if m, ok := message.(proto.Message); ok {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, m)
+	} else {
+		err = opt.Unmarshal(data, message)
+	}
+
+	if err != nil {
+		return fmt.Errorf("optconnect: unmarshal into %T: %w", message, err)
+	}
+
+	return nil
+}
+
+// IsBinary reports false, as JSON is text.
+func (c *Codec) IsBinary() (binary bool) {
+	return false
+}
@@ -0,0 +1,126 @@
+package optconnect_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optconnect"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testUpdateRequest struct {
+	ID    int                  `json:"id"`
+	Name  opt.Option[string]   `json:"name"`
+	Email opt.Option[string]   `json:"email,omitempty"`
+	Tags  opt.Option[[]string] `json:"tags"`
+}
+
+const testProcedure = "/users.v1.UserService/UpdateUser"
+
+// newHandler returns a handler whose UpdateUser procedure echoes its request.
+func newHandler() (h http.Handler) {
+	update := func(_ context.Context, req *connect.Request[testUpdateRequest]) (*connect.Response[testUpdateRequest], error) {
+		return connect.NewResponse(req.Msg), nil
+	}
+
+	return connect.NewUnaryHandler(testProcedure, update, optconnect.WithCodec())
+}
+
+// serve starts a server for newHandler, and returns a client calling it.
+func serve(t *testing.T, options ...connect.ClientOption) (client *connect.Client[testUpdateRequest, testUpdateRequest]) {
+	srv := httptest.NewServer(newHandler())
+	t.Cleanup(srv.Close)
+
+	options = append([]connect.ClientOption{optconnect.WithCodec()}, options...)
+	return connect.NewClient[testUpdateRequest, testUpdateRequest](srv.Client(), srv.URL+testProcedure, options...)
+}
+
+func Test_Codec_RoundTrip(t *testing.T) {
+	testCases := map[string]testUpdateRequest{
+		"None":  {ID: 1},
+		"Empty": {ID: 2, Name: opt.Some(""), Email: opt.Some(""), Tags: opt.Some([]string{})},
+		"Values": {
+			ID:    3,
+			Name:  opt.Some("ada"),
+			Email: opt.Some("ada@example.com"),
+			Tags:  opt.Some([]string{"admin"}),
+		},
+	}
+
+	for n, req := range testCases {
+		t.Run(n, func(t *testing.T) {
+			for _, get := range []bool{false, true} {
+				client := serve(t, connect.WithHTTPGet())
+				if !get {
+					client = serve(t)
+				}
+
+				res, err := client.CallUnary(context.Background(), connect.NewRequest(&req))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				snaps.MatchSnapshot(t, res.Msg)
+			}
+		})
+	}
+}
+
+func Test_Codec_Server(t *testing.T) {
+	h := newHandler()
+
+	for _, contentType := range []string{"application/json", "application/json; charset=utf-8"} {
+		t.Run(contentType, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, testProcedure, strings.NewReader(`{"id": 4, "name": null}`))
+			r.Header.Set("Content-Type", contentType)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			snaps.MatchSnapshot(t, w.Code, w.Body.String())
+		})
+	}
+}
+
+func Test_Codec(t *testing.T) {
+	c := optconnect.NewCodec("json")
+
+	t.Run("Name", func(t *testing.T) {
+		snaps.MatchSnapshot(t, c.Name(), c.IsBinary())
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		data, err := c.MarshalStable(testUpdateRequest{ID: 5, Email: opt.Some("ada@example.com")})
+		snaps.MatchSnapshot(t, string(data), err)
+	})
+
+	t.Run("Proto", func(t *testing.T) {
+		data, err := c.MarshalStable(&sourcecontextpb.SourceContext{FileName: "users.proto"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got sourcecontextpb.SourceContext
+		err = c.Unmarshal([]byte(`{"fileName": "users.proto", "unknown": 1}`), &got)
+		snaps.MatchSnapshot(t, string(data), got.GetFileName(), err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		var got testUpdateRequest
+		for _, data := range []string{"", `{"tags": "admin"}`} {
+			snaps.MatchSnapshot(t, c.Unmarshal([]byte(data), &got).Error())
+		}
+	})
+}
@@ -0,0 +1,66 @@
+// Package optcql provides gocql marshaling for Option so Option values can be
+// bound to and scanned from nullable Cassandra columns.
+//
+// Cassandra distinguishes a column that is written as NULL, which creates a
+// tombstone, from a column that is left unset by a statement. An Option that
+// was not provided marshals as NULL, so bind values with Value to leave the
+// column unset instead.
+package optcql
+
+import (
+	"github.com/fletcharoo/opt"
+	"github.com/gocql/gocql"
+)
+
+// Option wraps an opt.Option and implements gocql.Marshaler and
+// gocql.Unmarshaler.
+type Option[T any] struct {
+	opt.Option[T]
+}
+
+// Wrap returns o as an Option.
+func Wrap[T any](o opt.Option[T]) (wrapped Option[T]) {
+	return Option[T]{Option: o}
+}
+
+// Value returns the value to bind for o in a gocql query.
+// If the value is provided, Value returns the value.
+// If the value is not provided, Value returns gocql.UnsetValue so that the
+// column is left unset rather than written as NULL.
+func Value[T any](o opt.Option[T]) (value any) {
+	if !o.Exists() {
+		return gocql.UnsetValue
+	}
+
+	return o.Unwrap()
+}
+
+// MarshalCQL implements the gocql.Marshaler interface.
+// If the value is provided, MarshalCQL marshals the value.
+// If the value is not provided, MarshalCQL returns nil, which gocql writes as
+// NULL.
+func (o Option[T]) MarshalCQL(info gocql.TypeInfo) (data []byte, err error) {
+	if !o.Exists() {
+		return nil, nil
+	}
+
+	return gocql.Marshal(info, o.Unwrap())
+}
+
+// UnmarshalCQL implements the gocql.Unmarshaler interface.
+// If the column is NULL, the value is not set and UnmarshalCQL returns nil.
+// Otherwise UnmarshalCQL unmarshals the value and sets exists to true.
+func (o *Option[T]) UnmarshalCQL(info gocql.TypeInfo, data []byte) (err error) {
+	if data == nil {
+		o.Option = opt.None[T]()
+		return nil
+	}
+
+	var value T
+	if err = gocql.Unmarshal(info, data, &value); err != nil {
+		return err
+	}
+
+	o.Option = opt.Some(value)
+	return nil
+}
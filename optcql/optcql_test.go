@@ -0,0 +1,93 @@
+package optcql_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optcql"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/gocql/gocql"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+var (
+	textType   = gocql.NewNativeType(4, gocql.TypeText, "")
+	bigIntType = gocql.NewNativeType(4, gocql.TypeBigInt, "")
+)
+
+func Test_MarshalCQL(t *testing.T) {
+	t.Run("Some", func(t *testing.T) {
+		data, err := optcql.Wrap(opt.Some("hello world")).MarshalCQL(textType)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), data)
+	})
+
+	t.Run("Some empty", func(t *testing.T) {
+		data, err := optcql.Wrap(opt.Some("")).MarshalCQL(textType)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), data, data == nil)
+	})
+
+	t.Run("Some int", func(t *testing.T) {
+		data, err := optcql.Wrap(opt.Some(int64(42))).MarshalCQL(bigIntType)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), data)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		data, err := optcql.Wrap(opt.None[string]()).MarshalCQL(textType)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), data == nil)
+	})
+
+	t.Run("Type mismatch", func(t *testing.T) {
+		_, err := optcql.Wrap(opt.Some(true)).MarshalCQL(bigIntType)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Through gocql", func(t *testing.T) {
+		data, err := gocql.Marshal(textType, optcql.Wrap(opt.Some("hello world")))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), data)
+	})
+}
+
+func Test_UnmarshalCQL(t *testing.T) {
+	t.Run("Value", func(t *testing.T) {
+		var o optcql.Option[string]
+		err := gocql.Unmarshal(textType, []byte("hello world"), &o)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o.Exists(), o.Unwrap())
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		var o optcql.Option[string]
+		err := gocql.Unmarshal(textType, []byte{}, &o)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o.Exists(), o.Unwrap())
+	})
+
+	t.Run("Null", func(t *testing.T) {
+		o := optcql.Wrap(opt.Some("stale"))
+		err := gocql.Unmarshal(textType, nil, &o)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o.Exists())
+	})
+
+	t.Run("Int", func(t *testing.T) {
+		data, _ := gocql.Marshal(bigIntType, int64(42))
+
+		var o optcql.Option[int64]
+		err := gocql.Unmarshal(bigIntType, data, &o)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o.Exists(), o.Unwrap())
+	})
+}
+
+func Test_Value(t *testing.T) {
+	t.Run("Some", func(t *testing.T) {
+		snaps.MatchSnapshot(t, optcql.Value(opt.Some("hello world")))
+	})
+
+	t.Run("None", func(t *testing.T) {
+		snaps.MatchSnapshot(t, optcql.Value(opt.None[string]()) == gocql.UnsetValue)
+	})
+}
@@ -0,0 +1,79 @@
+// Package optecho lets github.com/labstack/echo bind Option fields.
+//
+// Options bind from JSON bodies through their UnmarshalJSON method, and from
+// query parameters, form values and path parameters through their
+// UnmarshalParam and UnmarshalParams methods, with echo's DefaultBinder.
+// Echo reports a path parameter that matched no text, such as the id of
+// /users//posts routed to /users/:id/posts, as an empty value, which binds to
+// Some("") or fails to convert. Binder leaves such parameters out so that they
+// bind to None:
+//
+//	e := echo.New()
+//	e.Binder = &optecho.Binder{}
+package optecho
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// Binder is an echo.Binder that binds like echo.DefaultBinder, except that
+// empty path parameters are treated as missing.
+type Binder struct {
+	echo.DefaultBinder
+}
+
+// Bind binds the path parameters, query parameters and body of the request of
+// c into i as echo.DefaultBinder does, leaving out empty path parameters.
+func (b *Binder) Bind(i any, c echo.Context) (err error) {
+	return b.DefaultBinder.Bind(i, withoutEmptyParams(c))
+}
+
+// BindPathParams binds the path parameters of c into i as
+// echo.DefaultBinder does, leaving out empty path parameters.
+func (b *Binder) BindPathParams(c echo.Context, i any) (err error) {
+	return b.DefaultBinder.BindPathParams(withoutEmptyParams(c), i)
+}
+
+// paramContext is an echo.Context reporting a filtered set of path parameters.
+type paramContext struct {
+	echo.Context
+	names  []string
+	values []string
+}
+
+// ParamNames returns the names of the path parameters kept.
+func (c *paramContext) ParamNames() (names []string) {
+	return c.names
+}
+
+// ParamValues returns the values of the path parameters kept.
+func (c *paramContext) ParamValues() (values []string) {
+	return c.values
+}
+
+// Param returns the value of the path parameter name, or "" if it was not
+// kept.
+func (c *paramContext) Param(name string) (value string) {
+	for i, n := range c.names {
+		if n == name {
+			return c.values[i]
+		}
+	}
+
+	return ""
+}
+
+// withoutEmptyParams returns c reporting only its non-empty path parameters.
+func withoutEmptyParams(c echo.Context) (filtered echo.Context) {
+	names, values := c.ParamNames(), c.ParamValues()
+
+	pc := &paramContext{Context: c}
+	for i, name := range names {
+		if i < len(values) && values[i] != "" {
+			pc.names = append(pc.names, name)
+			pc.values = append(pc.values, values[i])
+		}
+	}
+
+	return pc
+}
@@ -0,0 +1,76 @@
+package optecho_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optecho"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/labstack/echo/v4"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testRequest struct {
+	ID     opt.Option[int]      `param:"id" query:"id"`
+	Name   opt.Option[string]   `query:"name" json:"name"`
+	Tags   opt.Option[[]string] `query:"tag"`
+	Active opt.Option[bool]     `json:"active"`
+}
+
+// bind serves a request to target with body through a route binding
+// testRequest with binder, returning what was bound.
+func bind(binder echo.Binder, method string, target string, body string) (req testRequest, err error) {
+	e := echo.New()
+	e.Binder = binder
+
+	handler := func(c echo.Context) error {
+		err = c.Bind(&req)
+		return nil
+	}
+
+	e.Add(method, "/users/:id/posts", handler)
+
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+
+	e.ServeHTTP(httptest.NewRecorder(), r)
+	return req, err
+}
+
+func Test_Binder(t *testing.T) {
+	testCases := map[string]struct {
+		method string
+		target string
+		body   string
+	}{
+		"Path":          {method: http.MethodGet, target: "/users/42/posts"},
+		"Empty path":    {method: http.MethodGet, target: "/users//posts"},
+		"Query":         {method: http.MethodGet, target: "/users/42/posts?name=&tag=a&tag=b"},
+		"Invalid path":  {method: http.MethodGet, target: "/users/me/posts"},
+		"Body":          {method: http.MethodPost, target: "/users/42/posts", body: `{"name": "ada", "active": false}`},
+		"Body and path": {method: http.MethodPost, target: "/users//posts", body: `{"active": true}`},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			req, err := bind(&optecho.Binder{}, tc.method, tc.target, tc.body)
+			snaps.MatchSnapshot(t, req, err != nil)
+		})
+	}
+
+	t.Run("DefaultBinder empty path", func(t *testing.T) {
+		req, err := bind(&echo.DefaultBinder{}, http.MethodGet, "/users//posts", "")
+		snaps.MatchSnapshot(t, req, err != nil)
+	})
+}
@@ -0,0 +1,56 @@
+// Package optenv looks up environment variables as Options, so that
+// configuration read from the environment composes with the other sources of
+// a program:
+//
+//	port := opt.Coalesce(flagPort, optenv.Int("PORT")).UnwrapDefault(8080)
+//
+// A variable that is set, even to the empty string, is provided, and one that
+// is unset is not. The typed lookups also leave out values that do not parse.
+package optenv
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fletcharoo/opt"
+)
+
+// Get returns the value of the environment variable name.
+// If the variable is set, Get returns Some of its value, even if it is empty.
+// If the variable is unset, Get returns None.
+func Get(name string) (o opt.Option[string]) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return opt.None[string]()
+	}
+
+	return opt.Some(value)
+}
+
+// Int returns the value of the environment variable name parsed as a decimal
+// int by strconv.Atoi.
+// If the variable is unset, or does not parse, Int returns None.
+func Int(name string) (o opt.Option[int]) {
+	return opt.FlatMap(Get(name), func(value string) opt.Option[int] {
+		return opt.Wrap(strconv.Atoi(value))
+	})
+}
+
+// Bool returns the value of the environment variable name parsed as a boolean
+// by strconv.ParseBool, which accepts values such as "1", "true" and "FALSE".
+// If the variable is unset, or does not parse, Bool returns None.
+func Bool(name string) (o opt.Option[bool]) {
+	return opt.FlatMap(Get(name), func(value string) opt.Option[bool] {
+		return opt.Wrap(strconv.ParseBool(value))
+	})
+}
+
+// Duration returns the value of the environment variable name parsed as a
+// duration by time.ParseDuration, such as "1m30s".
+// If the variable is unset, or does not parse, Duration returns None.
+func Duration(name string) (o opt.Option[time.Duration]) {
+	return opt.FlatMap(Get(name), func(value string) opt.Option[time.Duration] {
+		return opt.Wrap(time.ParseDuration(value))
+	})
+}
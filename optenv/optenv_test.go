@@ -0,0 +1,52 @@
+package optenv_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fletcharoo/opt/optenv"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+// setenv sets the environment variable OPTENV_VALUE to value, or unsets it if
+// value is nil, for the duration of t.
+func setenv(t *testing.T, value *string) {
+	t.Setenv("OPTENV_VALUE", "")
+	if value == nil {
+		os.Unsetenv("OPTENV_VALUE")
+		return
+	}
+
+	t.Setenv("OPTENV_VALUE", *value)
+}
+
+func Test_Lookups(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+
+	testCases := map[string]*string{
+		"Unset":    nil,
+		"Empty":    ptr(""),
+		"Int":      ptr("42"),
+		"Bool":     ptr("true"),
+		"Duration": ptr("1m30s"),
+		"Text":     ptr("hello world"),
+	}
+
+	for n, value := range testCases {
+		t.Run(n, func(t *testing.T) {
+			setenv(t, value)
+			snaps.MatchSnapshot(t,
+				optenv.Get("OPTENV_VALUE"),
+				optenv.Int("OPTENV_VALUE"),
+				optenv.Bool("OPTENV_VALUE"),
+				optenv.Duration("OPTENV_VALUE"),
+			)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Package optform lets github.com/go-playground/form decode form posts into
+// Option fields and encode Option fields into form values, preserving whether
+// each optional input was submitted:
+//
+//	decoder := form.NewDecoder()
+//	optform.RegisterDecoder[string](decoder)
+//	optform.RegisterDecoder[int](decoder)
+//
+// form looks custom type funcs up by exact type, so they are registered for
+// each Option[T] a program uses.
+package optform
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+
+	"github.com/fletcharoo/opt"
+	"github.com/go-playground/form/v4"
+)
+
+// RegisterDecoder makes d decode Option[T] fields.
+// An Option whose input is submitted is provided, and one whose input is
+// missing is left untouched. Values are converted as opt.DecodeQuery converts
+// them, so slices receive every value of their input.
+// A submitted empty value provides the Option only if the empty string
+// converts to T, such as for strings, so that an empty number input leaves an
+// Option[int] unprovided rather than failing, as form does for an int.
+func RegisterDecoder[T any](d *form.Decoder) {
+	d.RegisterCustomTypeFunc(decode[T], opt.Option[T]{})
+}
+
+// RegisterEncoder makes e encode Option[T] fields.
+// If the value is provided, it is encoded as its text form: the text of an
+// encoding.TextMarshaler, or the value formatted as by fmt.Sprint, with every
+// element of a slice encoded as a value of the input.
+// If the value is not provided, the input is left out.
+func RegisterEncoder[T any](e *form.Encoder) {
+	e.RegisterCustomTypeFunc(encode[T], opt.Option[T]{})
+}
+
+// decode implements the form.DecodeCustomTypeFunc of Option[T].
+func decode[T any](values []string) (value any, err error) {
+	var dst struct {
+		Value opt.Option[T] `query:"value"`
+	}
+
+	err = opt.DecodeQuery(url.Values{"value": values}, &dst)
+	if err != nil {
+		if len(values) == 1 && values[0] == "" {
+			// Returning nil leaves the field alone.
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return dst.Value, nil
+}
+
+// encode implements the form.EncodeCustomTypeFunc of Option[T].
+func encode[T any](x any) (values []string, err error) {
+	value, exists := x.(opt.Option[T]).AnyValue()
+	if !exists {
+		return nil, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		s, err := format(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{s}, nil
+	}
+
+	values = make([]string, rv.Len())
+	for i := range values {
+		if values[i], err = format(rv.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// format returns the text form of v.
+func format(v any) (s string, err error) {
+	switch v := v.(type) {
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return "", err
+		}
+
+		return string(text), nil
+	case []byte:
+		return string(v), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
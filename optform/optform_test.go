@@ -0,0 +1,84 @@
+package optform_test
+
+import (
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optform"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/go-playground/form/v4"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testForm struct {
+	Name  opt.Option[string]   `form:"name"`
+	Age   opt.Option[int]      `form:"age"`
+	Admin opt.Option[bool]     `form:"admin"`
+	Tags  opt.Option[[]string] `form:"tags"`
+}
+
+func Test_RegisterDecoder(t *testing.T) {
+	d := form.NewDecoder()
+	optform.RegisterDecoder[string](d)
+	optform.RegisterDecoder[int](d)
+	optform.RegisterDecoder[bool](d)
+	optform.RegisterDecoder[[]string](d)
+
+	testCases := map[string]url.Values{
+		"None":        {},
+		"Empty":       {"name": {""}, "age": {""}},
+		"Zero values": {"name": {""}, "age": {"0"}, "admin": {"false"}},
+		"Values": {
+			"name":  {"Ada"},
+			"age":   {"36"},
+			"admin": {"true"},
+			"tags":  {"a", "b"},
+		},
+		"Invalid": {"age": {"thirty"}},
+	}
+
+	for n, values := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var dst testForm
+			err := d.Decode(&dst, values)
+			snaps.MatchSnapshot(t, dst, err != nil)
+		})
+	}
+}
+
+func Test_RegisterEncoder(t *testing.T) {
+	e := form.NewEncoder()
+	optform.RegisterEncoder[string](e)
+	optform.RegisterEncoder[int](e)
+	optform.RegisterEncoder[bool](e)
+	optform.RegisterEncoder[[]string](e)
+
+	testCases := map[string]testForm{
+		"None": {},
+		"Zero values": {
+			Name:  opt.Some(""),
+			Age:   opt.Some(0),
+			Admin: opt.Some(false),
+		},
+		"Values": {
+			Name:  opt.Some("Ada"),
+			Age:   opt.Some(36),
+			Admin: opt.Some(true),
+			Tags:  opt.Some([]string{"a", "b"}),
+		},
+	}
+
+	for n, src := range testCases {
+		t.Run(n, func(t *testing.T) {
+			values, err := e.Encode(src)
+			snaps.MatchSnapshot(t, values.Encode(), err)
+		})
+	}
+}
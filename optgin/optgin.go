@@ -0,0 +1,64 @@
+// Package optgin lets github.com/gin-gonic/gin bind and validate Option
+// fields.
+//
+// Options bind from JSON bodies through their UnmarshalJSON method, and from
+// query parameters, form values and URI parameters through their
+// UnmarshalParam method, with no setup. Validation needs the Option types to
+// be registered with gin's validator once, at startup:
+//
+//	err := optgin.Register(opt.Option[string]{}, opt.Option[int]{})
+//
+// after which binding tags apply to the values held by Options:
+//
+//	type listUsers struct {
+//		Name  opt.Option[string] `form:"name" binding:"required"`
+//		Limit opt.Option[int]    `form:"limit" binding:"omitempty,min=1,max=100"`
+//	}
+package optgin
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Register registers CustomTypeFunc for the Option types of the values types
+// with the validator used by gin's binding.Validator.
+// If binding.Validator is not backed by a *validator.Validate, Register
+// returns an error.
+func Register(types ...any) (err error) {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("optgin: binding.Validator engine %T is not a *validator.Validate", binding.Validator.Engine())
+	}
+
+	RegisterValidate(v, types...)
+	return nil
+}
+
+// RegisterValidate registers CustomTypeFunc for the Option types of the values
+// types with v.
+func RegisterValidate(v *validator.Validate, types ...any) {
+	v.RegisterCustomTypeFunc(CustomTypeFunc, types...)
+}
+
+// CustomTypeFunc is a validator.CustomTypeFunc that validates the value held
+// by an Option rather than the Option itself.
+// If the value is provided, CustomTypeFunc returns a pointer to a copy of it,
+// so that required passes even for a provided zero value and other tags
+// validate the value.
+// If the value is not provided, CustomTypeFunc returns nil, so that required
+// fails and omitempty skips the field.
+func CustomTypeFunc(field reflect.Value) (value any) {
+	v, exists := opt.ValueOf(field)
+	if !exists {
+		return nil
+	}
+
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	return ptr.Interface()
+}
@@ -0,0 +1,77 @@
+package optgin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optgin"
+	"github.com/gin-gonic/gin"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	err := optgin.Register(opt.Option[string]{}, opt.Option[int]{}, opt.Option[[]string]{})
+	if err != nil {
+		panic(err)
+	}
+
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testRequest struct {
+	Name  opt.Option[string]   `json:"name" form:"name" binding:"required"`
+	Limit opt.Option[int]      `json:"limit" form:"limit" binding:"omitempty,min=0,max=100"`
+	Tags  opt.Option[[]string] `json:"tags" form:"tags" binding:"omitempty,max=2"`
+}
+
+// testContext returns a gin context for a request to target with body.
+func testContext(method string, target string, body string) (c *gin.Context) {
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c
+}
+
+func Test_ShouldBindJSON(t *testing.T) {
+	testCases := map[string]string{
+		"Missing required": `{"limit": 10}`,
+		"Zero values":      `{"name": "", "limit": 0}`,
+		"Values":           `{"name": "ada", "limit": 10, "tags": ["a", "b"]}`,
+		"Out of range":     `{"name": "ada", "limit": 1000}`,
+		"Too many tags":    `{"name": "ada", "tags": ["a", "b", "c"]}`,
+	}
+
+	for n, body := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var req testRequest
+			err := testContext(http.MethodPost, "/", body).ShouldBindJSON(&req)
+			snaps.MatchSnapshot(t, req, err != nil)
+		})
+	}
+}
+
+func Test_ShouldBindQuery(t *testing.T) {
+	testCases := map[string]string{
+		"Missing required": "limit=10",
+		"Empty values":     "name=&limit=0",
+		"Values":           "name=ada&limit=10",
+		"Out of range":     "name=ada&limit=1000",
+		"Invalid":          "name=ada&limit=ten",
+	}
+
+	for n, query := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var req testRequest
+			err := testContext(http.MethodGet, "/?"+query, "").ShouldBindQuery(&req)
+			snaps.MatchSnapshot(t, req, err != nil)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package optgorm provides GORM support for Option fields beyond the
+// database/sql interfaces that opt.Option implements itself.
+//
+// Options of types a database driver cannot store, such as slices, maps and
+// structs, can be stored as JSON with the serializer this package registers
+// under the name "opt":
+//
+//	Tags opt.Option[[]string] `gorm:"serializer:opt"`
+//
+// An Option that was not provided is a zero struct, so Updates skips it the
+// way it skips any other zero field, while a provided zero value is written.
+// Option fields of the Option type of this package are written as DEFAULT
+// when they are not provided, so Create leaves such columns to their
+// database defaults rather than writing NULL.
+package optgorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/fletcharoo/opt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("opt", Serializer{})
+}
+
+// Serializer is a GORM serializer for Option fields. Values a database driver
+// can store are written as they are, other values are written as JSON, and an
+// Option that was not provided is written as NULL.
+type Serializer struct{}
+
+// Value implements the schema.SerializerValuerInterface interface.
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue any) (value any, err error) {
+	valuer, ok := fieldValue.(driver.Valuer)
+	if !ok {
+		return nil, fmt.Errorf("optgorm: value of field %s is a %T, not an Option", field.Name, fieldValue)
+	}
+
+	if value, err = valuer.Value(); err == nil {
+		return value, nil
+	}
+
+	data, err := json.Marshal(fieldValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Scan implements the schema.SerializerInterface interface.
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) (err error) {
+	target := reflect.New(field.FieldType)
+
+	scanner, ok := target.Interface().(sql.Scanner)
+	if !ok {
+		return fmt.Errorf("optgorm: field %s is a %s, not an Option", field.Name, field.FieldType)
+	}
+
+	if err = scanner.Scan(dbValue); err != nil {
+		var data []byte
+		switch v := dbValue.(type) {
+		case []byte:
+			data = v
+		case string:
+			data = []byte(v)
+		default:
+			return err
+		}
+
+		if err = json.Unmarshal(data, target.Interface()); err != nil {
+			return err
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(target.Elem())
+	return nil
+}
+
+// Option wraps an opt.Option and implements the gorm.Valuer interface so that
+// an Option that was not provided is written as DEFAULT.
+// Not every database accepts DEFAULT in every statement; SQLite, for one,
+// does not.
+type Option[T any] struct {
+	opt.Option[T]
+}
+
+// Wrap returns o as an Option.
+func Wrap[T any](o opt.Option[T]) (wrapped Option[T]) {
+	return Option[T]{Option: o}
+}
+
+// GormValue implements the gorm.Valuer interface.
+// If the value is provided, GormValue binds the value.
+// If the value is not provided, GormValue returns DEFAULT.
+func (o Option[T]) GormValue(ctx context.Context, db *gorm.DB) (expr clause.Expr) {
+	if !o.Exists() {
+		return clause.Expr{SQL: "DEFAULT"}
+	}
+
+	return clause.Expr{SQL: "?", Vars: []any{o.Option}}
+}
@@ -0,0 +1,143 @@
+package optgorm_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optgorm"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils/tests"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testUser struct {
+	ID     uint
+	Name   opt.Option[string]
+	Age    opt.Option[int]
+	Tags   opt.Option[[]string]       `gorm:"serializer:opt"`
+	Prefs  opt.Option[map[string]int] `gorm:"serializer:opt"`
+	Role   optgorm.Option[string]
+	Active opt.Option[bool]
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("Unexpected open error: %s", err)
+	}
+
+	return db
+}
+
+// statement returns the SQL and variables of a dry run statement.
+func statement(db *gorm.DB) (stmt []string) {
+	stmt = append(stmt, db.Statement.SQL.String())
+	for _, v := range db.Statement.Vars {
+		// Serialized fields are bound as valuers running the serializer.
+		if valuer, ok := v.(driver.Valuer); ok {
+			v, _ = valuer.Value()
+		}
+
+		stmt = append(stmt, fmt.Sprintf("%T %v", v, v))
+	}
+
+	return stmt
+}
+
+func Test_Schema(t *testing.T) {
+	s, err := schema.Parse(&testUser{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %s", err)
+	}
+
+	dataTypes := map[string]string{}
+	for _, f := range s.Fields {
+		dataTypes[f.Name] = string(f.DataType)
+	}
+
+	snaps.MatchSnapshot(t, dataTypes)
+}
+
+func Test_Create(t *testing.T) {
+	t.Run("Provided", func(t *testing.T) {
+		user := testUser{
+			ID:     1,
+			Name:   opt.Some("Ada"),
+			Age:    opt.Some(0),
+			Tags:   opt.Some([]string{"a", "b"}),
+			Prefs:  opt.Some(map[string]int{"x": 1}),
+			Role:   optgorm.Wrap(opt.Some("admin")),
+			Active: opt.Some(false),
+		}
+
+		snaps.MatchSnapshot(t, statement(newDB(t).Create(&user)))
+	})
+
+	t.Run("Not provided", func(t *testing.T) {
+		snaps.MatchSnapshot(t, statement(newDB(t).Create(&testUser{ID: 1})))
+	})
+}
+
+func Test_Updates(t *testing.T) {
+	t.Run("Skips absent fields", func(t *testing.T) {
+		user := testUser{ID: 1, Age: opt.Some(0), Active: opt.Some(false)}
+		snaps.MatchSnapshot(t, statement(newDB(t).Model(&testUser{ID: 1}).Updates(user)))
+	})
+}
+
+func Test_Serializer(t *testing.T) {
+	s, err := schema.Parse(&testUser{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %s", err)
+	}
+
+	tags := s.LookUpField("Tags")
+	name := s.LookUpField("Name")
+
+	t.Run("Scan JSON", func(t *testing.T) {
+		var user testUser
+		err := optgorm.Serializer{}.Scan(context.Background(), tags, reflectValue(&user), []byte(`["a","b"]`))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), user.Tags)
+	})
+
+	t.Run("Scan NULL", func(t *testing.T) {
+		user := testUser{Tags: opt.Some([]string{"stale"})}
+		err := optgorm.Serializer{}.Scan(context.Background(), tags, reflectValue(&user), nil)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), user.Tags)
+	})
+
+	t.Run("Scan native", func(t *testing.T) {
+		var user testUser
+		err := optgorm.Serializer{}.Scan(context.Background(), name, reflectValue(&user), "Ada")
+		snaps.MatchSnapshot(t, fmt.Sprint(err), user.Name)
+	})
+
+	t.Run("Scan invalid", func(t *testing.T) {
+		var user testUser
+		err := optgorm.Serializer{}.Scan(context.Background(), tags, reflectValue(&user), "not json")
+		snaps.MatchSnapshot(t, err != nil, user.Tags)
+	})
+
+	t.Run("Value not an Option", func(t *testing.T) {
+		_, err := optgorm.Serializer{}.Value(context.Background(), name, reflectValue(&testUser{}), "Ada")
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+}
+
+// reflectValue returns the reflect.Value GORM hands serializers for a model.
+func reflectValue(model any) reflect.Value {
+	return reflect.ValueOf(model).Elem()
+}
@@ -0,0 +1,121 @@
+// Package optgqlgen provides the marshal and unmarshal functions that
+// github.com/99designs/gqlgen binds GraphQL scalars to, so generated models use
+// Options rather than pointers for nullable fields. Listing this package as a
+// model of a scalar in gqlgen.yml lets fields of that scalar be Options, with
+// gqlgen choosing the model matching the Go type of each field:
+//
+//	models:
+//	  String:
+//	    model:
+//	      - github.com/99designs/gqlgen/graphql.String
+//	      - github.com/fletcharoo/opt/optgqlgen.String
+//
+// Other scalars are bound by writing their own function pairs with Marshal and
+// Unmarshal:
+//
+//	func MarshalUUID(o opt.Option[uuid.UUID]) graphql.Marshaler {
+//		return optgqlgen.Marshal(o, graphql.MarshalUUID)
+//	}
+//
+//	func UnmarshalUUID(v any) (opt.Option[uuid.UUID], error) {
+//		return optgqlgen.Unmarshal(v, graphql.UnmarshalUUID)
+//	}
+//
+// gqlgen leaves input fields that were not given untouched, so they stay None,
+// and null unmarshals to None as well. Fields that need to tell the two apart
+// can use graphql.Omittable[opt.Option[T]].
+package optgqlgen
+
+import (
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/fletcharoo/opt"
+)
+
+// Marshal returns the marshaler of the value of o given by marshal.
+// If the value is not provided, Marshal returns graphql.Null.
+func Marshal[T any](o opt.Option[T], marshal func(T) graphql.Marshaler) (m graphql.Marshaler) {
+	value, exists := o.AnyValue()
+	if !exists {
+		return graphql.Null
+	}
+
+	return marshal(value.(T))
+}
+
+// Unmarshal returns an Option holding v unmarshaled by unmarshal.
+// If v is nil, Unmarshal returns None.
+// If unmarshal returns an error, Unmarshal returns None and the error.
+func Unmarshal[T any](v any, unmarshal func(any) (T, error)) (o opt.Option[T], err error) {
+	if v == nil {
+		return opt.None[T](), nil
+	}
+
+	value, err := unmarshal(v)
+	if err != nil {
+		return opt.None[T](), err
+	}
+
+	return opt.Some(value), nil
+}
+
+// MarshalString marshals an Option of the String scalar.
+func MarshalString(o opt.Option[string]) (m graphql.Marshaler) {
+	return Marshal(o, graphql.MarshalString)
+}
+
+// UnmarshalString unmarshals an Option of the String scalar.
+func UnmarshalString(v any) (o opt.Option[string], err error) {
+	return Unmarshal(v, graphql.UnmarshalString)
+}
+
+// MarshalInt marshals an Option of the Int scalar.
+func MarshalInt(o opt.Option[int]) (m graphql.Marshaler) {
+	return Marshal(o, graphql.MarshalInt)
+}
+
+// UnmarshalInt unmarshals an Option of the Int scalar.
+func UnmarshalInt(v any) (o opt.Option[int], err error) {
+	return Unmarshal(v, graphql.UnmarshalInt)
+}
+
+// MarshalFloat marshals an Option of the Float scalar.
+func MarshalFloat(o opt.Option[float64]) (m graphql.Marshaler) {
+	return Marshal(o, graphql.MarshalFloat)
+}
+
+// UnmarshalFloat unmarshals an Option of the Float scalar.
+func UnmarshalFloat(v any) (o opt.Option[float64], err error) {
+	return Unmarshal(v, graphql.UnmarshalFloat)
+}
+
+// MarshalBoolean marshals an Option of the Boolean scalar.
+func MarshalBoolean(o opt.Option[bool]) (m graphql.Marshaler) {
+	return Marshal(o, graphql.MarshalBoolean)
+}
+
+// UnmarshalBoolean unmarshals an Option of the Boolean scalar.
+func UnmarshalBoolean(v any) (o opt.Option[bool], err error) {
+	return Unmarshal(v, graphql.UnmarshalBoolean)
+}
+
+// MarshalID marshals an Option of the ID scalar.
+func MarshalID(o opt.Option[string]) (m graphql.Marshaler) {
+	return Marshal(o, graphql.MarshalID)
+}
+
+// UnmarshalID unmarshals an Option of the ID scalar.
+func UnmarshalID(v any) (o opt.Option[string], err error) {
+	return Unmarshal(v, graphql.UnmarshalID)
+}
+
+// MarshalTime marshals an Option of the Time scalar.
+func MarshalTime(o opt.Option[time.Time]) (m graphql.Marshaler) {
+	return Marshal(o, graphql.MarshalTime)
+}
+
+// UnmarshalTime unmarshals an Option of the Time scalar.
+func UnmarshalTime(v any) (o opt.Option[time.Time], err error) {
+	return Unmarshal(v, graphql.UnmarshalTime)
+}
@@ -0,0 +1,66 @@
+package optgqlgen_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optgqlgen"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+// marshaled returns the JSON written by m.
+func marshaled(m graphql.Marshaler) (s string) {
+	var buf bytes.Buffer
+	m.MarshalGQL(&buf)
+	return buf.String()
+}
+
+func Test_Marshal(t *testing.T) {
+	testCases := map[string]graphql.Marshaler{
+		"String":       optgqlgen.MarshalString(opt.Some("ada")),
+		"Empty string": optgqlgen.MarshalString(opt.Some("")),
+		"None":         optgqlgen.MarshalString(opt.None[string]()),
+		"Int":          optgqlgen.MarshalInt(opt.Some(0)),
+		"Float":        optgqlgen.MarshalFloat(opt.Some(1.5)),
+		"Boolean":      optgqlgen.MarshalBoolean(opt.Some(false)),
+		"ID":           optgqlgen.MarshalID(opt.Some("42")),
+		"Time":         optgqlgen.MarshalTime(opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))),
+	}
+
+	for n, m := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, marshaled(m))
+		})
+	}
+}
+
+func Test_Unmarshal(t *testing.T) {
+	testCases := map[string]func() (any, error){
+		"String":      func() (any, error) { return optgqlgen.UnmarshalString("ada") },
+		"Null":        func() (any, error) { return optgqlgen.UnmarshalString(nil) },
+		"Int":         func() (any, error) { return optgqlgen.UnmarshalInt(json.Number("0")) },
+		"Invalid int": func() (any, error) { return optgqlgen.UnmarshalInt("zero") },
+		"Float":       func() (any, error) { return optgqlgen.UnmarshalFloat(1.5) },
+		"Boolean":     func() (any, error) { return optgqlgen.UnmarshalBoolean(false) },
+		"ID":          func() (any, error) { return optgqlgen.UnmarshalID(int64(42)) },
+		"Time":        func() (any, error) { return optgqlgen.UnmarshalTime("2024-01-02T03:04:05Z") },
+	}
+
+	for n, fn := range testCases {
+		t.Run(n, func(t *testing.T) {
+			o, err := fn()
+			snaps.MatchSnapshot(t, o, err != nil)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Package optjsonschema generates JSON Schemas for types holding Option fields
+// using github.com/invopop/jsonschema.
+//
+// An Option[T] is described by the schema of T, as its JSONSchemaAlias method
+// tells the reflector. Reflect and ReflectFromType additionally describe the
+// value of an Option field, slice element or map value as either that schema or
+// null, and leave Option fields out of the required properties, since an
+// Option that was not provided is left out when marshaling:
+//
+//	type updateUser struct {
+//		ID   int                `json:"id"`
+//		Name opt.Option[string] `json:"name"`
+//	}
+//
+//	schema := optjsonschema.Reflect(&jsonschema.Reflector{}, updateUser{})
+//
+// describes name as {"oneOf": [{"type": "string"}, {"type": "null"}]} and
+// requires only id.
+package optjsonschema
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/fletcharoo/opt"
+	"github.com/invopop/jsonschema"
+)
+
+// Reflect returns the JSON Schema r reflects from the type of v, with Option
+// values nullable and Option fields not required.
+func Reflect(r *jsonschema.Reflector, v any) (s *jsonschema.Schema) {
+	return ReflectFromType(r, reflect.TypeOf(v))
+}
+
+// ReflectFromType returns the JSON Schema r reflects from t, with Option values
+// nullable and Option fields not required.
+func ReflectFromType(r *jsonschema.Reflector, t reflect.Type) (s *jsonschema.Schema) {
+	s = r.ReflectFromType(t)
+
+	n := nullifier{r: r, defs: s.Definitions, done: map[*jsonschema.Schema]bool{}}
+	n.schema(s, t)
+
+	return s
+}
+
+// nullifier walks a reflected schema along with the type it was reflected
+// from, making Option values nullable.
+type nullifier struct {
+	// r is the reflector the schema was reflected with.
+	r *jsonschema.Reflector
+
+	// defs holds the definitions of the schema, which references are
+	// resolved against.
+	defs jsonschema.Definitions
+
+	// done holds the schemas already walked, so that definitions referenced
+	// several times are changed once.
+	done map[*jsonschema.Schema]bool
+}
+
+// value returns the schema s of a value of type t, made nullable if t is an
+// Option type.
+func (n nullifier) value(s *jsonschema.Schema, t reflect.Type) (value *jsonschema.Schema) {
+	n.schema(s, t)
+
+	if !opt.IsOptionType(t) || isNullable(s) {
+		return s
+	}
+
+	return &jsonschema.Schema{OneOf: []*jsonschema.Schema{s, {Type: "null"}}}
+}
+
+// schema makes the Option values described within s, the schema of t,
+// nullable.
+func (n nullifier) schema(s *jsonschema.Schema, t reflect.Type) {
+	for opt.IsOptionType(t) || t.Kind() == reflect.Pointer {
+		if opt.IsOptionType(t) {
+			t = opt.ElemType(t)
+		} else {
+			t = t.Elem()
+		}
+	}
+
+	if s == nil {
+		return
+	}
+
+	if s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+		if s = n.defs[name]; !ok || s == nil {
+			return
+		}
+	}
+
+	if n.done[s] {
+		return
+	}
+	n.done[s] = true
+
+	switch t.Kind() {
+	case reflect.Struct:
+		n.fields(s, t)
+	case reflect.Slice, reflect.Array:
+		if s.Items != nil {
+			s.Items = n.value(s.Items, t.Elem())
+		}
+	case reflect.Map:
+		if s.AdditionalProperties != nil {
+			s.AdditionalProperties = n.value(s.AdditionalProperties, t.Elem())
+		}
+
+		for pattern, p := range s.PatternProperties {
+			s.PatternProperties[pattern] = n.value(p, t.Elem())
+		}
+	}
+}
+
+// fields makes the properties of s, the schema of the struct type t, that
+// describe Option fields nullable and not required.
+func (n nullifier) fields(s *jsonschema.Schema, t reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		name, embedded := n.fieldName(f)
+		if embedded {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			n.fields(s, ft)
+			continue
+		}
+
+		if name == "" || s.Properties == nil {
+			continue
+		}
+
+		p, ok := s.Properties.Get(name)
+		if !ok {
+			continue
+		}
+
+		s.Properties.Set(name, n.value(p, f.Type))
+
+		if opt.IsOptionType(f.Type) {
+			s.Required = slices.DeleteFunc(s.Required, func(required string) bool {
+				return required == name
+			})
+		}
+	}
+}
+
+// fieldName returns the name of the property r reflects from the struct field
+// f, or whether the fields of f are reflected in place of f, as
+// github.com/invopop/jsonschema does. An empty name means f is not reflected.
+func (n nullifier) fieldName(f reflect.StructField) (name string, embedded bool) {
+	tagKey := n.r.FieldNameTag
+	if tagKey == "" {
+		tagKey = "json"
+	}
+
+	tags := strings.Split(f.Tag.Get(tagKey), ",")
+	if tags[0] == "-" || strings.Split(f.Tag.Get("jsonschema"), ",")[0] == "-" {
+		return "", false
+	}
+
+	ft := f.Type
+	if ft.Kind() == reflect.Pointer {
+		ft = ft.Elem()
+	}
+
+	if f.Anonymous && tags[0] == "" && ft.Kind() == reflect.Struct {
+		return "", true
+	}
+
+	if slices.Contains(tags[1:], "inline") {
+		return "", true
+	}
+
+	if !f.Anonymous && !f.IsExported() {
+		return "", false
+	}
+
+	name = f.Name
+	if tags[0] != "" {
+		name = tags[0]
+	}
+
+	if n.r.KeyNamer != nil {
+		name = n.r.KeyNamer(name)
+	}
+
+	return name, false
+}
+
+// isNullable reports whether s already allows null as one of its branches.
+func isNullable(s *jsonschema.Schema) (ok bool) {
+	return slices.ContainsFunc(s.OneOf, func(branch *jsonschema.Schema) bool {
+		return branch.Type == "null"
+	})
+}
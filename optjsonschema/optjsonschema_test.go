@@ -0,0 +1,82 @@
+package optjsonschema_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optjsonschema"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/invopop/jsonschema"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testAddress struct {
+	Street   string             `json:"street"`
+	Postcode opt.Option[string] `json:"postcode" jsonschema:"pattern=^[0-9]{4}$"`
+}
+
+type testAudit struct {
+	UpdatedBy opt.Option[string] `json:"updated_by"`
+}
+
+type testUser struct {
+	testAudit
+
+	ID       int                           `json:"id"`
+	Name     opt.Option[string]            `json:"name" jsonschema:"description=The display name"`
+	Email    opt.Option[string]            `json:"email,omitempty"`
+	Nickname opt.Option[string]            `json:"nickname" jsonschema:"nullable"`
+	Born     opt.Option[time.Time]         `json:"born"`
+	Home     opt.Option[testAddress]       `json:"home"`
+	Work     *testAddress                  `json:"work"`
+	Tags     []opt.Option[string]          `json:"tags"`
+	Scores   map[string]opt.Option[int]    `json:"scores"`
+	Aliases  opt.Option[[]string]          `json:"aliases"`
+	Tracked  opt.Tracked[string]           `json:"tracked"`
+	Ignored  opt.Option[string]            `json:"-"`
+	Extra    opt.Option[map[string]string] `json:"extra,omitzero"`
+}
+
+// schemaJSON returns the indented JSON encoding of s.
+func schemaJSON(t *testing.T, s *jsonschema.Schema) (data string) {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
+
+func Test_Reflect(t *testing.T) {
+	testCases := map[string]*jsonschema.Reflector{
+		"Default":          {},
+		"DoNotReference":   {DoNotReference: true},
+		"ExpandedStruct":   {ExpandedStruct: true},
+		"FieldNameTag":     {FieldNameTag: "yaml"},
+		"Anonymous schema": {Anonymous: true, AllowAdditionalProperties: true},
+	}
+
+	for n, r := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, schemaJSON(t, optjsonschema.Reflect(r, &testUser{})))
+		})
+	}
+}
+
+func Test_Reflect_Option(t *testing.T) {
+	snaps.MatchSnapshot(t, schemaJSON(t, optjsonschema.Reflect(&jsonschema.Reflector{}, opt.Some(3))))
+}
+
+func Test_JSONSchemaAlias(t *testing.T) {
+	// Without optjsonschema, Options are described by the schema of T.
+	r := &jsonschema.Reflector{DoNotReference: true}
+	snaps.MatchSnapshot(t, schemaJSON(t, r.Reflect(&testAddress{})))
+}
@@ -0,0 +1,187 @@
+// Package optlogrus logs Options with github.com/sirupsen/logrus, writing the
+// values that were provided rather than the internals of opt.Option:
+//
+//	logger.
+//		WithFields(optlogrus.Field("nickname", patch.Nickname)).
+//		WithFields(optlogrus.Fields(patch)).
+//		Info("user updated")
+//
+// How Options that were not provided are logged is chosen by a Policy, given
+// with WithPolicy. By default they are left out, so that entries are not
+// crowded with keys that hold no value. A struct reached again through a
+// pointer cycle is logged as the string "<cycle>".
+package optlogrus
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/internal/logwalk"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+	errorType         = reflect.TypeFor[error]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType          = reflect.TypeFor[time.Time]()
+)
+
+// walker logs structs as nested logrus.Fields unless they implement one of the
+// interfaces logrus formatters format values with.
+var walker = logwalk.Walker{
+	IsOption:    opt.IsOptionType,
+	Leaves:      []reflect.Type{timeType},
+	Interfaces:  []reflect.Type{stringerType, errorType, jsonMarshalerType, textMarshalerType},
+	Addressable: true,
+}
+
+// Policy decides how an Option that was not provided is logged.
+type Policy int
+
+const (
+	// Skip leaves the field out of the log entry.
+	Skip Policy = iota
+
+	// Null logs the field as nil.
+	Null
+
+	// Mark logs the field as the string "<empty>".
+	Mark
+)
+
+// config holds the settings of the fields of this package.
+type config struct {
+	// policy decides how Options that were not provided are logged.
+	policy Policy
+}
+
+// FieldOption configures Field and Fields.
+type FieldOption func(c *config)
+
+// WithPolicy logs Options that were not provided as p decides instead of
+// leaving them out. It applies to Option fields of nested structs too.
+func WithPolicy(p Policy) (option FieldOption) {
+	return func(c *config) {
+		c.policy = p
+	}
+}
+
+// newConfig returns the config set by opts.
+func newConfig(opts []FieldOption) (c config) {
+	for _, o := range opts {
+		o(&c)
+	}
+
+	return c
+}
+
+// Field returns logrus.Fields logging the Option o under key. Field takes an
+// opt.Wrapper so that it accepts any Option[T], and types embedding one such
+// as opt.Tracked[T].
+// If the value is provided, Field logs it as is, except that structs are
+// logged as nested logrus.Fields of their fields, named by their JSON names,
+// unless their types describe themselves by implementing fmt.Stringer, error,
+// or a JSON or text marshaler.
+// If the value is not provided, Field logs it as the Policy decides, so that
+// the returned logrus.Fields is empty by default.
+func Field(key string, o opt.Wrapper, opts ...FieldOption) (fields logrus.Fields) {
+	c := newConfig(opts)
+	fields = logrus.Fields{}
+
+	if o == nil {
+		c.absent(fields, key)
+		return fields
+	}
+
+	value, exists := o.AnyValue()
+	if !exists {
+		c.absent(fields, key)
+		return fields
+	}
+
+	c.add(fields, key, reflect.ValueOf(value), logwalk.Seen{})
+	return fields
+}
+
+// Fields returns logrus.Fields holding the fields of the struct v, or of the
+// struct a pointer v points to, named by their JSON names.
+// Options that were provided are logged as their values, and Options that were
+// not provided as the Policy decides. Nested structs are logged as nested
+// logrus.Fields, as Field logs them.
+// If v is not a struct, Fields logs it under the key "value".
+func Fields(v any, opts ...FieldOption) (fields logrus.Fields) {
+	c := newConfig(opts)
+	fields = logrus.Fields{}
+
+	rv := reflect.ValueOf(v)
+	if iv := reflect.Indirect(rv); !iv.IsValid() || iv.Kind() != reflect.Struct || opt.IsOptionType(iv.Type()) {
+		c.add(fields, "value", rv, logwalk.Seen{})
+		return fields
+	}
+
+	c.addFields(fields, reflect.Indirect(rv), logwalk.Seen{})
+	return fields
+}
+
+// absent adds an Option that was not provided to fields.
+func (c config) absent(fields logrus.Fields, key string) {
+	switch c.policy {
+	case Null:
+		fields[key] = nil
+	case Mark:
+		fields[key] = "<empty>"
+	}
+}
+
+// add adds v to fields, as nested logrus.Fields if it is a struct that does
+// not describe itself. The structs in seen are being logged, so v is logged as
+// a cycle if it is one of them.
+func (c config) add(fields logrus.Fields, key string, v reflect.Value, seen logwalk.Seen) {
+	if !v.IsValid() {
+		fields[key] = nil
+		return
+	}
+
+	if opt.IsOptionType(v.Type()) {
+		value, exists := opt.ValueOf(v)
+		if !exists {
+			c.absent(fields, key)
+			return
+		}
+
+		c.add(fields, key, value, seen)
+		return
+	}
+
+	rv, ok := walker.Struct(v)
+	if !ok {
+		fields[key] = v.Interface()
+		return
+	}
+
+	if seen.Contains(rv) {
+		fields[key] = logwalk.Cycle
+		return
+	}
+
+	nested := logrus.Fields{}
+	c.addFields(nested, rv, seen)
+	fields[key] = nested
+}
+
+// addFields adds the exported fields of the struct v to fields, promoting the
+// fields of embedded structs as encoding/json does.
+func (c config) addFields(fields logrus.Fields, v reflect.Value, seen logwalk.Seen) {
+	seen.Enter(v)
+	defer seen.Leave(v)
+
+	for name, fv := range walker.Fields(v) {
+		c.add(fields, name, fv, seen)
+	}
+}
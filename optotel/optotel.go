@@ -0,0 +1,219 @@
+// Package optotel converts Options to OpenTelemetry attributes, leaving out
+// the values that were not provided:
+//
+//	if kv, ok := optotel.Attribute("user.nickname", patch.Nickname); ok {
+//		span.SetAttributes(kv)
+//	}
+//
+//	span.SetAttributes(optotel.Attributes("patch", patch)...)
+package optotel
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/internal/logwalk"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+	errorType         = reflect.TypeFor[error]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType          = reflect.TypeFor[time.Time]()
+)
+
+// walker flattens structs into the attributes of their fields unless they
+// describe themselves as strings.
+var walker = logwalk.Walker{
+	IsOption:   opt.IsOptionType,
+	Leaves:     []reflect.Type{timeType},
+	Interfaces: []reflect.Type{stringerType, errorType, textMarshalerType},
+}
+
+// Attribute returns an attribute.KeyValue holding the value of the Option o
+// under key, and whether the value was provided. Attribute takes an
+// opt.Wrapper so that it accepts any Option[T], and types embedding one such
+// as opt.Tracked[T].
+// Booleans, integers, floats and strings, and slices of them, are converted to
+// attributes of the matching type. Times are converted to strings in RFC 3339
+// format, and other values to strings as fmt.Sprint formats them.
+// If the value is not provided, Attribute returns false.
+func Attribute(key string, o opt.Wrapper) (kv attribute.KeyValue, ok bool) {
+	if o == nil {
+		return attribute.KeyValue{}, false
+	}
+
+	value, exists := o.AnyValue()
+	if !exists {
+		return attribute.KeyValue{}, false
+	}
+
+	return convert(key, reflect.ValueOf(value))
+}
+
+// Attributes returns the attributes of the exported fields of the struct v, or
+// of the struct a pointer v points to, keyed by their JSON names joined to
+// prefix with dots. Nested structs are flattened, so that the field City of
+// the field Address is keyed "prefix.address.city".
+// Options that were provided are converted as Attribute converts them, and
+// Options that were not provided are left out. A struct reached again through
+// a pointer cycle is converted to the string "<cycle>".
+// If v is not a struct, Attributes returns its attribute keyed by prefix.
+func Attributes(prefix string, v any) (attrs []attribute.KeyValue) {
+	return appendValue(nil, prefix, reflect.ValueOf(v), logwalk.Seen{})
+}
+
+// appendValue appends the attributes of v to attrs, flattening the fields of
+// structs that do not describe themselves. The structs in seen are being
+// flattened, so v is converted as a cycle if it is one of them.
+func appendValue(attrs []attribute.KeyValue, key string, v reflect.Value, seen logwalk.Seen) (result []attribute.KeyValue) {
+	if !v.IsValid() {
+		return attrs
+	}
+
+	if opt.IsOptionType(v.Type()) {
+		value, exists := opt.ValueOf(v)
+		if !exists {
+			return attrs
+		}
+
+		return appendValue(attrs, key, value, seen)
+	}
+
+	rv, ok := walker.Struct(v)
+	switch {
+	case !rv.IsValid():
+		return attrs
+	case !ok:
+		if kv, ok := convert(key, v); ok {
+			attrs = append(attrs, kv)
+		}
+
+		return attrs
+	case seen.Contains(rv):
+		return append(attrs, attribute.String(key, logwalk.Cycle))
+	}
+
+	seen.Enter(rv)
+	defer seen.Leave(rv)
+
+	for name, fv := range walker.Fields(rv) {
+		attrs = appendValue(attrs, logwalk.JoinKey(key, name), fv, seen)
+	}
+
+	return attrs
+}
+
+// convert returns the attribute of v under key, and false if v is a nil
+// pointer or interface.
+func convert(key string, v reflect.Value) (kv attribute.KeyValue, ok bool) {
+	if !v.IsValid() {
+		return attribute.KeyValue{}, false
+	}
+
+	if opt.IsOptionType(v.Type()) {
+		value, exists := opt.ValueOf(v)
+		if !exists {
+			return attribute.KeyValue{}, false
+		}
+
+		return convert(key, value)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return attribute.KeyValue{}, false
+		}
+	}
+
+	if walker.DescribesItself(v.Type()) {
+		return attribute.String(key, describe(v.Interface())), true
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return convert(key, v.Elem())
+	case reflect.Bool:
+		return attribute.Bool(key, v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return attribute.Int64(key, v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return attribute.String(key, fmt.Sprint(v.Uint())), true
+		}
+
+		return attribute.Int64(key, int64(v.Uint())), true
+	case reflect.Float32, reflect.Float64:
+		return attribute.Float64(key, v.Float()), true
+	case reflect.String:
+		return attribute.String(key, v.String()), true
+	case reflect.Slice, reflect.Array:
+		if kv, ok := convertSlice(key, v); ok {
+			return kv, true
+		}
+	}
+
+	return attribute.String(key, fmt.Sprint(v.Interface())), true
+}
+
+// convertSlice returns the slice attribute of the slice or array v, and false
+// if its elements are not booleans, signed integers, floats or strings.
+func convertSlice(key string, v reflect.Value) (kv attribute.KeyValue, ok bool) {
+	et := v.Type().Elem()
+	if walker.DescribesItself(et) {
+		return attribute.KeyValue{}, false
+	}
+
+	switch et.Kind() {
+	case reflect.Bool:
+		s := make([]bool, v.Len())
+		for i := range s {
+			s[i] = v.Index(i).Bool()
+		}
+
+		return attribute.BoolSlice(key, s), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s := make([]int64, v.Len())
+		for i := range s {
+			s[i] = v.Index(i).Int()
+		}
+
+		return attribute.Int64Slice(key, s), true
+	case reflect.Float32, reflect.Float64:
+		s := make([]float64, v.Len())
+		for i := range s {
+			s[i] = v.Index(i).Float()
+		}
+
+		return attribute.Float64Slice(key, s), true
+	case reflect.String:
+		s := make([]string, v.Len())
+		for i := range s {
+			s[i] = v.Index(i).String()
+		}
+
+		return attribute.StringSlice(key, s), true
+	default:
+		return attribute.KeyValue{}, false
+	}
+}
+
+// describe returns the string a value that describes itself is converted to.
+func describe(v any) (s string) {
+	switch v := v.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case encoding.TextMarshaler:
+		if b, err := v.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,145 @@
+package optotel_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optotel"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testAddress struct {
+	City     opt.Option[string] `json:"city"`
+	Postcode opt.Option[string] `json:"postcode"`
+}
+
+type testBase struct {
+	ID int `json:"id"`
+}
+
+type testUser struct {
+	testBase
+	Name     opt.Option[string]      `json:"name"`
+	Nickname opt.Option[string]      `json:"nickname"`
+	Age      opt.Option[int]         `json:"age"`
+	Address  opt.Option[testAddress] `json:"address"`
+	Seen     opt.Tracked[time.Time]  `json:"seen"`
+	Tags     []string                `json:"tags,omitempty"`
+	Password string                  `json:"-"`
+	internal string
+}
+
+var testUserValue = testUser{
+	testBase: testBase{ID: 1},
+	Name:     opt.Some("Ada"),
+	Age:      opt.Some(0),
+	Address:  opt.Some(testAddress{City: opt.Some("London")}),
+	Tags:     []string{"admin"},
+	Password: "secret",
+	internal: "internal",
+}
+
+// describe returns the keys, types and values of attrs, one per line.
+func describe(attrs ...attribute.KeyValue) (s string) {
+	for _, kv := range attrs {
+		s += fmt.Sprintf("%s %s %s\n", kv.Key, kv.Value.Type(), kv.Value.Emit())
+	}
+
+	return s
+}
+
+func Test_Attribute(t *testing.T) {
+	testCases := map[string]opt.Wrapper{
+		"String":      opt.Some("hello world"),
+		"Bool":        opt.Some(true),
+		"Int zero":    opt.Some(0),
+		"Uint":        opt.Some[uint8](42),
+		"Large uint":  opt.Some[uint64](1 << 63),
+		"Float":       opt.Some(1.5),
+		"Strings":     opt.Some([]string{"a", "b"}),
+		"Ints":        opt.Some([]int32{1, 2}),
+		"Pointer":     opt.Some(new(int)),
+		"Nil pointer": opt.Some[*int](nil),
+		"Time":        opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		"Duration":    opt.Some(time.Second),
+		"Struct":      opt.Some(testAddress{City: opt.Some("London")}),
+		"Nested":      opt.Some(opt.Some(42)),
+		"Nested None": opt.Some(opt.None[int]()),
+		"None":        opt.None[string](),
+		"Nil":         nil,
+		"Tracked":     opt.Tracked[string]{Option: opt.Some("tracked")},
+	}
+
+	for n, o := range testCases {
+		t.Run(n, func(t *testing.T) {
+			kv, ok := optotel.Attribute("value", o)
+			if !ok {
+				snaps.MatchSnapshot(t, ok)
+				return
+			}
+
+			snaps.MatchSnapshot(t, ok, describe(kv))
+		})
+	}
+}
+
+func Test_Attributes(t *testing.T) {
+	testCases := map[string]any{
+		"Struct":     testUserValue,
+		"Pointer":    &testUserValue,
+		"Empty":      testUser{},
+		"No prefix":  testAddress{City: opt.Some("London")},
+		"Not struct": 42,
+		"Option":     opt.Some(42),
+		"None":       opt.None[int](),
+	}
+
+	for n, v := range testCases {
+		t.Run(n, func(t *testing.T) {
+			prefix := "user"
+			if n == "No prefix" {
+				prefix = ""
+			}
+
+			snaps.MatchSnapshot(t, describe(optotel.Attributes(prefix, v)...))
+		})
+	}
+}
+
+type testNode struct {
+	Name   string                `json:"name"`
+	Parent opt.Option[*testNode] `json:"parent"`
+	Next   *testNode             `json:"next"`
+}
+
+func Test_Attributes_Cycle(t *testing.T) {
+	leaf := &testNode{Name: "leaf"}
+	shared := &testNode{Name: "shared", Parent: opt.Some(leaf), Next: leaf}
+
+	root := &testNode{Name: "root"}
+	child := &testNode{Name: "child", Parent: opt.Some(root)}
+	root.Next = child
+
+	testCases := map[string]*testNode{
+		"Shared": shared,
+		"Cycle":  root,
+		"Self":   {Name: "self"},
+	}
+	testCases["Self"].Next = testCases["Self"]
+
+	for n, node := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, describe(optotel.Attributes("node", node)...))
+		})
+	}
+}
@@ -0,0 +1,276 @@
+// Package optparquet reads and writes Parquet files of structs holding Option
+// fields using github.com/parquet-go/parquet-go.
+//
+// An Option field becomes an OPTIONAL column: an Option that was not provided
+// is written with a definition level marking the value as null, and reading a
+// null leaves the Option not provided. Options of slices and maps are written
+// as repeated columns, which Parquet cannot mark as null, so they read back as
+// not provided when empty.
+//
+// Rows are read back through encoding/json, so the json struct tags of the row
+// type must name every field uniquely.
+package optparquet
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/fletcharoo/opt"
+	"github.com/parquet-go/parquet-go"
+)
+
+// Schema returns the Parquet schema for rows of type T, which must be a struct.
+func Schema[T any]() (schema *parquet.Schema) {
+	t := reflect.TypeFor[T]()
+	return parquet.NewSchema(t.Name(), parquet.SchemaOf(reflect.Zero(mirrorOf(t)).Interface()))
+}
+
+// Writer writes rows of type T to a Parquet file.
+type Writer[T any] struct {
+	// w is the underlying Parquet writer.
+	w *parquet.Writer
+}
+
+// NewWriter returns a Writer writing rows of type T to output.
+// The schema of the file is Schema[T]; options may configure anything else.
+func NewWriter[T any](output io.Writer, options ...parquet.WriterOption) (w *Writer[T]) {
+	options = append([]parquet.WriterOption{Schema[T]()}, options...)
+
+	return &Writer[T]{
+		w: parquet.NewWriter(output, options...),
+	}
+}
+
+// Write writes rows to the file.
+func (w *Writer[T]) Write(rows ...T) (err error) {
+	mirror := mirrorOf(reflect.TypeFor[T]())
+
+	for _, row := range rows {
+		if err = w.w.Write(toMirror(reflect.ValueOf(row), mirror).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush flushes buffered rows to the current row group.
+func (w *Writer[T]) Flush() (err error) {
+	return w.w.Flush()
+}
+
+// Close flushes buffered rows and writes the file footer. It does not close
+// the underlying io.Writer.
+func (w *Writer[T]) Close() (err error) {
+	return w.w.Close()
+}
+
+// Reader reads rows of type T from a Parquet file.
+type Reader[T any] struct {
+	// r is the underlying Parquet reader.
+	r *parquet.Reader
+}
+
+// NewReader returns a Reader reading rows of type T from input.
+func NewReader[T any](input io.ReaderAt, options ...parquet.ReaderOption) (r *Reader[T]) {
+	options = append([]parquet.ReaderOption{Schema[T]()}, options...)
+
+	return &Reader[T]{
+		r: parquet.NewReader(input, options...),
+	}
+}
+
+// NumRows returns the number of rows in the file.
+func (r *Reader[T]) NumRows() (n int64) {
+	return r.r.NumRows()
+}
+
+// Read reads the next row into row.
+// Read returns io.EOF when there are no more rows.
+func (r *Reader[T]) Read(row *T) (err error) {
+	mirror := reflect.New(mirrorOf(reflect.TypeFor[T]()))
+	if err = r.r.Read(mirror.Interface()); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(mirror.Interface())
+	if err != nil {
+		return err
+	}
+
+	*row = *new(T)
+	return json.Unmarshal(data, row)
+}
+
+// Close closes the reader. It does not close the underlying io.ReaderAt.
+func (r *Reader[T]) Close() (err error) {
+	return r.r.Close()
+}
+
+// mirrorCache maps a reflect.Type to its mirror type.
+var mirrorCache sync.Map
+
+// mirrorOf returns the type parquet-go reads and writes in place of t.
+// Options become pointers, which parquet-go maps to OPTIONAL columns, and
+// types holding Options are rebuilt around their mirrors. Types holding no
+// Options are their own mirror.
+func mirrorOf(t reflect.Type) (mirror reflect.Type) {
+	if m, ok := mirrorCache.Load(t); ok {
+		return m.(reflect.Type)
+	}
+
+	m, _ := mirrorCache.LoadOrStore(t, buildMirror(t))
+	return m.(reflect.Type)
+}
+
+// buildMirror does the work for mirrorOf.
+func buildMirror(t reflect.Type) (mirror reflect.Type) {
+	if opt.IsOptionType(t) {
+		elem := mirrorOf(opt.ElemType(t))
+
+		switch elem.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			return elem
+		}
+
+		return reflect.PointerTo(elem)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return reflect.PointerTo(mirrorOf(t.Elem()))
+	case reflect.Slice:
+		return reflect.SliceOf(mirrorOf(t.Elem()))
+	case reflect.Map:
+		return reflect.MapOf(t.Key(), mirrorOf(t.Elem()))
+	case reflect.Struct:
+		fields := mirrorFields(t)
+		if fields == nil {
+			return t
+		}
+
+		return reflect.StructOf(fields)
+	}
+
+	return t
+}
+
+// mirrorFields returns the fields of the mirror of the struct type t, or nil if
+// t holds no Options.
+func mirrorFields(t reflect.Type) (fields []reflect.StructField) {
+	changed := false
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			changed = true
+			continue
+		}
+
+		field := reflect.StructField{
+			Name: sf.Name,
+			Type: mirrorOf(sf.Type),
+			Tag:  sf.Tag,
+		}
+
+		if opt.IsOptionType(sf.Type) {
+			// Options that were not provided are left out of the JSON form of
+			// the mirror so that they decode as not provided.
+			field.Tag = withOmitEmpty(sf)
+		}
+
+		if field.Type != sf.Type {
+			changed = true
+		}
+
+		fields = append(fields, field)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return fields
+}
+
+// withOmitEmpty returns the tag of sf with the omitempty option added to its
+// json tag.
+func withOmitEmpty(sf reflect.StructField) (tag reflect.StructTag) {
+	jsonTag, ok := sf.Tag.Lookup("json")
+	if !ok {
+		return reflect.StructTag(strings.TrimSpace(string(sf.Tag) + ` json:",omitempty"`))
+	}
+
+	old := `json:"` + jsonTag + `"`
+	return reflect.StructTag(strings.Replace(string(sf.Tag), old, `json:"`+jsonTag+`,omitempty"`, 1))
+}
+
+// toMirror converts v into a value of its mirror type.
+func toMirror(v reflect.Value, mirror reflect.Type) (m reflect.Value) {
+	t := v.Type()
+	if t == mirror {
+		return v
+	}
+
+	if opt.IsOptionType(t) {
+		value, exists := opt.ValueOf(v)
+		if !exists {
+			return reflect.Zero(mirror)
+		}
+
+		if mirror.Kind() == reflect.Pointer && mirror.Elem() == mirrorOf(value.Type()) {
+			p := reflect.New(mirror.Elem())
+			p.Elem().Set(toMirror(value, mirror.Elem()))
+			return p
+		}
+
+		return toMirror(value, mirror)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return reflect.Zero(mirror)
+		}
+
+		p := reflect.New(mirror.Elem())
+		p.Elem().Set(toMirror(v.Elem(), mirror.Elem()))
+		return p
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(mirror)
+		}
+
+		s := reflect.MakeSlice(mirror, v.Len(), v.Len())
+		for i := range v.Len() {
+			s.Index(i).Set(toMirror(v.Index(i), mirror.Elem()))
+		}
+
+		return s
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(mirror)
+		}
+
+		mv := reflect.MakeMapWithSize(mirror, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			mv.SetMapIndex(iter.Key(), toMirror(iter.Value(), mirror.Elem()))
+		}
+
+		return mv
+	case reflect.Struct:
+		s := reflect.New(mirror).Elem()
+		for i := range mirror.NumField() {
+			mf := mirror.Field(i)
+			s.Field(i).Set(toMirror(v.FieldByName(mf.Name), mf.Type))
+		}
+
+		return s
+	}
+
+	return v
+}
@@ -0,0 +1,91 @@
+package optparquet_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optparquet"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testVehicle struct {
+	Make  string             `parquet:"make" json:"make"`
+	Model opt.Option[string] `parquet:"model" json:"model"`
+}
+
+type testRow struct {
+	ID      int64                   `parquet:"id" json:"id"`
+	Name    opt.Option[string]      `parquet:"name" json:"name"`
+	Count   opt.Option[int32]       `parquet:"count" json:"count"`
+	Score   opt.Option[float64]     `parquet:"score" json:"score"`
+	Active  opt.Option[bool]        `parquet:"active" json:"active"`
+	Tags    opt.Option[[]string]    `parquet:"tags,list" json:"tags"`
+	Vehicle opt.Option[testVehicle] `parquet:"vehicle" json:"vehicle"`
+}
+
+var testRows = []testRow{
+	{ID: 1},
+	{
+		ID:      2,
+		Name:    opt.Some("hello world"),
+		Count:   opt.Some(int32(3)),
+		Score:   opt.Some(0.5),
+		Active:  opt.Some(true),
+		Tags:    opt.Some([]string{"a", "b"}),
+		Vehicle: opt.Some(testVehicle{Make: "Toyota", Model: opt.Some("Hilux")}),
+	},
+	{
+		ID:      3,
+		Name:    opt.Some(""),
+		Count:   opt.Some(int32(0)),
+		Active:  opt.Some(false),
+		Vehicle: opt.Some(testVehicle{Make: "Ford"}),
+	},
+}
+
+func Test_Schema(t *testing.T) {
+	snaps.MatchSnapshot(t, optparquet.Schema[testRow]().String())
+}
+
+func Test_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := optparquet.NewWriter[testRow](&buf)
+	if err := w.Write(testRows...); err != nil {
+		t.Fatalf("Unexpected write error: %s", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Unexpected close error: %s", err)
+	}
+
+	r := optparquet.NewReader[testRow](bytes.NewReader(buf.Bytes()))
+	defer r.Close()
+
+	var rows []testRow
+	for {
+		var row testRow
+		err := r.Read(&row)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Unexpected read error: %s", err)
+		}
+
+		rows = append(rows, row)
+	}
+
+	snaps.MatchSnapshot(t, r.NumRows(), rows)
+}
@@ -0,0 +1,159 @@
+// Package optpflag binds command-line flags of github.com/spf13/pflag, and so
+// of github.com/spf13/cobra, to Options, so that an Option is provided exactly
+// when its flag was given on the command line:
+//
+//	var port opt.Option[int]
+//	optpflag.Var(cmd.Flags(), &port, "port", "port to listen on")
+//
+// Flags defined with the usual pflag functions can be read as Options with
+// Get, which returns None unless the flag was changed:
+//
+//	port, err := optpflag.Get[int](cmd.Flags(), "port")
+package optpflag
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/spf13/pflag"
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// Value implements pflag.Value for an opt.Option, setting the Option when its
+// flag is given. A *Value points at the Option it binds, as returned by
+// Target, so setting the flag fills in that Option.
+type Value[T any] struct {
+	// option is the Option set by the flag.
+	option *opt.Option[T]
+
+	// changed indicates whether the flag was given, so that the values of a
+	// slice flag given again are appended rather than replacing them.
+	changed bool
+}
+
+// Target returns a *Value binding o, for use as a pflag.Value that fills in o
+// directly.
+func Target[T any](o *opt.Option[T]) (target *Value[T]) {
+	return &Value[T]{option: o}
+}
+
+// Option returns the Option v binds.
+func (v *Value[T]) Option() (unwrapped opt.Option[T]) {
+	return *v.option
+}
+
+// String implements the pflag.Value interface.
+// If the value is provided, String returns it formatted as by fmt.Sprint.
+// If the value is not provided, String returns "", so that pflag does not
+// print a default for the flag.
+func (v *Value[T]) String() (str string) {
+	return v.Option().StringOr("")
+}
+
+// Set implements the pflag.Value interface, parsing s into T as
+// opt.ParseFlag does and marking the value as provided.
+// When T is a slice split on commas by opt.ParseFlag, the values of a flag
+// given more than once are appended, as in pflag's own slice flags, so that
+// --tags a --tags b sets []string{"a", "b"}. The first value given replaces
+// any default.
+func (v *Value[T]) Set(s string) (err error) {
+	o, err := opt.ParseFlag[T](s)
+	if err != nil {
+		return err
+	}
+
+	if v.changed && isSliceType(reflect.TypeFor[T]()) {
+		appended := reflect.AppendSlice(reflect.ValueOf(v.option.Unwrap()), reflect.ValueOf(o.Unwrap()))
+		o = opt.Some(appended.Interface().(T))
+	}
+
+	*v.option = o
+	v.changed = true
+	return nil
+}
+
+// Type implements the pflag.Value interface, returning the name of T as pflag
+// names the types of its own flags, such as "int", "duration" or
+// "stringSlice".
+func (v *Value[T]) Type() (name string) {
+	return typeName(reflect.TypeFor[T]())
+}
+
+// Var defines a flag with the specified name and usage on fs that sets o when
+// it is given. A flag of an Option[bool] can be given without a value, as
+// --name, to set it to true.
+func Var[T any](fs *pflag.FlagSet, o *opt.Option[T], name, usage string) (flag *pflag.Flag) {
+	return VarP(fs, o, name, "", usage)
+}
+
+// VarP is like Var, but accepts a shorthand letter that can be used after a
+// single dash.
+func VarP[T any](fs *pflag.FlagSet, o *opt.Option[T], name, shorthand, usage string) (flag *pflag.Flag) {
+	flag = fs.VarPF(Target(o), name, shorthand, usage)
+	if reflect.TypeFor[T]().Kind() == reflect.Bool {
+		flag.NoOptDefVal = "true"
+	}
+
+	return flag
+}
+
+// Get returns the value of the flag with the specified name on fs as an
+// Option, converted to T as Value.Set converts it.
+// If the flag was changed, Get returns Some of its value, even if it is the
+// default or the zero value of T.
+// If the flag was not changed, Get returns None.
+// Get returns an error if fs has no flag of that name, or if its value does
+// not convert to T.
+func Get[T any](fs *pflag.FlagSet, name string) (o opt.Option[T], err error) {
+	flag := fs.Lookup(name)
+	if flag == nil {
+		return o, fmt.Errorf("optpflag: flag accessed but not defined: %s", name)
+	}
+
+	if !flag.Changed {
+		return opt.None[T](), nil
+	}
+
+	s := flag.Value.String()
+	if sv, ok := flag.Value.(pflag.SliceValue); ok {
+		s = strings.Join(sv.GetSlice(), ",")
+	}
+
+	if o, err = opt.ParseFlag[T](s); err != nil {
+		return o, fmt.Errorf("optpflag: flag %s: %w", name, err)
+	}
+
+	return o, nil
+}
+
+// isSliceType reports whether t is a slice that opt.ParseFlag splits on
+// commas, that is a slice other than a byte slice that does not implement
+// encoding.TextUnmarshaler.
+func isSliceType(t reflect.Type) (ok bool) {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 &&
+		!reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// typeName returns the name of t as pflag names the types of its flags.
+func typeName(t reflect.Type) (name string) {
+	switch {
+	case t == durationType:
+		return "duration"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return "bytes"
+	case t.Kind() == reflect.Slice:
+		return typeName(t.Elem()) + "Slice"
+	case t.PkgPath() == "" && t.Name() != "":
+		return t.Name()
+	default:
+		return t.String()
+	}
+}
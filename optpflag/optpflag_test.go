@@ -0,0 +1,119 @@
+package optpflag_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optpflag"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/spf13/pflag"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testFlags struct {
+	Name    opt.Option[string]
+	Port    opt.Option[int]
+	Debug   opt.Option[bool]
+	Timeout opt.Option[time.Duration]
+	Tags    opt.Option[[]string]
+	IDs     opt.Option[[]int]
+	Backoff opt.Option[[]time.Duration]
+	Started opt.Option[time.Time]
+}
+
+// newFlagSet returns a FlagSet binding the fields of flags.
+func newFlagSet(flags *testFlags) (fs *pflag.FlagSet) {
+	fs = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	optpflag.VarP(fs, &flags.Name, "name", "n", "name of the service")
+	optpflag.Var(fs, &flags.Port, "port", "port to listen on")
+	optpflag.Var(fs, &flags.Debug, "debug", "enable debugging")
+	optpflag.Var(fs, &flags.Timeout, "timeout", "request timeout")
+	optpflag.Var(fs, &flags.Tags, "tags", "tags to apply")
+	optpflag.Var(fs, &flags.IDs, "ids", "ids to process")
+	optpflag.Var(fs, &flags.Backoff, "backoff", "delays between retries")
+	optpflag.Var(fs, &flags.Started, "started", "start time")
+	return fs
+}
+
+func Test_Var(t *testing.T) {
+	testCases := map[string][]string{
+		"None":        {},
+		"Zero values": {"--name=", "--port=0", "--debug=false", "--tags="},
+		"Values": {
+			"-n", "service",
+			"--port", "8080",
+			"--debug",
+			"--timeout", "1m30s",
+			"--tags", "a,b",
+			"--ids", "1,2",
+			"--backoff", "1s,2s",
+			"--started", "2024-01-02T03:04:05Z",
+		},
+		"Repeated": {
+			"--tags", "a",
+			"--tags", "b,c",
+			"--ids", "1",
+			"--ids", "2",
+			"--port", "8080",
+			"--port", "9090",
+		},
+		"Invalid": {"--port", "eighty"},
+	}
+
+	for n, args := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var flags testFlags
+			err := newFlagSet(&flags).Parse(args)
+			snaps.MatchSnapshot(t, flags, err)
+		})
+	}
+
+	t.Run("Default replaced", func(t *testing.T) {
+		flags := testFlags{Tags: opt.Some([]string{"default"})}
+		err := newFlagSet(&flags).Parse([]string{"--tags", "a", "--tags", "b"})
+		snaps.MatchSnapshot(t, flags.Tags, err)
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		var flags testFlags
+		snaps.MatchSnapshot(t, newFlagSet(&flags).FlagUsages())
+	})
+}
+
+func Test_Get(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.Int("port", 8080, "port to listen on")
+	fs.Bool("debug", false, "enable debugging")
+	fs.IntSlice("ids", nil, "ids to process")
+	fs.Duration("timeout", time.Second, "request timeout")
+	fs.DurationSlice("backoff", nil, "delays between retries")
+
+	err := fs.Parse([]string{"--port", "8080", "--ids", "1,2", "--backoff", "1s,1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port, portErr := optpflag.Get[int](fs, "port")
+	debug, debugErr := optpflag.Get[bool](fs, "debug")
+	ids, idsErr := optpflag.Get[[]int](fs, "ids")
+	timeout, timeoutErr := optpflag.Get[time.Duration](fs, "timeout")
+	backoff, backoffErr := optpflag.Get[[]time.Duration](fs, "backoff")
+	_, missingErr := optpflag.Get[int](fs, "missing")
+	_, invalidErr := optpflag.Get[bool](fs, "port")
+
+	snaps.MatchSnapshot(t,
+		port, portErr,
+		debug, debugErr,
+		ids, idsErr,
+		timeout, timeoutErr,
+		backoff, backoffErr,
+		missingErr, invalidErr != nil,
+	)
+}
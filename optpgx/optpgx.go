@@ -0,0 +1,152 @@
+// Package optpgx lets pgx v5 bind Option values as query parameters and scan
+// nullable columns into them using the native PostgreSQL codecs, including
+// array types.
+//
+// opt.Option implements sql.Scanner and driver.Valuer, which pgx uses as a
+// fallback, but pgx hands a sql.Scanner the text form of arrays and other
+// composite types. The Option type of this package implements neither, so once
+// Register has been called on a pgtype.Map, pgx encodes and scans the value of
+// the Option with the codec of the column, and an Option that was not provided
+// is written as NULL.
+package optpgx
+
+import (
+	"github.com/fletcharoo/opt"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Option is an opt.Option that pgx encodes and scans with the codec for T once
+// Register has been called. It is a distinct type rather than a struct
+// embedding opt.Option so that it does not inherit the database/sql methods.
+type Option[T any] opt.Option[T]
+
+// Wrap returns o as an Option, for use as a query argument.
+func Wrap[T any](o opt.Option[T]) (wrapped Option[T]) {
+	return Option[T](o)
+}
+
+// Target returns o as a *Option, for use as a scan destination that fills in
+// o directly.
+func Target[T any](o *opt.Option[T]) (target *Option[T]) {
+	return (*Option[T])(o)
+}
+
+// Option returns o as an opt.Option.
+func (o Option[T]) Option() (unwrapped opt.Option[T]) {
+	return opt.Option[T](o)
+}
+
+// Register makes m encode and scan Options with the codecs of their value
+// types. It is typically called on the type map of each new connection, for
+// example from pgxpool.Config.AfterConnect:
+//
+//	optpgx.Register(conn.TypeMap())
+func Register(m *pgtype.Map) {
+	m.TryWrapEncodePlanFuncs = append([]pgtype.TryWrapEncodePlanFunc{TryWrapEncodePlan}, m.TryWrapEncodePlanFuncs...)
+	m.TryWrapScanPlanFuncs = append([]pgtype.TryWrapScanPlanFunc{TryWrapScanPlan}, m.TryWrapScanPlanFuncs...)
+}
+
+// encoder is implemented by every Option[T] and lets the encode plan reach the
+// value without knowing T.
+type encoder interface {
+	pgxValue() (value any, exists bool)
+	pgxZero() (zero any)
+}
+
+// pgxValue returns the value as an any along with whether it was provided.
+func (o Option[T]) pgxValue() (value any, exists bool) {
+	return o.Option().Unwrap(), o.Option().Exists()
+}
+
+// pgxZero returns the zero value of T, which pgx uses to find the plan for T.
+func (o Option[T]) pgxZero() (zero any) {
+	return *new(T)
+}
+
+// scanner is implemented by every *Option[T] and lets the scan plan fill in
+// the value without knowing T.
+type scanner interface {
+	pgxTarget() (target any)
+	pgxScan(next pgtype.ScanPlan, src []byte) (err error)
+}
+
+// pgxTarget returns a *T, which pgx uses to find the plan for T.
+func (o *Option[T]) pgxTarget() (target any) {
+	return new(T)
+}
+
+// pgxScan scans src into the value with next, leaving the value not provided
+// if src is NULL.
+func (o *Option[T]) pgxScan(next pgtype.ScanPlan, src []byte) (err error) {
+	if src == nil {
+		*o = Option[T]{}
+		return nil
+	}
+
+	var value T
+	if err = next.Scan(src, &value); err != nil {
+		return err
+	}
+
+	*o = Option[T](opt.Some(value))
+	return nil
+}
+
+// TryWrapEncodePlan is a pgtype.TryWrapEncodePlanFunc that encodes an Option
+// as its value, or as NULL if the value is not provided.
+func TryWrapEncodePlan(value any) (plan pgtype.WrappedEncodePlanNextSetter, nextValue any, ok bool) {
+	e, ok := value.(encoder)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return &encodePlan{}, e.pgxZero(), true
+}
+
+// encodePlan is the pgtype.EncodePlan returned by TryWrapEncodePlan.
+type encodePlan struct {
+	// next encodes the value of the Option.
+	next pgtype.EncodePlan
+}
+
+// SetNext implements the pgtype.WrappedEncodePlanNextSetter interface.
+func (p *encodePlan) SetNext(next pgtype.EncodePlan) {
+	p.next = next
+}
+
+// Encode implements the pgtype.EncodePlan interface.
+func (p *encodePlan) Encode(value any, buf []byte) (newBuf []byte, err error) {
+	v, exists := value.(encoder).pgxValue()
+	if !exists {
+		return nil, nil
+	}
+
+	return p.next.Encode(v, buf)
+}
+
+// TryWrapScanPlan is a pgtype.TryWrapScanPlanFunc that scans into the value of
+// an Option, leaving it not provided if the column is NULL.
+func TryWrapScanPlan(target any) (plan pgtype.WrappedScanPlanNextSetter, nextTarget any, ok bool) {
+	s, ok := target.(scanner)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return &scanPlan{}, s.pgxTarget(), true
+}
+
+// scanPlan is the pgtype.ScanPlan returned by TryWrapScanPlan.
+type scanPlan struct {
+	// next scans into the value of the Option.
+	next pgtype.ScanPlan
+}
+
+// SetNext implements the pgtype.WrappedScanPlanNextSetter interface.
+func (p *scanPlan) SetNext(next pgtype.ScanPlan) {
+	p.next = next
+}
+
+// Scan implements the pgtype.ScanPlan interface.
+func (p *scanPlan) Scan(src []byte, target any) (err error) {
+	return target.(scanner).pgxScan(p.next, src)
+}
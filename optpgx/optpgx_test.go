@@ -0,0 +1,102 @@
+package optpgx_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optpgx"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+func newMap() *pgtype.Map {
+	m := pgtype.NewMap()
+	optpgx.Register(m)
+	return m
+}
+
+type encodeTestCase struct {
+	oid    uint32
+	format int16
+	value  any
+}
+
+var encodeTestCases = map[string]encodeTestCase{
+	"Int4 binary":      {oid: pgtype.Int4OID, format: pgtype.BinaryFormatCode, value: optpgx.Wrap(opt.Some(int32(42)))},
+	"Int4 text":        {oid: pgtype.Int4OID, format: pgtype.TextFormatCode, value: optpgx.Wrap(opt.Some(int32(42)))},
+	"Int4 none":        {oid: pgtype.Int4OID, format: pgtype.BinaryFormatCode, value: optpgx.Wrap(opt.None[int32]())},
+	"Text":             {oid: pgtype.TextOID, format: pgtype.TextFormatCode, value: optpgx.Wrap(opt.Some("hello world"))},
+	"Text empty":       {oid: pgtype.TextOID, format: pgtype.TextFormatCode, value: optpgx.Wrap(opt.Some(""))},
+	"Int4 array":       {oid: pgtype.Int4ArrayOID, format: pgtype.TextFormatCode, value: optpgx.Wrap(opt.Some([]int32{1, 2, 3}))},
+	"Int4 array none":  {oid: pgtype.Int4ArrayOID, format: pgtype.TextFormatCode, value: optpgx.Wrap(opt.None[[]int32]())},
+	"Text array":       {oid: pgtype.TextArrayOID, format: pgtype.TextFormatCode, value: optpgx.Wrap(opt.Some([]string{"a", "b c"}))},
+	"Timestamptz":      {oid: pgtype.TimestamptzOID, format: pgtype.TextFormatCode, value: optpgx.Wrap(opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))},
+	"Pointer nil":      {oid: pgtype.TextOID, format: pgtype.TextFormatCode, value: optpgx.Wrap(opt.Some[*string](nil))},
+	"Unwrapped Option": {oid: pgtype.Int4ArrayOID, format: pgtype.TextFormatCode, value: opt.Some([]int32{1, 2, 3})},
+}
+
+func Test_Encode(t *testing.T) {
+	m := newMap()
+
+	for n, c := range encodeTestCases {
+		t.Run(n, func(t *testing.T) {
+			buf, err := m.Encode(c.oid, c.format, c.value, []byte{})
+			snaps.MatchSnapshot(t, fmt.Sprint(err), fmt.Sprintf("%q", buf), buf == nil)
+		})
+	}
+}
+
+func Test_Scan(t *testing.T) {
+	m := newMap()
+
+	t.Run("Int4 binary", func(t *testing.T) {
+		var o opt.Option[int32]
+		err := m.Scan(pgtype.Int4OID, pgtype.BinaryFormatCode, []byte{0, 0, 0, 42}, optpgx.Target(&o))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o)
+	})
+
+	t.Run("Int4 NULL", func(t *testing.T) {
+		o := opt.Some(int32(1))
+		err := m.Scan(pgtype.Int4OID, pgtype.BinaryFormatCode, nil, optpgx.Target(&o))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o)
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var o optpgx.Option[string]
+		err := m.Scan(pgtype.TextOID, pgtype.TextFormatCode, []byte(""), &o)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o.Option())
+	})
+
+	t.Run("Int4 array", func(t *testing.T) {
+		var o opt.Option[[]int32]
+		err := m.Scan(pgtype.Int4ArrayOID, pgtype.TextFormatCode, []byte("{1,2,3}"), optpgx.Target(&o))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o)
+	})
+
+	t.Run("Text array NULL", func(t *testing.T) {
+		var o opt.Option[[]string]
+		err := m.Scan(pgtype.TextArrayOID, pgtype.TextFormatCode, nil, optpgx.Target(&o))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o)
+	})
+
+	t.Run("Timestamptz", func(t *testing.T) {
+		var o opt.Option[time.Time]
+		err := m.Scan(pgtype.TimestamptzOID, pgtype.TextFormatCode, []byte("2024-01-02 03:04:05Z"), optpgx.Target(&o))
+		snaps.MatchSnapshot(t, fmt.Sprint(err), o.Unwrap().UTC().String())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		var o opt.Option[int32]
+		err := m.Scan(pgtype.Int4OID, pgtype.TextFormatCode, []byte("forty two"), optpgx.Target(&o))
+		snaps.MatchSnapshot(t, err != nil, o)
+	})
+}
@@ -0,0 +1,336 @@
+// Package optproto converts between Options and the well-known protobuf types
+// of google.golang.org/protobuf, so code at gRPC boundaries can move nullable
+// fields in and out of messages without nil checks:
+//
+//	user := User{
+//		Nickname: optproto.FromStringValue(req.GetNickname()),
+//		Birthday: optproto.FromTimestamp(req.GetBirthday()),
+//	}
+//
+//	resp := &pb.User{
+//		Nickname: optproto.ToStringValue(user.Nickname),
+//		Birthday: optproto.ToTimestamp(user.Birthday),
+//	}
+//
+// It also builds and applies field masks from structs of Options, for Update
+// RPCs:
+//
+//	mask, err := optproto.FieldMask(patch, &pb.User{})
+//	...
+//	err = optproto.ApplyMask(user, req.GetUpdateMask(), patch)
+package optproto
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// wrapper is a message of wrapperspb holding a value of type T, such as
+// *wrapperspb.StringValue.
+type wrapper[T any] interface {
+	comparable
+	GetValue() T
+}
+
+// fromWrapper returns an Option holding the value of w if w is not nil, and an
+// Option whose value is not provided otherwise.
+func fromWrapper[T any, W wrapper[T]](w W) (o opt.Option[T]) {
+	var null W
+	if w == null {
+		return opt.None[T]()
+	}
+
+	return opt.Some(w.GetValue())
+}
+
+// toWrapper returns the wrapper wrap makes of the value of o if it is provided,
+// and nil otherwise.
+func toWrapper[T any, W wrapper[T]](o opt.Option[T], wrap func(T) W) (w W) {
+	if !o.Exists() {
+		return w
+	}
+
+	return wrap(o.Unwrap())
+}
+
+// FromStringValue returns an Option holding the value of w if w is not nil, and
+// an Option whose value is not provided otherwise.
+func FromStringValue(w *wrapperspb.StringValue) (o opt.Option[string]) {
+	return fromWrapper[string](w)
+}
+
+// ToStringValue returns a wrapperspb.StringValue holding the value of o if it
+// is provided, and nil otherwise.
+func ToStringValue(o opt.Option[string]) (w *wrapperspb.StringValue) {
+	return toWrapper(o, wrapperspb.String)
+}
+
+// FromBoolValue returns an Option holding the value of w if w is not nil, and
+// an Option whose value is not provided otherwise.
+func FromBoolValue(w *wrapperspb.BoolValue) (o opt.Option[bool]) {
+	return fromWrapper[bool](w)
+}
+
+// ToBoolValue returns a wrapperspb.BoolValue holding the value of o if it is
+// provided, and nil otherwise.
+func ToBoolValue(o opt.Option[bool]) (w *wrapperspb.BoolValue) {
+	return toWrapper(o, wrapperspb.Bool)
+}
+
+// FromInt32Value returns an Option holding the value of w if w is not nil, and
+// an Option whose value is not provided otherwise.
+func FromInt32Value(w *wrapperspb.Int32Value) (o opt.Option[int32]) {
+	return fromWrapper[int32](w)
+}
+
+// ToInt32Value returns a wrapperspb.Int32Value holding the value of o if it is
+// provided, and nil otherwise.
+func ToInt32Value(o opt.Option[int32]) (w *wrapperspb.Int32Value) {
+	return toWrapper(o, wrapperspb.Int32)
+}
+
+// FromInt64Value returns an Option holding the value of w if w is not nil, and
+// an Option whose value is not provided otherwise.
+func FromInt64Value(w *wrapperspb.Int64Value) (o opt.Option[int64]) {
+	return fromWrapper[int64](w)
+}
+
+// ToInt64Value returns a wrapperspb.Int64Value holding the value of o if it is
+// provided, and nil otherwise.
+func ToInt64Value(o opt.Option[int64]) (w *wrapperspb.Int64Value) {
+	return toWrapper(o, wrapperspb.Int64)
+}
+
+// FromUInt32Value returns an Option holding the value of w if w is not nil, and
+// an Option whose value is not provided otherwise.
+func FromUInt32Value(w *wrapperspb.UInt32Value) (o opt.Option[uint32]) {
+	return fromWrapper[uint32](w)
+}
+
+// ToUInt32Value returns a wrapperspb.UInt32Value holding the value of o if it
+// is provided, and nil otherwise.
+func ToUInt32Value(o opt.Option[uint32]) (w *wrapperspb.UInt32Value) {
+	return toWrapper(o, wrapperspb.UInt32)
+}
+
+// FromUInt64Value returns an Option holding the value of w if w is not nil, and
+// an Option whose value is not provided otherwise.
+func FromUInt64Value(w *wrapperspb.UInt64Value) (o opt.Option[uint64]) {
+	return fromWrapper[uint64](w)
+}
+
+// ToUInt64Value returns a wrapperspb.UInt64Value holding the value of o if it
+// is provided, and nil otherwise.
+func ToUInt64Value(o opt.Option[uint64]) (w *wrapperspb.UInt64Value) {
+	return toWrapper(o, wrapperspb.UInt64)
+}
+
+// FromFloatValue returns an Option holding the value of w if w is not nil, and
+// an Option whose value is not provided otherwise.
+func FromFloatValue(w *wrapperspb.FloatValue) (o opt.Option[float32]) {
+	return fromWrapper[float32](w)
+}
+
+// ToFloatValue returns a wrapperspb.FloatValue holding the value of o if it is
+// provided, and nil otherwise.
+func ToFloatValue(o opt.Option[float32]) (w *wrapperspb.FloatValue) {
+	return toWrapper(o, wrapperspb.Float)
+}
+
+// FromDoubleValue returns an Option holding the value of w if w is not nil, and
+// an Option whose value is not provided otherwise.
+func FromDoubleValue(w *wrapperspb.DoubleValue) (o opt.Option[float64]) {
+	return fromWrapper[float64](w)
+}
+
+// ToDoubleValue returns a wrapperspb.DoubleValue holding the value of o if it
+// is provided, and nil otherwise.
+func ToDoubleValue(o opt.Option[float64]) (w *wrapperspb.DoubleValue) {
+	return toWrapper(o, wrapperspb.Double)
+}
+
+// FromBytesValue returns an Option holding the value of w if w is not nil, and
+// an Option whose value is not provided otherwise.
+func FromBytesValue(w *wrapperspb.BytesValue) (o opt.Option[[]byte]) {
+	return fromWrapper[[]byte](w)
+}
+
+// ToBytesValue returns a wrapperspb.BytesValue holding the value of o if it is
+// provided, and nil otherwise.
+func ToBytesValue(o opt.Option[[]byte]) (w *wrapperspb.BytesValue) {
+	return toWrapper(o, wrapperspb.Bytes)
+}
+
+// FromTimestamp returns an Option holding the time of ts if ts is not nil, and
+// an Option whose value is not provided otherwise.
+// The time is in UTC, as returned by ts.AsTime.
+func FromTimestamp(ts *timestamppb.Timestamp) (o opt.Option[time.Time]) {
+	if ts == nil {
+		return opt.None[time.Time]()
+	}
+
+	return opt.Some(ts.AsTime())
+}
+
+// ToTimestamp returns a timestamppb.Timestamp holding the time if it is
+// provided, and nil otherwise.
+func ToTimestamp(o opt.Option[time.Time]) (ts *timestamppb.Timestamp) {
+	if !o.Exists() {
+		return nil
+	}
+
+	return timestamppb.New(o.Unwrap())
+}
+
+// FromDuration returns an Option holding the duration of d if d is not nil,
+// and an Option whose value is not provided otherwise.
+// Durations outside the range of time.Duration are clamped, as by
+// d.AsDuration.
+func FromDuration(d *durationpb.Duration) (o opt.Option[time.Duration]) {
+	if d == nil {
+		return opt.None[time.Duration]()
+	}
+
+	return opt.Some(d.AsDuration())
+}
+
+// ToDuration returns a durationpb.Duration holding the duration if it is
+// provided, and nil otherwise.
+func ToDuration(o opt.Option[time.Duration]) (d *durationpb.Duration) {
+	if !o.Exists() {
+		return nil
+	}
+
+	return durationpb.New(o.Unwrap())
+}
+
+// FieldMask returns a field mask of the fields of msg that the Option fields
+// of the struct patch provide.
+// Fields of patch are matched to those of msg by JSON name, which may be
+// either the JSON name of the protobuf field or its proto name. A provided
+// Option holding a struct of Options adds the fields it provides, as in
+// "address.city", rather than the whole message.
+// If a provided field has no counterpart in msg, FieldMask returns an error.
+func FieldMask(patch any, msg proto.Message) (mask *fieldmaskpb.FieldMask, err error) {
+	present := opt.Presence(patch)
+	if present == nil {
+		return nil, fmt.Errorf("optproto: FieldMask requires a struct patch, got %T", patch)
+	}
+
+	var paths []string
+	for path, exists := range present {
+		if !exists || hasPresentChild(present, path) {
+			continue
+		}
+
+		protoPath, err := resolvePath(msg.ProtoReflect().Descriptor(), path)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, protoPath)
+	}
+
+	slices.Sort(paths)
+	return &fieldmaskpb.FieldMask{Paths: paths}, nil
+}
+
+// hasPresentChild reports whether present holds a provided field nested in
+// the field at path.
+func hasPresentChild(present map[string]bool, path string) (ok bool) {
+	for p, exists := range present {
+		if exists && strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolvePath returns the proto path of the dotted path of JSON names path in
+// messages described by md.
+func resolvePath(md protoreflect.MessageDescriptor, path string) (protoPath string, err error) {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		if md == nil {
+			return "", fmt.Errorf("optproto: field %s is not a message", strings.Join(parts[:i], "."))
+		}
+
+		fd := md.Fields().ByJSONName(part)
+		if fd == nil {
+			fd = md.Fields().ByName(protoreflect.Name(part))
+		}
+
+		if fd == nil {
+			return "", fmt.Errorf("optproto: no field %s in %s", path, md.FullName())
+		}
+
+		parts[i] = string(fd.Name())
+
+		md = nil
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			md = fd.Message()
+		}
+	}
+
+	return strings.Join(parts, "."), nil
+}
+
+// ApplyMask sets the fields of msg listed by mask to the values of the Option
+// fields of the struct patch, matched as they are by FieldMask.
+// A field listed by mask whose Option is not provided is cleared.
+// Values are converted as protojson decodes the JSON encoding of patch written
+// by opt.Marshal, so time.Time values set Timestamp fields and strings set
+// enum fields by name.
+// If mask lists a field msg does not have, or patch has provided fields msg
+// does not have or values that cannot be converted, ApplyMask returns an error
+// and leaves msg unchanged.
+func ApplyMask(msg proto.Message, mask *fieldmaskpb.FieldMask, patch any) (err error) {
+	if !mask.IsValid(msg) {
+		return fmt.Errorf("optproto: invalid field mask %v for %s", mask.GetPaths(), msg.ProtoReflect().Descriptor().FullName())
+	}
+
+	data, err := opt.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	src := msg.ProtoReflect().New()
+	if err = protojson.Unmarshal(data, src.Interface()); err != nil {
+		return fmt.Errorf("optproto: ApplyMask: %w", err)
+	}
+
+	for _, path := range mask.GetPaths() {
+		copyPath(msg.ProtoReflect(), src, strings.Split(path, "."))
+	}
+
+	return nil
+}
+
+// copyPath copies the field at the proto path parts from src to dst, clearing
+// it in dst if src does not have it.
+func copyPath(dst, src protoreflect.Message, parts []string) {
+	fd := dst.Descriptor().Fields().ByName(protoreflect.Name(parts[0]))
+	if len(parts) > 1 {
+		copyPath(dst.Mutable(fd).Message(), src.Get(fd).Message(), parts[1:])
+		return
+	}
+
+	if !src.Has(fd) {
+		dst.Clear(fd)
+		return
+	}
+
+	dst.Set(fd, src.Get(fd))
+}
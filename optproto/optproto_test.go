@@ -0,0 +1,172 @@
+package optproto_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optproto"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+func Test_FromWrappers(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := map[string]any{
+		"StringValue":     optproto.FromStringValue(wrapperspb.String("ada")),
+		"Empty string":    optproto.FromStringValue(wrapperspb.String("")),
+		"Nil StringValue": optproto.FromStringValue(nil),
+		"BoolValue":       optproto.FromBoolValue(wrapperspb.Bool(false)),
+		"Int32Value":      optproto.FromInt32Value(wrapperspb.Int32(-1)),
+		"Int64Value":      optproto.FromInt64Value(wrapperspb.Int64(0)),
+		"UInt32Value":     optproto.FromUInt32Value(wrapperspb.UInt32(1)),
+		"UInt64Value":     optproto.FromUInt64Value(wrapperspb.UInt64(2)),
+		"FloatValue":      optproto.FromFloatValue(wrapperspb.Float(1.5)),
+		"DoubleValue":     optproto.FromDoubleValue(wrapperspb.Double(2.5)),
+		"BytesValue":      optproto.FromBytesValue(wrapperspb.Bytes([]byte("ada"))),
+		"Nil Int64Value":  optproto.FromInt64Value(nil),
+		"Timestamp":       optproto.FromTimestamp(timestamppb.New(ts)),
+		"Nil Timestamp":   optproto.FromTimestamp(nil),
+		"Duration":        optproto.FromDuration(durationpb.New(90 * time.Second)),
+		"Nil Duration":    optproto.FromDuration(nil),
+	}
+
+	for n, o := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, o)
+		})
+	}
+}
+
+func Test_ToWrappers(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := map[string]any{
+		"StringValue":     optproto.ToStringValue(opt.Some("ada")).GetValue(),
+		"Nil StringValue": optproto.ToStringValue(opt.None[string]()) == nil,
+		"BoolValue":       optproto.ToBoolValue(opt.Some(false)).GetValue(),
+		"Int32Value":      optproto.ToInt32Value(opt.Some[int32](-1)).GetValue(),
+		"Int64Value":      optproto.ToInt64Value(opt.Some[int64](0)).GetValue(),
+		"UInt32Value":     optproto.ToUInt32Value(opt.Some[uint32](1)).GetValue(),
+		"UInt64Value":     optproto.ToUInt64Value(opt.Some[uint64](2)).GetValue(),
+		"FloatValue":      optproto.ToFloatValue(opt.Some[float32](1.5)).GetValue(),
+		"DoubleValue":     optproto.ToDoubleValue(opt.Some(2.5)).GetValue(),
+		"BytesValue":      optproto.ToBytesValue(opt.Some([]byte("ada"))).GetValue(),
+		"Timestamp":       optproto.ToTimestamp(opt.Some(ts)).AsTime(),
+		"Nil Timestamp":   optproto.ToTimestamp(opt.None[time.Time]()) == nil,
+		"Duration":        optproto.ToDuration(opt.Some(90 * time.Second)).AsDuration(),
+		"Nil Duration":    optproto.ToDuration(opt.None[time.Duration]()) == nil,
+	}
+
+	for n, v := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, v)
+		})
+	}
+}
+
+type testSourceContextPatch struct {
+	FileName opt.Option[string] `json:"fileName"`
+}
+
+type testAPIPatch struct {
+	Name          opt.Option[string]                 `json:"name"`
+	Version       opt.Option[string]                 `json:"version"`
+	SourceContext opt.Option[testSourceContextPatch] `json:"sourceContext"`
+	Syntax        opt.Option[string]                 `json:"syntax"`
+}
+
+func Test_FieldMask(t *testing.T) {
+	testCases := map[string]any{
+		"None": testAPIPatch{},
+		"Fields": testAPIPatch{
+			Name:   opt.Some("library"),
+			Syntax: opt.Some("SYNTAX_PROTO3"),
+		},
+		"Nested": testAPIPatch{
+			Version:       opt.Some(""),
+			SourceContext: opt.Some(testSourceContextPatch{FileName: opt.Some("library.proto")}),
+		},
+		"Empty nested": testAPIPatch{
+			SourceContext: opt.Some(testSourceContextPatch{}),
+		},
+		"Unknown field": struct {
+			Owner opt.Option[string] `json:"owner"`
+		}{Owner: opt.Some("ada")},
+	}
+
+	for n, patch := range testCases {
+		t.Run(n, func(t *testing.T) {
+			mask, err := optproto.FieldMask(patch, &apipb.Api{})
+			snaps.MatchSnapshot(t, mask.GetPaths(), err)
+		})
+	}
+}
+
+func Test_ApplyMask(t *testing.T) {
+	// newAPI returns the message the patches are applied to.
+	newAPI := func() (api *apipb.Api) {
+		return &apipb.Api{
+			Name:          "library",
+			Version:       "v1",
+			SourceContext: &sourcecontextpb.SourceContext{FileName: "library.proto"},
+		}
+	}
+
+	testCases := map[string]struct {
+		paths []string
+		patch testAPIPatch
+	}{
+		"Set": {
+			paths: []string{"version", "syntax"},
+			patch: testAPIPatch{Version: opt.Some("v2"), Syntax: opt.Some("SYNTAX_PROTO3")},
+		},
+		"Clear": {
+			paths: []string{"version", "source_context.file_name"},
+			patch: testAPIPatch{},
+		},
+		"Unmasked": {
+			paths: []string{"name"},
+			patch: testAPIPatch{Name: opt.Some("shelves"), Version: opt.Some("v2")},
+		},
+		"Nested": {
+			paths: []string{"source_context.file_name"},
+			patch: testAPIPatch{SourceContext: opt.Some(testSourceContextPatch{FileName: opt.Some("shelf.proto")})},
+		},
+		"Invalid mask": {
+			paths: []string{"owner"},
+			patch: testAPIPatch{},
+		},
+		"Invalid value": {
+			paths: []string{"syntax"},
+			patch: testAPIPatch{Syntax: opt.Some("SYNTAX_PROTO4")},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			api := newAPI()
+			err := optproto.ApplyMask(api, &fieldmaskpb.FieldMask{Paths: tc.paths}, tc.patch)
+			snaps.MatchSnapshot(t,
+				api.GetName(),
+				api.GetVersion(),
+				api.GetSourceContext().GetFileName(),
+				api.GetSyntax().String(),
+				err != nil,
+			)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Package optsql builds the SET clause of partial SQL UPDATE statements from
+// structs holding Option fields, assigning only the fields that were provided:
+//
+//	clauses, args, err := optsql.Set(patch, optsql.WithAllowed("name", "email"))
+//	if err != nil {
+//		return err
+//	}
+//
+//	query := "UPDATE users SET " + strings.Join(clauses, ", ") + " WHERE id = ?"
+//	_, err = db.ExecContext(ctx, query, append(args, id)...)
+//
+// Columns are named as they are by opt.ToSetMap: by the db tag of the field,
+// or else by its JSON name.
+package optsql
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/fletcharoo/opt"
+)
+
+var (
+	// ErrNoColumns is returned by Set when none of the Option fields were
+	// provided, as an UPDATE statement needs at least one assignment.
+	ErrNoColumns = errors.New("optsql: no columns to set")
+
+	// ErrColumnNotAllowed is returned by Set when a provided Option field names
+	// a column missing from the allowlist given with WithAllowed.
+	ErrColumnNotAllowed = errors.New("optsql: column not allowed")
+)
+
+// Placeholder returns the bind parameter for the nth argument of a statement,
+// counting from 1.
+type Placeholder func(n int) string
+
+// Question writes every bind parameter as "?", as MySQL and SQLite expect.
+func Question(n int) (placeholder string) {
+	return "?"
+}
+
+// Dollar writes bind parameters as "$1", "$2" and so on, as PostgreSQL
+// expects.
+func Dollar(n int) (placeholder string) {
+	return "$" + strconv.Itoa(n)
+}
+
+// setConfig holds the settings of Set.
+type setConfig struct {
+	// allowed holds the columns that may be set, or nil if any column may be.
+	allowed map[string]bool
+
+	// placeholder writes the bind parameters.
+	placeholder Placeholder
+
+	// offset is the number of arguments bound before the SET clause.
+	offset int
+}
+
+// SetOption configures Set.
+type SetOption func(c *setConfig)
+
+// WithAllowed limits the columns Set may assign to cols. If a column outside
+// of cols is provided, Set returns an error wrapping ErrColumnNotAllowed
+// rather than leaving it out, so that a request trying to change a read-only
+// column is rejected.
+// WithAllowed may be given more than once to add to the allowlist.
+func WithAllowed(cols ...string) (option SetOption) {
+	return func(c *setConfig) {
+		if c.allowed == nil {
+			c.allowed = map[string]bool{}
+		}
+
+		for _, col := range cols {
+			c.allowed[col] = true
+		}
+	}
+}
+
+// WithPlaceholder writes bind parameters with p instead of Question.
+func WithPlaceholder(p Placeholder) (option SetOption) {
+	return func(c *setConfig) {
+		c.placeholder = p
+	}
+}
+
+// WithOffset numbers the bind parameters of the SET clause after the first n
+// arguments of the statement. It only matters to numbered placeholders such as
+// Dollar.
+func WithOffset(n int) (option SetOption) {
+	return func(c *setConfig) {
+		c.offset = n
+	}
+}
+
+// Set returns an assignment such as "name = ?" for every Option field of the
+// struct v that is provided, along with the arguments to bind to them.
+// v may also be a pointer to a struct.
+// The assignments are sorted by column name so that the text of a statement
+// only depends on which fields were provided, which keeps it cacheable as a
+// prepared statement.
+func Set(v any, opts ...SetOption) (clauses []string, args []any, err error) {
+	c := setConfig{placeholder: Question}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	set := opt.ToSetMap(v)
+	if set == nil {
+		return nil, nil, fmt.Errorf("optsql: Set requires a struct, got %T", v)
+	}
+
+	if len(set) == 0 {
+		return nil, nil, ErrNoColumns
+	}
+
+	cols := slices.Sorted(maps.Keys(set))
+
+	clauses = make([]string, len(cols))
+	args = make([]any, len(cols))
+	for i, col := range cols {
+		if c.allowed != nil && !c.allowed[col] {
+			return nil, nil, fmt.Errorf("%w: %s", ErrColumnNotAllowed, col)
+		}
+
+		clauses[i] = col + " = " + c.placeholder(c.offset+i+1)
+		args[i] = set[col]
+	}
+
+	return clauses, args, nil
+}
@@ -0,0 +1,71 @@
+package optsql_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optsql"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testPatch struct {
+	ID    int64              `db:"id"`
+	Name  opt.Option[string] `db:"name"`
+	Email opt.Option[string] `json:"email"`
+	Age   opt.Option[int]    `db:"age"`
+	Role  opt.Option[string] `db:"role"`
+}
+
+func Test_Set(t *testing.T) {
+	patch := testPatch{
+		ID:    1,
+		Name:  opt.Some("Ada"),
+		Email: opt.Some("ada@example.com"),
+		Age:   opt.Some(36),
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		clauses, args, err := optsql.Set(&patch)
+		snaps.MatchSnapshot(t, clauses, args, fmt.Sprint(err))
+	})
+
+	t.Run("Dollar with offset", func(t *testing.T) {
+		clauses, args, err := optsql.Set(patch,
+			optsql.WithPlaceholder(optsql.Dollar),
+			optsql.WithOffset(1),
+		)
+		snaps.MatchSnapshot(t, clauses, args, fmt.Sprint(err))
+	})
+
+	t.Run("Allowed", func(t *testing.T) {
+		clauses, args, err := optsql.Set(patch,
+			optsql.WithAllowed("name", "email"),
+			optsql.WithAllowed("age", "role"),
+		)
+		snaps.MatchSnapshot(t, clauses, args, fmt.Sprint(err))
+	})
+
+	t.Run("Not allowed", func(t *testing.T) {
+		clauses, args, err := optsql.Set(patch, optsql.WithAllowed("name"))
+		snaps.MatchSnapshot(t, clauses, args, fmt.Sprint(err), errors.Is(err, optsql.ErrColumnNotAllowed))
+	})
+
+	t.Run("No columns", func(t *testing.T) {
+		clauses, args, err := optsql.Set(testPatch{ID: 1})
+		snaps.MatchSnapshot(t, clauses, args, errors.Is(err, optsql.ErrNoColumns))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		clauses, args, err := optsql.Set("hello world")
+		snaps.MatchSnapshot(t, clauses, args, fmt.Sprint(err))
+	})
+}
@@ -0,0 +1,81 @@
+// Package optsqlx helps build github.com/jmoiron/sqlx named queries over
+// structs holding Option fields.
+//
+// opt.Option implements sql.Scanner and driver.Valuer, so Option fields can
+// be used with StructScan, Get and Select as they are, and NamedExec binds an
+// Option that was not provided as NULL. To leave such columns out of a
+// statement instead, build its column list with Columns:
+//
+//	cols := optsqlx.Columns(db.Mapper, user)
+//	query := "INSERT INTO users (" + strings.Join(cols, ", ") + ") VALUES (:" + strings.Join(cols, ", :") + ")"
+//	_, err := db.NamedExec(query, user)
+package optsqlx
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+
+	"github.com/fletcharoo/opt"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+var valuerType = reflect.TypeFor[driver.Valuer]()
+
+// Columns returns the named parameters sqlx binds for the struct arg, as
+// mapped by m, leaving out Option fields that were not provided.
+// Nested structs are walked, so their fields are named by path, as in
+// "address.city". Fields of types implementing driver.Valuer, including
+// Options, are not walked. The names are returned in the order sqlx maps
+// fields, breadth first, so fields of nested and embedded structs follow the
+// top-level fields.
+// A nil m uses the default sqlx mapping: the db tag, or the lower cased field
+// name.
+func Columns(m *reflectx.Mapper, arg any) (names []string) {
+	if m == nil {
+		m = reflectx.NewMapperFunc("db", strings.ToLower)
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(arg))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for _, fi := range m.TypeMap(v.Type()).Index {
+		if !isColumn(fi) {
+			continue
+		}
+
+		if w, ok := reflectx.FieldByIndexesReadOnly(v, fi.Index).Interface().(opt.Wrapper); ok {
+			if _, exists := w.AnyValue(); !exists {
+				continue
+			}
+		}
+
+		names = append(names, fi.Path)
+	}
+
+	return names
+}
+
+// isColumn reports whether fi is bound as a single parameter: it is not an
+// embedded struct, it is a leaf or a driver.Valuer, and none of its parents
+// are bound as a single parameter.
+func isColumn(fi *reflectx.FieldInfo) (ok bool) {
+	if fi.Embedded || (len(fi.Children) > 0 && !isValuer(fi.Field.Type)) {
+		return false
+	}
+
+	for p := fi.Parent; p != nil && p.Field.Type != nil; p = p.Parent {
+		if isValuer(p.Field.Type) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValuer reports whether values of t implement driver.Valuer.
+func isValuer(t reflect.Type) (ok bool) {
+	return t.Implements(valuerType) || reflect.PointerTo(t).Implements(valuerType)
+}
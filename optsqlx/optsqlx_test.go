@@ -0,0 +1,83 @@
+package optsqlx_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optsqlx"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testAddress struct {
+	City    opt.Option[string] `db:"city"`
+	Country string             `db:"country"`
+}
+
+type testBase struct {
+	ID int64 `db:"id"`
+}
+
+type testUser struct {
+	testBase
+	Name     opt.Option[string] `db:"name"`
+	Nickname opt.Option[string] `db:"nickname"`
+	Age      opt.Option[int]    `db:"age"`
+	Address  testAddress        `db:"address"`
+	Email    string
+}
+
+var testUsers = map[string]testUser{
+	"Sparse": {testBase: testBase{ID: 1}, Name: opt.Some("Ada"), Age: opt.Some(0)},
+	"Full": {
+		testBase: testBase{ID: 2},
+		Name:     opt.Some("Ada"),
+		Nickname: opt.Some(""),
+		Age:      opt.Some(36),
+		Address:  testAddress{City: opt.Some("London"), Country: "UK"},
+		Email:    "ada@example.com",
+	},
+}
+
+func Test_Columns(t *testing.T) {
+	for n, user := range testUsers {
+		t.Run(n, func(t *testing.T) {
+			cols := optsqlx.Columns(nil, &user)
+			query := "INSERT INTO users (" + strings.Join(cols, ", ") + ") VALUES (:" + strings.Join(cols, ", :") + ")"
+
+			bound, args, err := sqlx.Named(query, user)
+
+			var values []string
+			for _, arg := range args {
+				values = append(values, fmt.Sprint(arg))
+			}
+
+			snaps.MatchSnapshot(t, cols, bound, values, fmt.Sprint(err))
+		})
+	}
+
+	t.Run("Custom mapper", func(t *testing.T) {
+		snaps.MatchSnapshot(t, optsqlx.Columns(reflectx.NewMapper("json"), testUsers["Sparse"]))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		snaps.MatchSnapshot(t, optsqlx.Columns(nil, 1))
+	})
+}
+
+func Test_NamedNull(t *testing.T) {
+	_, args, err := sqlx.Named("UPDATE users SET nickname = :nickname WHERE id = :id", testUsers["Sparse"])
+
+	value, valueErr := args[0].(opt.Option[string]).Value()
+	snaps.MatchSnapshot(t, fmt.Sprint(err), value, fmt.Sprint(valueErr))
+}
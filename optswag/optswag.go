@@ -0,0 +1,136 @@
+// Package optswag lets github.com/swaggo/swag document Option fields by the
+// types they hold.
+//
+// swag reads types from source rather than through reflection, so it describes
+// an Option[T] field as an opaque object named opt.Option-T. Its global
+// overrides can replace each Option type with T instead. Fields are optional
+// in the generated spec unless tagged as required, so Option fields are shown
+// as optional values of T. Overrides lists the overrides for the Option types
+// reachable from a set of values, to be given to swag.SetOverrides, and
+// WriteOverrides writes them as a .swaggo file for swag init, for instance
+// from a small program run by go generate:
+//
+//	f, err := os.Create(".swaggo")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	if err = optswag.WriteOverrides(f, api.User{}, api.Post{}); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// swag names types by the package names used in source, so the opt package
+// must be imported under its own name. swag cannot describe a map held by an
+// Option, so such Options are left out; tag those fields with swaggertype
+// instead.
+package optswag
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/fletcharoo/opt"
+)
+
+// Overrides returns the swag global overrides replacing the Option types
+// reachable from the types of values, through struct fields, pointers, slices,
+// arrays and maps, with the types they hold.
+func Overrides(values ...any) (overrides map[string]string) {
+	c := collector{overrides: map[string]string{}, seen: map[reflect.Type]bool{}}
+	for _, v := range values {
+		if t := reflect.TypeOf(v); t != nil {
+			c.collect(t)
+		}
+	}
+
+	return c.overrides
+}
+
+// WriteOverrides writes the overrides returned by Overrides for values to w in
+// the format of a .swaggo file, sorted by the type replaced.
+func WriteOverrides(w io.Writer, values ...any) (err error) {
+	overrides := Overrides(values...)
+
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		if _, err = fmt.Fprintf(w, "replace %s %s\n", name, overrides[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collector gathers the overrides for the Option types reachable from a type.
+type collector struct {
+	// overrides holds the overrides gathered, keyed by the name swag gives
+	// the Option type.
+	overrides map[string]string
+
+	// seen holds the types already walked, so that recursive types end.
+	seen map[reflect.Type]bool
+}
+
+// collect adds the overrides for t and the Option types reachable from it.
+func (c collector) collect(t reflect.Type) {
+	if c.seen[t] {
+		return
+	}
+	c.seen[t] = true
+
+	if opt.IsOptionType(t) {
+		elem := opt.ElemType(t)
+		name, nameOK := swagName(t)
+		value, valueOK := swagName(elem)
+		if nameOK && valueOK {
+			c.overrides[name] = value
+		}
+
+		c.collect(elem)
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		c.collect(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if f := t.Field(i); f.IsExported() || f.Anonymous {
+				c.collect(f.Type)
+			}
+		}
+	}
+}
+
+// swagName returns the name swag gives t when it appears as a type argument,
+// reporting false if swag cannot name t. Pointers are dropped and types are
+// qualified by the name of their package.
+func swagName(t reflect.Type) (name string, ok bool) {
+	if opt.IsOptionType(t) {
+		base, _, _ := strings.Cut(t.Name(), "[")
+		elem, ok := swagName(opt.ElemType(t))
+		return "opt." + base + "[" + elem + "]", ok
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return swagName(t.Elem())
+	case reflect.Slice, reflect.Array:
+		elem, ok := swagName(t.Elem())
+		return "[]" + elem, ok
+	}
+
+	if t.Name() == "" || strings.Contains(t.Name(), "[") {
+		// Unnamed maps, structs and interfaces, and instances of other
+		// generic types, have no name swag can match.
+		return "", false
+	}
+
+	// The string of a named type is qualified by its package name, as the
+	// type is written in source.
+	return t.String(), true
+}
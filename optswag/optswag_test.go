@@ -0,0 +1,43 @@
+package optswag_test
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fletcharoo/opt/optswag"
+	"github.com/fletcharoo/opt/optswag/testdata/api"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/swaggo/swag"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+func Test_Overrides(t *testing.T) {
+	snaps.MatchSnapshot(t, optswag.Overrides(api.User{}))
+}
+
+func Test_WriteOverrides(t *testing.T) {
+	var b strings.Builder
+	err := optswag.WriteOverrides(&b, &api.User{}, nil)
+	snaps.MatchSnapshot(t, b.String(), err)
+}
+
+func Test_Overrides_Swag(t *testing.T) {
+	p := swag.New(swag.SetOverrides(optswag.Overrides(api.User{})))
+	if err := p.ParseAPI("testdata/api", "api.go", 100); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.MarshalIndent(p.GetSwagger().Definitions, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snaps.MatchSnapshot(t, string(data))
+}
@@ -0,0 +1,35 @@
+// Package api is an API documented with swag whose models hold Option fields.
+//
+//	@title		Users
+//	@version	1.0
+package api
+
+import (
+	"time"
+
+	"github.com/fletcharoo/opt"
+)
+
+type Address struct {
+	Street   string             `json:"street"`
+	Postcode opt.Option[string] `json:"postcode"`
+}
+
+type User struct {
+	ID      int                          `json:"id" binding:"required"`
+	Name    opt.Option[string]           `json:"name"`
+	Born    opt.Option[time.Time]        `json:"born"`
+	Home    opt.Option[Address]          `json:"home"`
+	Others  opt.Option[[]*Address]       `json:"others"`
+	Nested  opt.Option[opt.Option[int]]  `json:"nested"`
+	Tracked opt.Tracked[bool]            `json:"tracked"`
+	Scores  map[string]opt.Option[int64] `json:"scores"`
+	Extra   opt.Option[map[string]int]   `json:"extra" swaggertype:"object,integer"`
+}
+
+// GetUser godoc
+//
+//	@Summary	Get a user
+//	@Success	200	{object}	User
+//	@Router		/users [get]
+func GetUser() {}
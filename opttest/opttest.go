@@ -0,0 +1,468 @@
+// Package opttest provides a reference in-memory resource server with Option
+// PATCH semantics that services can run contract tests against.
+//
+// A patch is a struct whose fields are Options named, through their JSON tags,
+// after the fields of the resource they update. Applying a patch sets every
+// field whose Option was provided and leaves every other field untouched. An
+// Option holding a pointer, map or slice that was provided as null clears the
+// field.
+package opttest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/fletcharoo/opt"
+)
+
+// ErrNotFound is returned when a resource does not exist in a Store.
+var ErrNotFound = errors.New("opttest: resource not found")
+
+// Store is an in-memory collection of resources of type R keyed by ID.
+// A Store is safe for concurrent use.
+type Store[R any] struct {
+	// mu guards resources.
+	mu sync.RWMutex
+
+	// resources holds the stored resources by ID.
+	resources map[string]R
+}
+
+// NewStore returns an empty Store.
+func NewStore[R any]() (s *Store[R]) {
+	return &Store[R]{
+		resources: map[string]R{},
+	}
+}
+
+// Get returns the resource stored under id, and reports whether it exists.
+func (s *Store[R]) Get(id string) (resource R, exists bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resource, exists = s.resources[id]
+	return resource, exists
+}
+
+// Put stores resource under id, replacing any existing resource.
+func (s *Store[R]) Put(id string, resource R) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resources[id] = resource
+}
+
+// Delete removes the resource stored under id, and reports whether it existed.
+func (s *Store[R]) Delete(id string) (existed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, existed = s.resources[id]
+	delete(s.resources, id)
+	return existed
+}
+
+// Patch applies patch to the resource stored under id and stores the result.
+// If the resource does not exist, Patch returns ErrNotFound.
+func (s *Store[R]) Patch(id string, patch any) (result PatchResult[R], err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resource, exists := s.resources[id]
+	if !exists {
+		return result, ErrNotFound
+	}
+
+	if result, err = Apply(resource, patch); err != nil {
+		return result, err
+	}
+
+	s.resources[id] = result.Resource
+	return result, nil
+}
+
+// PatchResult describes the outcome of applying a patch to a resource.
+type PatchResult[R any] struct {
+	// Resource is the resource after the patch was applied.
+	Resource R `json:"resource"`
+
+	// FieldMask holds the JSON names of the fields provided by the patch.
+	FieldMask []string `json:"fieldMask"`
+
+	// Changed holds the JSON names of the fields whose values were changed by
+	// the patch. A field can be in FieldMask without being changed if the patch
+	// provided the value it already held.
+	Changed []string `json:"changed"`
+}
+
+// Apply returns a copy of resource with patch applied to it.
+// Nested structs provided by the patch are merged into the resource field by
+// field, while every other field provided, maps included, is replaced.
+func Apply[R any](resource R, patch any) (result PatchResult[R], err error) {
+	data, err := opt.Marshal(patch)
+	if err != nil {
+		return result, err
+	}
+
+	if result.FieldMask, err = FieldMask(patch); err != nil {
+		return result, err
+	}
+
+	patched := clone(resource)
+	resetFields(reflect.ValueOf(&patched).Elem(), result.FieldMask)
+
+	if err = json.Unmarshal(data, &patched); err != nil {
+		return result, err
+	}
+
+	if result.Changed, err = Diff(resource, patched); err != nil {
+		return result, err
+	}
+
+	result.Resource = patched
+	return result, nil
+}
+
+// resetFields zeroes the fields of the struct v named by mask that are not
+// structs, so that decoding a patch replaces them rather than merging into
+// them as encoding/json does with maps.
+func resetFields(v reflect.Value, mask []string) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" || (!sf.IsExported() && !sf.Anonymous) {
+			continue
+		}
+
+		if sf.Anonymous && name == "" {
+			resetFields(v.Field(i), mask)
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		if !slices.ContainsFunc(mask, func(m string) bool { return strings.EqualFold(m, name) }) {
+			continue
+		}
+
+		f := v.Field(i)
+		t := f.Type()
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct && f.CanSet() {
+			f.SetZero()
+		}
+	}
+}
+
+// FieldMask returns the sorted JSON names of the top level fields provided by
+// patch.
+func FieldMask(patch any) (mask []string, err error) {
+	fields, err := encodeFields(patch, opt.Marshal)
+	if err != nil {
+		return nil, err
+	}
+
+	mask = make([]string, 0, len(fields))
+	for name := range fields {
+		mask = append(mask, name)
+	}
+
+	slices.Sort(mask)
+	return mask, nil
+}
+
+// Diff returns the sorted JSON names of the top level fields whose encoded
+// values differ between before and after.
+func Diff(before, after any) (changed []string, err error) {
+	beforeFields, err := encodeFields(before, json.Marshal)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := encodeFields(after, json.Marshal)
+	if err != nil {
+		return nil, err
+	}
+
+	changed = []string{}
+	for name, value := range afterFields {
+		if !jsonEqual(beforeFields[name], value) {
+			changed = append(changed, name)
+		}
+	}
+
+	for name := range beforeFields {
+		if _, exists := afterFields[name]; !exists {
+			changed = append(changed, name)
+		}
+	}
+
+	slices.Sort(changed)
+	return changed, nil
+}
+
+// encodeFields encodes v with marshal and splits the resulting JSON object into
+// its top level fields.
+func encodeFields(v any, marshal func(any) ([]byte, error)) (fields map[string]json.RawMessage, err error) {
+	data, err := marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// jsonEqual reports whether a and b encode the same JSON value.
+func jsonEqual(a, b json.RawMessage) (equal bool) {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	var av, bv any
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(av, bv)
+}
+
+// clone returns a deep copy of v, so that applying a patch never changes the
+// stored resource through a pointer, map or slice they share. Unlike a JSON
+// round trip, it keeps the fields that are not encoded, such as unexported
+// fields and those tagged json:"-". Unexported fields are copied as they are,
+// since a patch cannot reach them.
+func clone[R any](v R) (c R) {
+	c = v
+	deepCopy(reflect.ValueOf(&c).Elem(), map[uintptr]reflect.Value{})
+	return c
+}
+
+// optionHook rebuilds the Options copied by deepCopy.
+var optionHook = opt.DecodeHook()
+
+// deepCopy replaces every pointer, map, slice and interface reachable from v
+// through settable values with a copy. copies maps the pointers already copied
+// to their copies, so that pointers shared within v stay shared and pointer
+// cycles are copied as cycles.
+func deepCopy(v reflect.Value, copies map[uintptr]reflect.Value) {
+	if !v.CanSet() {
+		return
+	}
+
+	t := v.Type()
+
+	if opt.IsOptionType(t) && !embedsOption(t) {
+		value, exists := opt.ValueOf(v)
+		if !exists || !hasReferences(value) {
+			return
+		}
+
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		deepCopy(copied, copies)
+
+		o, err := optionHook(copied, v)
+		if err == nil {
+			v.Set(reflect.ValueOf(o))
+		}
+
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+
+		if copied, exists := copies[v.Pointer()]; exists {
+			v.Set(copied)
+			return
+		}
+
+		copied := reflect.New(t.Elem())
+		copies[v.Pointer()] = copied
+		copied.Elem().Set(v.Elem())
+		v.Set(copied)
+		deepCopy(copied.Elem(), copies)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		copied := reflect.New(v.Elem().Type()).Elem()
+		copied.Set(v.Elem())
+		deepCopy(copied, copies)
+		v.Set(copied)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			deepCopy(v.Field(i), copies)
+		}
+	case reflect.Array:
+		for i := range v.Len() {
+			deepCopy(v.Index(i), copies)
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+
+		copied := reflect.MakeSlice(t, v.Len(), v.Len())
+		reflect.Copy(copied, v)
+		v.Set(copied)
+
+		for i := range copied.Len() {
+			deepCopy(copied.Index(i), copies)
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+
+		copied := reflect.MakeMapWithSize(t, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(t.Elem()).Elem()
+			value.Set(iter.Value())
+			deepCopy(value, copies)
+			copied.SetMapIndex(iter.Key(), value)
+		}
+
+		v.Set(copied)
+	}
+}
+
+// embedsOption reports whether the struct type t embeds an Option in an
+// exported field, as Tracked does, in which case deepCopy copies that field
+// and keeps the others.
+func embedsOption(t reflect.Type) (ok bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Anonymous && f.IsExported() && opt.IsOptionType(f.Type) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasReferences reports whether v holds a pointer, map, slice or interface
+// that is not nil, which deepCopy has to copy.
+func hasReferences(v reflect.Value) (ok bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return !v.IsNil()
+	case reflect.Struct, reflect.Array:
+		return true
+	}
+
+	return false
+}
+
+// NewHandler returns an http.Handler serving the resources in store.
+// P is the patch type decoded from PATCH request bodies.
+//
+// The handler serves the following routes:
+//
+//	GET    /{id}  responds with the resource
+//	PUT    /{id}  replaces the resource with the request body
+//	PATCH  /{id}  applies the request body and responds with a PatchResult
+//	DELETE /{id}  deletes the resource
+//
+// Missing resources result in 404 Not Found and undecodable request bodies in
+// 400 Bad Request.
+func NewHandler[R, P any](store *Store[R]) (h http.Handler) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{id}", func(w http.ResponseWriter, r *http.Request) {
+		resource, exists := store.Get(r.PathValue("id"))
+		if !exists {
+			http.Error(w, ErrNotFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resource)
+	})
+
+	mux.HandleFunc("PUT /{id}", func(w http.ResponseWriter, r *http.Request) {
+		var resource R
+		if err := json.NewDecoder(r.Body).Decode(&resource); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		store.Put(r.PathValue("id"), resource)
+		writeJSON(w, http.StatusOK, resource)
+	})
+
+	mux.HandleFunc("PATCH /{id}", func(w http.ResponseWriter, r *http.Request) {
+		var patch P
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result, err := store.Patch(r.PathValue("id"), patch)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, result)
+	})
+
+	mux.HandleFunc("DELETE /{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !store.Delete(r.PathValue("id")) {
+			http.Error(w, ErrNotFound.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return mux
+}
+
+// NewServer starts and returns an httptest.Server serving the resources in
+// store with NewHandler. The caller should call Close when finished.
+func NewServer[R, P any](store *Store[R]) (s *httptest.Server) {
+	return httptest.NewServer(NewHandler[R, P](store))
+}
+
+// writeJSON responds with status and the JSON encoding of v.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
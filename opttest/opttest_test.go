@@ -0,0 +1,148 @@
+package opttest_test
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/opttest"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testAddress struct {
+	City     string `json:"city"`
+	Postcode string `json:"postcode"`
+}
+
+type testResource struct {
+	Name     string            `json:"name"`
+	Email    string            `json:"email"`
+	Nickname *string           `json:"nickname"`
+	Tags     []string          `json:"tags"`
+	Address  testAddress       `json:"address"`
+	Labels   map[string]string `json:"labels"`
+}
+
+type testAddressPatch struct {
+	City     opt.Option[string] `json:"city"`
+	Postcode opt.Option[string] `json:"postcode"`
+}
+
+type testPatch struct {
+	Name     opt.Option[string]            `json:"name"`
+	Email    opt.Option[string]            `json:"email"`
+	Nickname opt.Option[*string]           `json:"nickname"`
+	Tags     opt.Option[[]string]          `json:"tags"`
+	Address  opt.Option[testAddressPatch]  `json:"address"`
+	Labels   opt.Option[map[string]string] `json:"labels"`
+}
+
+const testResourceBody = `{
+	"name": "Ada",
+	"email": "ada@example.com",
+	"nickname": "countess",
+	"tags": ["admin"],
+	"address": {"city": "London", "postcode": "W1"},
+	"labels": {"team": "engines"}
+}`
+
+var patchTestCases = map[string]string{
+	"Empty":          `{}`,
+	"Primitive":      `{"email": "ada@lovelace.dev"}`,
+	"Unchanged":      `{"name": "Ada"}`,
+	"Primitive null": `{"name": null}`,
+	"Pointer null":   `{"nickname": null}`,
+	"Slice":          `{"tags": ["admin", "owner"]}`,
+	"Slice null":     `{"tags": null}`,
+	"Nested":         `{"address": {"postcode": "NW1"}}`,
+	"Map":            `{"labels": {"role": "engineer"}}`,
+	"Map null":       `{"labels": null}`,
+	"Invalid":        `{"name": 42}`,
+}
+
+func Test_Handler(t *testing.T) {
+	for n, body := range patchTestCases {
+		t.Run(n, func(t *testing.T) {
+			server := opttest.NewServer[testResource, testPatch](opttest.NewStore[testResource]())
+			defer server.Close()
+
+			do(t, server.URL, http.MethodPut, "/ada", testResourceBody)
+			do(t, server.URL, http.MethodPatch, "/ada", body)
+			do(t, server.URL, http.MethodGet, "/ada", "")
+		})
+	}
+
+	t.Run("Not found", func(t *testing.T) {
+		server := opttest.NewServer[testResource, testPatch](opttest.NewStore[testResource]())
+		defer server.Close()
+
+		do(t, server.URL, http.MethodGet, "/missing", "")
+		do(t, server.URL, http.MethodPatch, "/missing", `{}`)
+		do(t, server.URL, http.MethodDelete, "/missing", "")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		server := opttest.NewServer[testResource, testPatch](opttest.NewStore[testResource]())
+		defer server.Close()
+
+		do(t, server.URL, http.MethodPut, "/ada", testResourceBody)
+		do(t, server.URL, http.MethodDelete, "/ada", "")
+		do(t, server.URL, http.MethodGet, "/ada", "")
+	})
+}
+
+type testHiddenResource struct {
+	Name     string `json:"name"`
+	Password string `json:"-"`
+	Labels   map[string]string
+	revision int
+}
+
+func Test_Store_Patch(t *testing.T) {
+	store := opttest.NewStore[testHiddenResource]()
+	store.Put("ada", testHiddenResource{
+		Name:     "Ada",
+		Password: "hunter2",
+		Labels:   map[string]string{"team": "engines"},
+		revision: 3,
+	})
+
+	result, err := store.Patch("ada", struct {
+		Name opt.Option[string] `json:"name"`
+	}{Name: opt.Some("Ada Lovelace")})
+
+	stored, _ := store.Get("ada")
+	snaps.MatchSnapshot(t, result, err, stored)
+}
+
+// do sends a request to the server and snapshots the response.
+func do(t *testing.T, url, method, path, body string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Unexpected request error: %s", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected response error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Unexpected read error: %s", err)
+	}
+
+	snaps.MatchSnapshot(t, method+" "+path, resp.StatusCode, string(respBody))
+}
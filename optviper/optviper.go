@@ -0,0 +1,176 @@
+// Package optviper unmarshals github.com/spf13/viper configuration into
+// structs with Option fields, recording which keys were actually configured:
+//
+//	type Config struct {
+//		Port    opt.Option[int]           `mapstructure:"port"`
+//		Timeout opt.Option[time.Duration] `mapstructure:"timeout"`
+//	}
+//
+// Defaults go on a second Viper rather than on the one holding the
+// configuration, because Viper's API cannot tell a key that only has a
+// default from one that is set:
+//
+//	defaults := viper.New()
+//	defaults.SetDefault("port", 8080)
+//
+//	var config Config
+//	err := optviper.Unmarshal(v, defaults, &config)
+//
+// An Option is provided if its key is set in the config file, the
+// environment, a flag, a key/value store or by Viper.Set, even to the zero
+// value of T or to its default, and is not provided if its key is missing or
+// only has a default, including the default value of a flag that was not
+// changed.
+// Fields that are not Options are decoded as Viper.Unmarshal decodes them.
+package optviper
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/fletcharoo/opt"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// keyDelimiter separates the parts of nested keys, as it does by default in
+// Viper.
+const keyDelimiter = "."
+
+// Unmarshal decodes the configuration held by v into the struct target points
+// to, as Viper.Unmarshal does, with the settings of defaults used for keys
+// that v does not set. defaults may be nil.
+// An Option is provided if Viper.IsSet reports its key as set in v, that is by
+// Viper.Set, a flag that was changed, a bound or automatic environment
+// variable, the config file or a key/value store, whatever its value, so that
+// a key set to its default is still provided. Options whose keys are only set
+// in defaults are not provided, while fields that are not Options get their
+// defaults. Defaults registered on v with Viper.SetDefault count as set.
+// Option fields are decoded with opt.DecodeHook, after Viper's decode hooks
+// for time.Duration and string slices, or those given by opts, have converted
+// the value to T.
+func Unmarshal(v, defaults *viper.Viper, target any, opts ...viper.DecoderConfigOption) (err error) {
+	config := &mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+		WeaklyTypedInput: true,
+	}
+
+	for _, o := range opts {
+		o(config)
+	}
+
+	config.Result = target
+	inner := config.DecodeHook
+
+	// The first passes decode every setting of v, the default values of
+	// flags included, and then those of defaults, which Viper ranks above
+	// the default values of flags, into the fields that are not Options.
+	// The last decodes the settings set in v into the Options as well.
+	config.DecodeHook = skipOptionsHook(inner)
+	if err = decode(config, v.AllSettings()); err != nil {
+		return err
+	}
+
+	if defaults != nil {
+		if err = decode(config, defaults.AllSettings()); err != nil {
+			return err
+		}
+	}
+
+	config.DecodeHook = optionHook(inner)
+	return decode(config, settings(v, defaults))
+}
+
+// decode decodes input as config describes.
+func decode(config *mapstructure.DecoderConfig, input map[string]any) (err error) {
+	decoder, err := mapstructure.NewDecoder(config)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(input)
+}
+
+// skipOptionsHook returns a decode hook that leaves Options as they are and
+// runs inner on the data decoded into other types.
+func skipOptionsHook(inner mapstructure.DecodeHookFunc) (hook func(from, to reflect.Value) (value any, err error)) {
+	return func(from, to reflect.Value) (value any, err error) {
+		if to.IsValid() && opt.IsOptionType(to.Type()) {
+			return to.Interface(), nil
+		}
+
+		if inner == nil {
+			return from.Interface(), nil
+		}
+
+		return mapstructure.DecodeHookExec(inner, from, to)
+	}
+}
+
+// optionHook returns a decode hook that runs inner on the data decoded into an
+// Option as if it was decoded into T, and then opt.DecodeHook.
+func optionHook(inner mapstructure.DecodeHookFunc) (hook func(from, to reflect.Value) (value any, err error)) {
+	optionHook := opt.DecodeHook()
+
+	return func(from, to reflect.Value) (value any, err error) {
+		if !to.IsValid() || !opt.IsOptionType(to.Type()) {
+			if inner == nil {
+				return from.Interface(), nil
+			}
+
+			return mapstructure.DecodeHookExec(inner, from, to)
+		}
+
+		if inner != nil && from.IsValid() && from.Type() != to.Type() {
+			data, err := mapstructure.DecodeHookExec(inner, from, reflect.New(opt.ElemType(to.Type())).Elem())
+			if err != nil {
+				return nil, err
+			}
+
+			from = reflect.ValueOf(data)
+		}
+
+		return optionHook(from, to)
+	}
+}
+
+// settings returns the nested map of the keys of v and defaults that are set
+// in v, and their values.
+func settings(v, defaults *viper.Viper) (m map[string]any) {
+	keys := v.AllKeys()
+	if defaults != nil {
+		keys = append(keys, defaults.AllKeys()...)
+	}
+
+	m = map[string]any{}
+
+	for _, key := range keys {
+		if !v.IsSet(key) {
+			continue
+		}
+
+		value := v.Get(key)
+		if value == nil {
+			continue
+		}
+
+		path := strings.Split(key, keyDelimiter)
+		parent := m
+		for _, part := range path[:len(path)-1] {
+			child, ok := parent[part].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				parent[part] = child
+			}
+
+			parent = child
+		}
+
+		parent[path[len(path)-1]] = value
+	}
+
+	return m
+}
@@ -0,0 +1,154 @@
+package optviper_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optviper"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testDatabase struct {
+	Host opt.Option[string] `mapstructure:"host"`
+	Port opt.Option[int]    `mapstructure:"port"`
+}
+
+type testConfig struct {
+	Port     opt.Option[int]           `mapstructure:"port"`
+	Debug    opt.Option[bool]          `mapstructure:"debug"`
+	Timeout  opt.Option[time.Duration] `mapstructure:"timeout"`
+	Tags     opt.Option[[]string]      `mapstructure:"tags"`
+	Name     opt.Option[string]        `mapstructure:"name"`
+	Database testDatabase              `mapstructure:"database"`
+	Workers  int                       `mapstructure:"workers"`
+}
+
+// newViper returns a Viper reading the YAML config, and a Viper with defaults
+// for every key of testConfig.
+func newViper(t *testing.T, config string) (v, defaults *viper.Viper) {
+	v = viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+
+	defaults = viper.New()
+	defaults.SetDefault("port", 8080)
+	defaults.SetDefault("debug", true)
+	defaults.SetDefault("timeout", "5s")
+	defaults.SetDefault("database.host", "localhost")
+	defaults.SetDefault("workers", 4)
+	return v, defaults
+}
+
+func Test_Unmarshal(t *testing.T) {
+	testCases := map[string]struct {
+		config string
+		setup  func(v *viper.Viper)
+	}{
+		"Defaults only": {},
+		"Config": {
+			config: "port: 0\ndebug: false\ntimeout: 1m\ntags: [a, b]\nname: \"\"\ndatabase:\n  port: 5432\n",
+		},
+		"Config equal to default": {
+			config: "port: 8080\ndatabase:\n  host: localhost\n",
+		},
+		"Set": {
+			setup: func(v *viper.Viper) {
+				v.Set("port", 9090)
+				v.Set("name", "service")
+			},
+		},
+		"Set to default": {
+			setup: func(v *viper.Viper) {
+				v.Set("port", 8080)
+			},
+		},
+		"Environment": {
+			setup: func(v *viper.Viper) {
+				t.Setenv("OPTVIPER_TIMEOUT", "2s")
+				t.Setenv("OPTVIPER_DEBUG", "false")
+				v.SetEnvPrefix("optviper")
+				v.AutomaticEnv()
+			},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			v, defaults := newViper(t, tc.config)
+			if tc.setup != nil {
+				tc.setup(v)
+			}
+
+			var config testConfig
+			err := optviper.Unmarshal(v, defaults, &config)
+			snaps.MatchSnapshot(t, config, err)
+		})
+	}
+
+	t.Run("Flags", func(t *testing.T) {
+		v, defaults := newViper(t, "")
+		defaults.SetDefault("port", 0)
+
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.Int("port", 0, "")
+		flags.Bool("debug", false, "")
+		flags.String("name", "unnamed", "")
+		if err := flags.Parse([]string{"--port=0"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := v.BindPFlags(flags); err != nil {
+			t.Fatal(err)
+		}
+
+		var config testConfig
+		err := optviper.Unmarshal(v, defaults, &config)
+		snaps.MatchSnapshot(t, config, err)
+	})
+
+	t.Run("Bound environment", func(t *testing.T) {
+		v, defaults := newViper(t, "")
+		t.Setenv("APP_DEBUG", "true")
+		t.Setenv("APP_PORT", "")
+		if err := v.BindEnv("debug", "APP_DEBUG"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := v.BindEnv("port", "APP_PORT"); err != nil {
+			t.Fatal(err)
+		}
+
+		var config testConfig
+		err := optviper.Unmarshal(v, defaults, &config)
+		snaps.MatchSnapshot(t, config, err)
+	})
+
+	t.Run("No defaults", func(t *testing.T) {
+		v, _ := newViper(t, "port: 0\n")
+
+		var config testConfig
+		err := optviper.Unmarshal(v, nil, &config)
+		snaps.MatchSnapshot(t, config, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		v, defaults := newViper(t, "port: eighty\n")
+
+		var config testConfig
+		err := optviper.Unmarshal(v, defaults, &config)
+		snaps.MatchSnapshot(t, err != nil)
+	})
+}
@@ -0,0 +1,196 @@
+// Package optzap logs Options with go.uber.org/zap, writing the values that
+// were provided rather than the internals of opt.Option:
+//
+//	logger.Info("user updated",
+//		optzap.Any("nickname", patch.Nickname),
+//		optzap.Object("patch", patch),
+//	)
+//
+// How Options that were not provided are logged is chosen by a Policy, given
+// with WithPolicy. By default they are left out. A struct reached again through
+// a pointer cycle is logged as the string "<cycle>".
+package optzap
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/internal/logwalk"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	objectMarshalerType = reflect.TypeFor[zapcore.ObjectMarshaler]()
+	arrayMarshalerType  = reflect.TypeFor[zapcore.ArrayMarshaler]()
+	stringerType        = reflect.TypeFor[fmt.Stringer]()
+	errorType           = reflect.TypeFor[error]()
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// walker logs structs as objects of their fields unless they implement one of
+// the interfaces zap formats values with.
+var walker = logwalk.Walker{
+	IsOption:    opt.IsOptionType,
+	Leaves:      []reflect.Type{timeType},
+	Interfaces:  []reflect.Type{objectMarshalerType, arrayMarshalerType, stringerType, errorType, jsonMarshalerType, textMarshalerType},
+	Addressable: true,
+}
+
+// Policy decides how an Option that was not provided is logged.
+type Policy int
+
+const (
+	// Skip leaves the field out of the log entry.
+	Skip Policy = iota
+
+	// Null logs the field as null.
+	Null
+
+	// Mark logs the field as the string "<empty>".
+	Mark
+)
+
+// config holds the settings of the fields and marshalers of this package.
+type config struct {
+	// policy decides how Options that were not provided are logged.
+	policy Policy
+}
+
+// FieldOption configures Any, Object and Marshaler.
+type FieldOption func(c *config)
+
+// WithPolicy logs Options that were not provided as p decides instead of
+// leaving them out. It applies to Option fields of nested structs too.
+func WithPolicy(p Policy) (option FieldOption) {
+	return func(c *config) {
+		c.policy = p
+	}
+}
+
+// newConfig returns the config set by opts.
+func newConfig(opts []FieldOption) (c config) {
+	for _, o := range opts {
+		o(&c)
+	}
+
+	return c
+}
+
+// Any returns a zap.Field logging the Option o under key. Any takes an
+// opt.Wrapper so that it accepts any Option[T], and types embedding one such
+// as opt.Tracked[T].
+// If the value is provided, Any logs it as zap.Any does, except that structs
+// are logged as objects of their fields, named by their JSON names, unless
+// their types describe themselves by implementing zapcore.ObjectMarshaler,
+// fmt.Stringer, error, or a JSON or text marshaler.
+// If the value is not provided, Any logs the field as the Policy decides.
+func Any(key string, o opt.Wrapper, opts ...FieldOption) (field zap.Field) {
+	c := newConfig(opts)
+
+	if o == nil {
+		return c.absent(key)
+	}
+
+	value, exists := o.AnyValue()
+	if !exists {
+		return c.absent(key)
+	}
+
+	return c.field(key, reflect.ValueOf(value), nil)
+}
+
+// Object returns a zap.Field logging the struct v under key as an object of
+// its fields, as Marshaler does.
+func Object(key string, v any, opts ...FieldOption) (field zap.Field) {
+	return zap.Object(key, Marshaler(v, opts...))
+}
+
+// Marshaler returns a zapcore.ObjectMarshaler logging the struct v, or a
+// pointer to one, as an object of its fields named by their JSON names.
+// Options that were provided are logged as their values, and Options that were
+// not provided as the Policy decides. Nested structs are logged as nested
+// objects, as Any logs them.
+// If v is not a struct, the marshaler logs it under the key "value".
+func Marshaler(v any, opts ...FieldOption) (marshaler zapcore.ObjectMarshaler) {
+	return structMarshaler{value: reflect.ValueOf(v), config: newConfig(opts)}
+}
+
+// absent returns the field logging an Option that was not provided.
+func (c config) absent(key string) (field zap.Field) {
+	switch c.policy {
+	case Null:
+		return zap.Reflect(key, nil)
+	case Mark:
+		return zap.String(key, "<empty>")
+	default:
+		return zap.Skip()
+	}
+}
+
+// field returns the field logging v, as an object if it is a struct that does
+// not describe itself. The structs in seen are being logged, so v is logged as
+// a cycle if it is one of them.
+func (c config) field(key string, v reflect.Value, seen logwalk.Seen) (field zap.Field) {
+	if !v.IsValid() {
+		return zap.Reflect(key, nil)
+	}
+
+	if opt.IsOptionType(v.Type()) {
+		value, exists := opt.ValueOf(v)
+		if !exists {
+			return c.absent(key)
+		}
+
+		return c.field(key, value, seen)
+	}
+
+	rv, ok := walker.Struct(v)
+	if !ok {
+		return zap.Any(key, v.Interface())
+	}
+
+	if seen.Contains(rv) {
+		return zap.String(key, logwalk.Cycle)
+	}
+
+	return zap.Object(key, structMarshaler{value: rv, config: c, seen: seen})
+}
+
+// structMarshaler logs a struct as an object of its fields.
+type structMarshaler struct {
+	value  reflect.Value
+	config config
+
+	// seen holds the structs being logged by the marshalers this one is
+	// nested in, and is made when the outermost one is marshaled.
+	seen logwalk.Seen
+}
+
+// MarshalLogObject implements the zapcore.ObjectMarshaler interface.
+func (m structMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) (err error) {
+	rv := reflect.Indirect(m.value)
+	if !rv.IsValid() || rv.Kind() != reflect.Struct || opt.IsOptionType(rv.Type()) {
+		m.config.field("value", m.value, m.seen).AddTo(enc)
+		return nil
+	}
+
+	if m.seen == nil {
+		m.seen = logwalk.Seen{}
+	}
+
+	m.seen.Enter(rv)
+	defer m.seen.Leave(rv)
+
+	for name, fv := range walker.Fields(rv) {
+		m.config.field(name, fv, m.seen).AddTo(enc)
+	}
+
+	return nil
+}
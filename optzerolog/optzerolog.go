@@ -0,0 +1,228 @@
+// Package optzerolog logs Options with github.com/rs/zerolog, writing the
+// values that were provided rather than the internals of opt.Option:
+//
+//	logger.Info().
+//		Func(optzerolog.Any("nickname", patch.Nickname)).
+//		Object("patch", optzerolog.Marshaler(patch)).
+//		Msg("user updated")
+//
+// How Options that were not provided are logged is chosen by a Policy, given
+// with WithPolicy. By default they are left out. A struct reached again through
+// a pointer cycle is logged as the string "<cycle>".
+package optzerolog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/internal/logwalk"
+	"github.com/rs/zerolog"
+)
+
+var (
+	objectMarshalerType = reflect.TypeFor[zerolog.LogObjectMarshaler]()
+	arrayMarshalerType  = reflect.TypeFor[zerolog.LogArrayMarshaler]()
+	stringerType        = reflect.TypeFor[fmt.Stringer]()
+	errorType           = reflect.TypeFor[error]()
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+)
+
+// walker logs structs as objects of their fields unless they implement one of
+// the interfaces zerolog formats values with.
+var walker = logwalk.Walker{
+	IsOption:    opt.IsOptionType,
+	Leaves:      []reflect.Type{timeType, durationType},
+	Interfaces:  []reflect.Type{objectMarshalerType, arrayMarshalerType, stringerType, errorType, jsonMarshalerType, textMarshalerType},
+	Addressable: true,
+}
+
+// Policy decides how an Option that was not provided is logged.
+type Policy int
+
+const (
+	// Skip leaves the field out of the log event.
+	Skip Policy = iota
+
+	// Null logs the field as null.
+	Null
+
+	// Mark logs the field as the string "<empty>".
+	Mark
+)
+
+// config holds the settings of the fields and marshalers of this package.
+type config struct {
+	// policy decides how Options that were not provided are logged.
+	policy Policy
+}
+
+// FieldOption configures Any, Object and Marshaler.
+type FieldOption func(c *config)
+
+// WithPolicy logs Options that were not provided as p decides instead of
+// leaving them out. It applies to Option fields of nested structs too.
+func WithPolicy(p Policy) (option FieldOption) {
+	return func(c *config) {
+		c.policy = p
+	}
+}
+
+// newConfig returns the config set by opts.
+func newConfig(opts []FieldOption) (c config) {
+	for _, o := range opts {
+		o(&c)
+	}
+
+	return c
+}
+
+// Any returns a function, to be passed to zerolog.Event.Func, logging the
+// Option o under key. Any takes an opt.Wrapper so that it accepts any
+// Option[T], and types embedding one such as opt.Tracked[T].
+// If the value is provided, Any logs it as zerolog.Event.Interface does,
+// except that structs are logged as objects of their fields, named by their
+// JSON names, unless their types describe themselves by implementing
+// zerolog.LogObjectMarshaler, fmt.Stringer, error, or a JSON or text
+// marshaler. Times and durations are logged as zerolog formats them.
+// If the value is not provided, Any logs the field as the Policy decides.
+func Any(key string, o opt.Wrapper, opts ...FieldOption) (fn func(e *zerolog.Event)) {
+	c := newConfig(opts)
+
+	return func(e *zerolog.Event) {
+		if o == nil {
+			c.absent(e, key)
+			return
+		}
+
+		value, exists := o.AnyValue()
+		if !exists {
+			c.absent(e, key)
+			return
+		}
+
+		c.field(e, key, reflect.ValueOf(value), nil)
+	}
+}
+
+// Object returns a function, to be passed to zerolog.Event.Func, logging the
+// struct v under key as an object of its fields, as Marshaler does.
+func Object(key string, v any, opts ...FieldOption) (fn func(e *zerolog.Event)) {
+	m := Marshaler(v, opts...)
+
+	return func(e *zerolog.Event) {
+		e.Object(key, m)
+	}
+}
+
+// Marshaler returns a zerolog.LogObjectMarshaler logging the struct v, or a
+// pointer to one, as an object of its fields named by their JSON names.
+// Options that were provided are logged as their values, and Options that were
+// not provided as the Policy decides. Nested structs are logged as nested
+// objects, as Any logs them.
+// If v is not a struct, the marshaler logs it under the key "value".
+func Marshaler(v any, opts ...FieldOption) (marshaler zerolog.LogObjectMarshaler) {
+	return structMarshaler{value: reflect.ValueOf(v), config: newConfig(opts)}
+}
+
+// absent logs an Option that was not provided to e.
+func (c config) absent(e *zerolog.Event, key string) {
+	switch c.policy {
+	case Null:
+		e.Interface(key, nil)
+	case Mark:
+		e.Str(key, "<empty>")
+	}
+}
+
+// field logs v to e, as an object if it is a struct that does not describe
+// itself. The structs in seen are being logged, so v is logged as a cycle if
+// it is one of them.
+func (c config) field(e *zerolog.Event, key string, v reflect.Value, seen logwalk.Seen) {
+	if !v.IsValid() {
+		e.Interface(key, nil)
+		return
+	}
+
+	if opt.IsOptionType(v.Type()) {
+		value, exists := opt.ValueOf(v)
+		if !exists {
+			c.absent(e, key)
+			return
+		}
+
+		c.field(e, key, value, seen)
+		return
+	}
+
+	rv, ok := walker.Struct(v)
+	switch {
+	case !rv.IsValid():
+		e.Interface(key, nil)
+	case !ok:
+		value(e, key, v.Interface())
+	case seen.Contains(rv):
+		e.Str(key, logwalk.Cycle)
+	default:
+		e.Object(key, structMarshaler{value: rv, config: c, seen: seen})
+	}
+}
+
+// value logs the value v, which is not a nil pointer, to e with the method of
+// zerolog.Event suiting its type.
+func value(e *zerolog.Event, key string, v any) {
+	switch v := v.(type) {
+	case zerolog.LogObjectMarshaler:
+		e.Object(key, v)
+	case zerolog.LogArrayMarshaler:
+		e.Array(key, v)
+	case time.Time:
+		e.Time(key, v)
+	case time.Duration:
+		e.Dur(key, v)
+	case error:
+		e.AnErr(key, v)
+	case json.Marshaler, encoding.TextMarshaler:
+		e.Interface(key, v)
+	case fmt.Stringer:
+		e.Stringer(key, v)
+	default:
+		e.Interface(key, v)
+	}
+}
+
+// structMarshaler logs a struct as an object of its fields.
+type structMarshaler struct {
+	value  reflect.Value
+	config config
+
+	// seen holds the structs being logged by the marshalers this one is
+	// nested in, and is made when the outermost one is marshaled.
+	seen logwalk.Seen
+}
+
+// MarshalZerologObject implements the zerolog.LogObjectMarshaler interface.
+func (m structMarshaler) MarshalZerologObject(e *zerolog.Event) {
+	rv := reflect.Indirect(m.value)
+	if !rv.IsValid() || rv.Kind() != reflect.Struct || opt.IsOptionType(rv.Type()) {
+		m.config.field(e, "value", m.value, m.seen)
+		return
+	}
+
+	if m.seen == nil {
+		m.seen = logwalk.Seen{}
+	}
+
+	m.seen.Enter(rv)
+	defer m.seen.Leave(rv)
+
+	for name, fv := range walker.Fields(rv) {
+		m.config.field(e, name, fv, m.seen)
+	}
+}
@@ -0,0 +1,141 @@
+package optzerolog_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/fletcharoo/opt/optzerolog"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/rs/zerolog"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+type testAddress struct {
+	City     opt.Option[string] `json:"city"`
+	Postcode opt.Option[string] `json:"postcode"`
+}
+
+type testBase struct {
+	ID int `json:"id"`
+}
+
+type testUser struct {
+	testBase
+	Name     opt.Option[string]      `json:"name"`
+	Nickname opt.Option[string]      `json:"nickname"`
+	Age      opt.Option[int]         `json:"age"`
+	Address  opt.Option[testAddress] `json:"address"`
+	Seen     opt.Tracked[time.Time]  `json:"seen"`
+	Tags     []string                `json:"tags,omitempty"`
+	Password string                  `json:"-"`
+	internal string
+}
+
+var testUserValue = testUser{
+	testBase: testBase{ID: 1},
+	Name:     opt.Some("Ada"),
+	Age:      opt.Some(0),
+	Address:  opt.Some(testAddress{City: opt.Some("London")}),
+	Password: "secret",
+	internal: "internal",
+}
+
+// logOutput returns the JSON lines logged by fn.
+func logOutput(fn func(logger zerolog.Logger)) (output string) {
+	var buf bytes.Buffer
+
+	fn(zerolog.New(&buf))
+	return buf.String()
+}
+
+func Test_Any(t *testing.T) {
+	testCases := map[string]opt.Wrapper{
+		"Some":        opt.Some("hello world"),
+		"Some zero":   opt.Some(0),
+		"None":        opt.None[string](),
+		"Nil":         nil,
+		"Struct":      opt.Some(testAddress{City: opt.Some("London")}),
+		"Pointer":     opt.Some(&testAddress{Postcode: opt.Some("W1")}),
+		"Nil pointer": opt.Some[*testAddress](nil),
+		"Time":        opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		"Duration":    opt.Some(time.Second),
+		"Slice":       opt.Some([]opt.Option[int]{opt.Some(1), opt.None[int]()}),
+		"Nested":      opt.Some(opt.None[int]()),
+		"Tracked":     opt.Tracked[string]{Option: opt.Some("tracked")},
+	}
+
+	for n, o := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, logOutput(func(logger zerolog.Logger) {
+				logger.Info().Func(optzerolog.Any("value", o)).Msg("skip")
+				logger.Info().Func(optzerolog.Any("value", o, optzerolog.WithPolicy(optzerolog.Null))).Msg("null")
+				logger.Info().Func(optzerolog.Any("value", o, optzerolog.WithPolicy(optzerolog.Mark))).Msg("mark")
+			}))
+		})
+	}
+}
+
+func Test_Object(t *testing.T) {
+	testCases := map[string]any{
+		"Struct":     testUserValue,
+		"Pointer":    &testUserValue,
+		"Empty":      testUser{},
+		"Not struct": 42,
+		"Option":     opt.Some(42),
+	}
+
+	for n, v := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, logOutput(func(logger zerolog.Logger) {
+				logger.Info().Func(optzerolog.Object("user", v)).Msg("skip")
+				logger.Info().Func(optzerolog.Object("user", v, optzerolog.WithPolicy(optzerolog.Null))).Msg("null")
+				logger.Info().Func(optzerolog.Object("user", v, optzerolog.WithPolicy(optzerolog.Mark))).Msg("mark")
+			}))
+		})
+	}
+}
+
+func Test_Marshaler(t *testing.T) {
+	snaps.MatchSnapshot(t, logOutput(func(logger zerolog.Logger) {
+		logger.Info().EmbedObject(optzerolog.Marshaler(testUserValue)).Msg("embed")
+	}))
+}
+
+type testNode struct {
+	Name   string                `json:"name"`
+	Parent opt.Option[*testNode] `json:"parent"`
+	Next   *testNode             `json:"next"`
+}
+
+func Test_Cycle(t *testing.T) {
+	leaf := &testNode{Name: "leaf"}
+	shared := &testNode{Name: "shared", Parent: opt.Some(leaf), Next: leaf}
+
+	root := &testNode{Name: "root"}
+	child := &testNode{Name: "child", Parent: opt.Some(root)}
+	root.Next = child
+
+	testCases := map[string]*testNode{
+		"Shared": shared,
+		"Cycle":  root,
+		"Self":   {Name: "self"},
+	}
+	testCases["Self"].Next = testCases["Self"]
+
+	for n, node := range testCases {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, logOutput(func(logger zerolog.Logger) {
+				logger.Info().Func(optzerolog.Any("node", opt.Some(node))).Msg("any")
+				logger.Info().Func(optzerolog.Object("node", node)).Msg("object")
+			}))
+		})
+	}
+}
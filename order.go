@@ -0,0 +1,52 @@
+package opt
+
+import "cmp"
+
+// Ordered compares Options holding values of an ordered type, for use with
+// slices.SortFunc, slices.BinarySearchFunc and the Less method of a
+// container/heap implementation.
+// Options that are provided compare by their values. Options that are not
+// provided compare equal to each other and sort after every provided Option,
+// or before them if NoneFirst is set, so the zero Ordered places None last.
+type Ordered[T cmp.Ordered] struct {
+	// NoneFirst places Options that are not provided before every provided
+	// Option instead of after.
+	NoneFirst bool
+}
+
+// Compare returns -1 if a sorts before b, 1 if a sorts after b and 0
+// otherwise.
+func (ord Ordered[T]) Compare(a, b Option[T]) (c int) {
+	return compareOptions(a, b, cmp.Compare[T], ord.NoneFirst)
+}
+
+// Less reports whether a sorts before b.
+func (ord Ordered[T]) Less(a, b Option[T]) (less bool) {
+	return ord.Compare(a, b) < 0
+}
+
+// CompareFunc returns a comparison function for Options of a type that is not
+// ordered, such as time.Time, where compare orders the provided values.
+// Options that are not provided compare equal to each other and sort after
+// every provided Option, or before them if noneFirst is true.
+func CompareFunc[T any](compare func(a, b T) int, noneFirst bool) (f func(a, b Option[T]) int) {
+	return func(a, b Option[T]) int {
+		return compareOptions(a, b, compare, noneFirst)
+	}
+}
+
+// compareOptions does the work for Ordered and CompareFunc.
+func compareOptions[T any](a, b Option[T], compare func(a, b T) int, noneFirst bool) (c int) {
+	switch {
+	case a.exists && b.exists:
+		return compare(a.value, b.value)
+	case a.exists == b.exists:
+		return 0
+	case a.exists == noneFirst:
+		// Exactly one Option is provided: a sorts last if it is the provided one
+		// and None goes first, or if it is None and None goes last.
+		return 1
+	}
+
+	return -1
+}
@@ -0,0 +1,90 @@
+package opt_test
+
+import (
+	"container/heap"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+var orderTestValues = []opt.Option[int]{
+	opt.Some(3),
+	opt.None[int](),
+	opt.Some(-1),
+	opt.Some(0),
+	opt.None[int](),
+	opt.Some(2),
+}
+
+// deadlineQueue is a container/heap of optional deadlines.
+type deadlineQueue []opt.Option[time.Time]
+
+var compareDeadlines = opt.CompareFunc(time.Time.Compare, false)
+
+func (q deadlineQueue) Len() int           { return len(q) }
+func (q deadlineQueue) Less(i, j int) bool { return compareDeadlines(q[i], q[j]) < 0 }
+func (q deadlineQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *deadlineQueue) Push(x any)        { *q = append(*q, x.(opt.Option[time.Time])) }
+
+func (q *deadlineQueue) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
+
+func Test_Ordered(t *testing.T) {
+	t.Run("None last", func(t *testing.T) {
+		values := slices.Clone(orderTestValues)
+		slices.SortFunc(values, opt.Ordered[int]{}.Compare)
+		snaps.MatchSnapshot(t, values)
+	})
+
+	t.Run("None first", func(t *testing.T) {
+		values := slices.Clone(orderTestValues)
+		slices.SortFunc(values, opt.Ordered[int]{NoneFirst: true}.Compare)
+		snaps.MatchSnapshot(t, values)
+	})
+
+	t.Run("Binary search", func(t *testing.T) {
+		ord := opt.Ordered[int]{}
+		values := slices.Clone(orderTestValues)
+		slices.SortFunc(values, ord.Compare)
+
+		found, foundOK := slices.BinarySearchFunc(values, opt.Some(2), ord.Compare)
+		none, noneOK := slices.BinarySearchFunc(values, opt.None[int](), ord.Compare)
+		missing, missingOK := slices.BinarySearchFunc(values, opt.Some(1), ord.Compare)
+		snaps.MatchSnapshot(t, found, foundOK, none, noneOK, missing, missingOK)
+	})
+
+	t.Run("Less", func(t *testing.T) {
+		ord := opt.Ordered[string]{}
+		snaps.MatchSnapshot(t,
+			ord.Less(opt.Some("a"), opt.Some("b")),
+			ord.Less(opt.Some("b"), opt.None[string]()),
+			ord.Less(opt.None[string](), opt.Some("b")),
+			ord.Less(opt.None[string](), opt.None[string]()),
+		)
+	})
+
+	t.Run("Heap", func(t *testing.T) {
+		base := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		q := &deadlineQueue{}
+		heap.Push(q, opt.None[time.Time]())
+		heap.Push(q, opt.Some(base.Add(2*time.Hour)))
+		heap.Push(q, opt.None[time.Time]())
+		heap.Push(q, opt.Some(base))
+		heap.Push(q, opt.Some(base.Add(time.Hour)))
+
+		var order []string
+		for q.Len() > 0 {
+			order = append(order, heap.Pop(q).(opt.Option[time.Time]).String())
+		}
+
+		snaps.MatchSnapshot(t, order)
+	})
+}
@@ -0,0 +1,44 @@
+package opt
+
+import (
+	"reflect"
+)
+
+// UnmarshalParam implements the BindUnmarshaler interfaces of
+// github.com/gin-gonic/gin and github.com/labstack/echo so an Option can be
+// bound from a query parameter, form value or path parameter.
+// Binders that find several values pass only the first; see UnmarshalParams.
+// It is called only for parameters that are present, so UnmarshalParam
+// always sets exists to true, even if param is empty, and an absent parameter
+// leaves the Option untouched.
+// param is converted as it is by DecodeQuery.
+// If param cannot be converted, UnmarshalParam returns an error and leaves o
+// unchanged.
+func (o *Option[T]) UnmarshalParam(param string) (err error) {
+	var value Option[T]
+	if err = assignStrings(reflect.ValueOf(&value).Elem(), []string{param}); err != nil {
+		return err
+	}
+
+	*o = value
+	return nil
+}
+
+// UnmarshalParams implements the multiple value BindUnmarshaler interface of
+// github.com/labstack/echo so an Option of a slice can be bound from every
+// value of a repeated query parameter or form value.
+// It is called only for parameters that are present, so UnmarshalParams always
+// sets exists to true, even if params holds a single empty value.
+// params is converted as it is by DecodeQuery, so slices receive every value
+// and other types the first.
+// If params cannot be converted, UnmarshalParams returns an error and leaves o
+// unchanged.
+func (o *Option[T]) UnmarshalParams(params []string) (err error) {
+	var value Option[T]
+	if err = assignStrings(reflect.ValueOf(&value).Elem(), params); err != nil {
+		return err
+	}
+
+	*o = value
+	return nil
+}
@@ -0,0 +1,68 @@
+package opt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_Option_UnmarshalParam(t *testing.T) {
+	t.Run("String", func(t *testing.T) {
+		testCases := map[string]string{
+			"Value": "ada",
+			"Empty": "",
+		}
+
+		for n, param := range testCases {
+			t.Run(n, func(t *testing.T) {
+				var o opt.Option[string]
+				err := o.UnmarshalParam(param)
+				snaps.MatchSnapshot(t, o, err)
+			})
+		}
+	})
+
+	t.Run("Int", func(t *testing.T) {
+		testCases := map[string]string{
+			"Value":   "42",
+			"Zero":    "0",
+			"Invalid": "forty-two",
+		}
+
+		for n, param := range testCases {
+			t.Run(n, func(t *testing.T) {
+				o := opt.Some(7)
+				err := o.UnmarshalParam(param)
+				snaps.MatchSnapshot(t, o, err)
+			})
+		}
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		var o opt.Option[time.Time]
+		err := o.UnmarshalParam("2024-01-02T03:04:05Z")
+		snaps.MatchSnapshot(t, o, err)
+	})
+}
+
+func Test_Option_UnmarshalParams(t *testing.T) {
+	t.Run("Slice", func(t *testing.T) {
+		var o opt.Option[[]int]
+		err := o.UnmarshalParams([]string{"1", "2", "3"})
+		snaps.MatchSnapshot(t, o, err)
+	})
+
+	t.Run("First value", func(t *testing.T) {
+		var o opt.Option[string]
+		err := o.UnmarshalParams([]string{"a", "b"})
+		snaps.MatchSnapshot(t, o, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		o := opt.Some([]int{7})
+		err := o.UnmarshalParams([]string{"1", "two"})
+		snaps.MatchSnapshot(t, o, err)
+	})
+}
@@ -0,0 +1,142 @@
+package opt
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Patch holds the fields of the struct type T that were provided by a partial
+// update, such as the body of an HTTP PATCH request, without the need to
+// declare a struct of Options mirroring T:
+//
+//	var patch opt.Patch[User]
+//	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+//		return err
+//	}
+//
+//	patch.Apply(&user)
+//
+// Fields are named by their JSON names and the aliases of their opt tags, as
+// they are by Unmarshal. The zero Patch holds no fields.
+type Patch[T any] struct {
+	// values maps the JSON name of each field provided to its value, which is
+	// of the type of the field.
+	values map[string]any
+}
+
+// UnmarshalJSON decodes a JSON object into the Patch, recording the fields of
+// T it holds. Members naming no field of T are ignored.
+// A member holding null is recorded too, and sets the field to its zero value
+// when the Patch is applied.
+func (p *Patch[T]) UnmarshalJSON(data []byte) (err error) {
+	t, err := patchType[T]()
+	if err != nil {
+		return err
+	}
+
+	var object map[string]json.RawMessage
+	if err = json.Unmarshal(data, &object); err != nil {
+		if json.Valid(data) {
+			return fmt.Errorf("opt: Patch requires a JSON object, got %.20s", data)
+		}
+
+		return err
+	}
+
+	values := map[string]any{}
+	for _, f := range cachedFields(t) {
+		key, found := lookupKey(object, f.name)
+		for i := 0; !found && i < len(f.aliases); i++ {
+			_, found = object[f.aliases[i]]
+			key = f.aliases[i]
+		}
+
+		if !found {
+			continue
+		}
+
+		raw, err := resolveAliases(object[key], f.typ)
+		if err != nil {
+			return err
+		}
+
+		value := reflect.New(f.typ)
+		if err = json.Unmarshal(raw, value.Interface()); err != nil {
+			return fmt.Errorf("opt: Patch field %s: %w", f.name, err)
+		}
+
+		values[f.name] = value.Elem().Interface()
+	}
+
+	p.values = values
+	return nil
+}
+
+// MarshalJSON encodes the fields the Patch holds as a JSON object.
+func (p Patch[T]) MarshalJSON() (data []byte, err error) {
+	if p.values == nil {
+		return []byte("{}"), nil
+	}
+
+	return Marshal(p.values)
+}
+
+// Fields returns the JSON names of the fields the Patch holds, in the order
+// they are declared in T.
+func (p Patch[T]) Fields() (names []string) {
+	t, err := patchType[T]()
+	if err != nil {
+		return nil
+	}
+
+	for _, f := range cachedFields(t) {
+		if _, ok := p.values[f.name]; ok {
+			names = append(names, f.name)
+		}
+	}
+
+	return names
+}
+
+// Get returns the value of the field named name.
+// If the Patch holds the field, Get returns its value, which is of the type of
+// the field, and ok is true.
+// If the Patch does not hold the field, Get returns nil and ok is false.
+func (p Patch[T]) Get(name string) (value any, ok bool) {
+	value, ok = p.values[name]
+	return value, ok
+}
+
+// Apply sets the fields of the struct dst points to that the Patch holds,
+// leaving the other fields unchanged.
+// Fields of nested structs are replaced as a whole; to update nested structs
+// field by field, declare them as structs of Options and use ApplyTo.
+func (p Patch[T]) Apply(dst *T) {
+	t, err := patchType[T]()
+	if err != nil || dst == nil {
+		return
+	}
+
+	v := reflect.ValueOf(dst).Elem()
+	for _, f := range cachedFields(t) {
+		if value, ok := p.values[f.name]; ok {
+			fv := allocFieldByIndex(v, f.index)
+			if value == nil {
+				fv.SetZero()
+			} else {
+				fv.Set(reflect.ValueOf(value))
+			}
+		}
+	}
+}
+
+// patchType returns the type T of a Patch, or an error if it is not a struct.
+func patchType[T any]() (t reflect.Type, err error) {
+	t = reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct || isOptionType(t) {
+		return nil, fmt.Errorf("opt: Patch requires a struct type, got %s", t)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,84 @@
+package opt_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testPatchUser struct {
+	testApplyMeta
+	Name     string             `json:"name"`
+	Email    string             `json:"email" opt:"aliases=mail"`
+	Age      int                `json:"age"`
+	Nickname *string            `json:"nickname"`
+	Manager  opt.Option[string] `json:"manager"`
+	Address  testApplyAddress   `json:"address"`
+	Tags     []string           `json:"tags"`
+}
+
+func newPatchUser() (user testPatchUser) {
+	nickname := "Countess"
+
+	return testPatchUser{
+		Name:     "Ada",
+		Email:    "ada@example.com",
+		Age:      36,
+		Nickname: &nickname,
+		Address:  testApplyAddress{City: "London", Postcode: "W1"},
+		Tags:     []string{"admin"},
+	}
+}
+
+func Test_Patch(t *testing.T) {
+	t.Run("Apply", func(t *testing.T) {
+		var patch opt.Patch[testPatchUser]
+
+		err := json.Unmarshal([]byte(`{"AGE": 37, "mail": "ada@lovelace.name", "nickname": null, "manager": "Charles", "address": {"city": "Paris"}, "revision": 2, "unknown": true}`), &patch)
+		if err != nil {
+			t.Fatalf("Unexpected unmarshal error: %s", err)
+		}
+
+		user := newPatchUser()
+		patch.Apply(&user)
+
+		age, ok := patch.Get("age")
+		data, err := json.Marshal(patch)
+		snaps.MatchSnapshot(t, patch.Fields(), age, ok, user, string(data), fmt.Sprint(err))
+	})
+
+	t.Run("Zero", func(t *testing.T) {
+		var patch opt.Patch[testPatchUser]
+
+		user := newPatchUser()
+		patch.Apply(&user)
+
+		name, ok := patch.Get("name")
+		data, err := json.Marshal(patch)
+		snaps.MatchSnapshot(t, patch.Fields(), name, ok, user, string(data), fmt.Sprint(err))
+	})
+
+	t.Run("Type error", func(t *testing.T) {
+		var patch opt.Patch[testPatchUser]
+
+		err := json.Unmarshal([]byte(`{"age": "old"}`), &patch)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Not an object", func(t *testing.T) {
+		var patch opt.Patch[testPatchUser]
+
+		err := json.Unmarshal([]byte(`[1]`), &patch)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		var patch opt.Patch[string]
+
+		err := json.Unmarshal([]byte(`{}`), &patch)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), patch.Fields())
+	})
+}
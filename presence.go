@@ -0,0 +1,58 @@
+package opt
+
+import (
+	"reflect"
+
+	"github.com/fletcharoo/opt/internal/logwalk"
+)
+
+// Presence reports, for every Option field of the struct v, whether it was
+// provided, keyed by JSON name. v may also be a pointer to a struct; Presence
+// returns nil for anything else.
+// Fields of nested structs are keyed by their dotted path, as in
+// "address.city", both for plain struct fields and for provided Options
+// holding a struct. Fields that do not hold an Option are left out, and so are
+// the fields of a struct reached again through a pointer cycle.
+func Presence(v any) (present map[string]bool) {
+	rv := indirectValue(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct || isOptionType(rv.Type()) {
+		return nil
+	}
+
+	present = map[string]bool{}
+	addPresence(present, "", rv, logwalk.Seen{})
+	return present
+}
+
+// addPresence records in present whether each Option field of the struct v,
+// found at path, was provided. seen holds the structs being walked.
+func addPresence(present map[string]bool, path string, v reflect.Value, seen logwalk.Seen) {
+	if seen.Contains(v) {
+		return
+	}
+
+	seen.Enter(v)
+	defer seen.Leave(v)
+
+	for _, f := range cachedFields(v.Type()) {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+
+		fieldPath := joinPath(path, f.name)
+
+		if f.option {
+			value, exists := fv.Interface().(option).AnyValue()
+			present[fieldPath] = exists
+			fv = reflect.ValueOf(value)
+			if !exists {
+				continue
+			}
+		}
+
+		if fv = indirectValue(fv); fv.IsValid() && isPatchStruct(fv.Type()) {
+			addPresence(present, fieldPath, fv, seen)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package opt_test
+
+import (
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testPresence struct {
+	ID       int                          `json:"id"`
+	Name     opt.Option[string]           `json:"name"`
+	Email    opt.Option[string]           `json:"email"`
+	Address  testApplyAddressPatch        `json:"address"`
+	Billing  opt.Option[testAliasAddress] `json:"billing"`
+	Shipping *testApplyAddressPatch       `json:"shipping"`
+}
+
+func Test_Presence(t *testing.T) {
+	t.Run("Struct", func(t *testing.T) {
+		snaps.MatchSnapshot(t, opt.Presence(&testPresence{
+			Name:    opt.Some(""),
+			Address: testApplyAddressPatch{City: opt.Some("London")},
+			Billing: opt.Some(testAliasAddress{Postcode: opt.Some("W1")}),
+		}))
+	})
+
+	t.Run("Nested pointer", func(t *testing.T) {
+		snaps.MatchSnapshot(t, opt.Presence(testPresence{
+			Shipping: &testApplyAddressPatch{Postcode: opt.Some("NW1")},
+		}))
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		node := &testNode{Name: opt.Some("loop")}
+		node.Next = node
+
+		snaps.MatchSnapshot(t, opt.Presence(node))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		snaps.MatchSnapshot(t, opt.Presence(opt.Some("hello world")) == nil)
+	})
+}
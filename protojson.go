@@ -0,0 +1,482 @@
+package opt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	protoTimeType     = reflect.TypeFor[time.Time]()
+	protoDurationType = reflect.TypeFor[time.Duration]()
+)
+
+// MarshalProtoJSON returns the JSON encoding of v following the conventions of
+// google.golang.org/protobuf/encoding/protojson, so that responses built from
+// Options by grpc-gateway shims match the protojson encoding of the same data:
+//
+//   - Options that were not provided are omitted, and provided ones are always
+//     written, like proto3 optional and wrapper fields. A provided nil pointer,
+//     slice or map is written as null.
+//   - Other fields are omitted when they hold their zero value, an empty slice
+//     or an empty map, like proto3 fields without presence.
+//   - int, int64, uint and uint64 values are written as strings, and NaN and
+//     infinite floats as "NaN", "Infinity" and "-Infinity".
+//   - time.Time values are written as Timestamp strings in UTC and
+//     time.Duration values as Duration strings, such as "1.500s".
+//   - <, > and & are not escaped.
+//
+// Field names are taken from JSON tags as they are by Marshal, so they must
+// match the JSON names of the protobuf fields. protojson randomizes the
+// whitespace of its output, which MarshalProtoJSON leaves out.
+// MarshalProtoJSON is ProtoJSONOptions{}.Marshal.
+func MarshalProtoJSON(v any) (data []byte, err error) {
+	return ProtoJSONOptions{}.Marshal(v)
+}
+
+// ProtoJSONOptions configures the protojson compatible encoding written by
+// MarshalProtoJSON.
+type ProtoJSONOptions struct {
+	// EmitUnpopulated writes Options that were not provided as null, and
+	// other fields holding their zero value rather than omitting them, with
+	// nil slices written as [] and nil maps as {}. This matches
+	// protojson.MarshalOptions.EmitUnpopulated, which Twirp servers and
+	// clients use by default.
+	EmitUnpopulated bool
+}
+
+// Marshal returns the JSON encoding of v as MarshalProtoJSON does, following
+// the options of o.
+func (o ProtoJSONOptions) Marshal(v any) (data []byte, err error) {
+	rv := reflect.ValueOf(v)
+
+	if rv.IsValid() {
+		if err = checkType(rv.Type()); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	e := encodeState{w: &buf, protoJSON: true, emitUnpopulated: o.EmitUnpopulated}
+	if err = e.encodeValue(rv); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeProtoValue writes the protojson encoding of v, which is not an Option.
+func (e *encodeState) encodeProtoValue(v reflect.Value) (err error) {
+	t := v.Type()
+
+	switch t {
+	case protoTimeType:
+		e.writeProtoString(formatProtoTimestamp(v.Interface().(time.Time)))
+		return nil
+	case protoDurationType:
+		e.writeProtoString(formatProtoDuration(time.Duration(v.Int())))
+		return nil
+	}
+
+	if isLeafType(t) {
+		return e.encodeLeaf(v)
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			e.w.Write(nullBytes)
+			return nil
+		}
+
+		return e.encodeElem(v)
+	case reflect.Struct:
+		return e.encodeProtoStruct(v)
+	case reflect.Slice:
+		if v.IsNil() {
+			e.w.Write(nullBytes)
+			return nil
+		}
+
+		if t.Elem().Kind() == reflect.Uint8 {
+			return e.encodeLeaf(v)
+		}
+
+		return e.encodeArray(v)
+	case reflect.Array:
+		return e.encodeArray(v)
+	case reflect.Map:
+		if v.IsNil() {
+			e.w.Write(nullBytes)
+			return nil
+		}
+
+		return e.encodeMap(v)
+	case reflect.Int, reflect.Int64:
+		e.writeProtoString(strconv.FormatInt(v.Int(), 10))
+		return nil
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		e.writeProtoString(strconv.FormatUint(v.Uint(), 10))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch f := v.Float(); {
+		case math.IsNaN(f):
+			e.writeProtoString("NaN")
+			return nil
+		case math.IsInf(f, 1):
+			e.writeProtoString("Infinity")
+			return nil
+		case math.IsInf(f, -1):
+			e.writeProtoString("-Infinity")
+			return nil
+		}
+	}
+
+	return e.encodeLeaf(v)
+}
+
+// encodeProtoStruct writes the fields of the struct v, skipping absent Options
+// and other fields holding their zero value.
+func (e *encodeState) encodeProtoStruct(v reflect.Value) (err error) {
+	e.w.WriteByte('{')
+
+	first := true
+	for _, f := range cachedFields(v.Type()) {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+
+		unpopulated := false
+		if f.option {
+			_, exists := fv.Interface().(option).AnyValue()
+			unpopulated = !exists
+		} else {
+			unpopulated = isProtoEmptyValue(fv)
+		}
+
+		if unpopulated && !e.emitUnpopulated {
+			continue
+		}
+
+		if !first {
+			e.w.WriteByte(',')
+		}
+		first = false
+
+		e.w.Write(f.key)
+		e.w.WriteByte(':')
+
+		if unpopulated && !f.option {
+			if ok := e.writeProtoUnpopulated(fv); ok {
+				continue
+			}
+		}
+
+		if err = e.encodeValue(fv); err != nil {
+			return err
+		}
+	}
+
+	e.w.WriteByte('}')
+	return nil
+}
+
+// writeProtoUnpopulated writes the protojson encoding of the nil slice or map
+// v, reporting whether v was one. Other empty values encode as usual.
+func (e *encodeState) writeProtoUnpopulated(v reflect.Value) (ok bool) {
+	switch {
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		e.w.Write([]byte(`""`))
+	case v.Kind() == reflect.Slice:
+		e.w.Write([]byte("[]"))
+	case v.Kind() == reflect.Map:
+		e.w.Write([]byte("{}"))
+	default:
+		return false
+	}
+
+	return true
+}
+
+// isProtoEmptyValue reports whether v holds the zero value of a proto3 field
+// without presence, which protojson leaves out.
+func isProtoEmptyValue(v reflect.Value) (empty bool) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+
+	return v.IsZero()
+}
+
+// writeProtoString writes s as a JSON string.
+func (e *encodeState) writeProtoString(s string) {
+	e.w.WriteByte('"')
+	e.w.Write([]byte(s))
+	e.w.WriteByte('"')
+}
+
+// formatProtoTimestamp formats t as protojson formats a Timestamp: in UTC with
+// 0, 3, 6 or 9 fractional digits.
+func formatProtoTimestamp(t time.Time) (s string) {
+	s = t.UTC().Format("2006-01-02T15:04:05.000000000")
+	return trimProtoNanos(s) + "Z"
+}
+
+// formatProtoDuration formats d as protojson formats a Duration: in seconds
+// with 0, 3, 6 or 9 fractional digits.
+func formatProtoDuration(d time.Duration) (s string) {
+	secs := int64(d / time.Second)
+	nanos := int64(d % time.Second)
+
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		secs, nanos = -secs, -nanos
+	}
+
+	s = fmt.Sprintf("%s%d.%09d", sign, secs, nanos)
+	return trimProtoNanos(s) + "s"
+}
+
+// trimProtoNanos trims the nine fractional digits ending s to the fewest
+// groups of three that keep its precision, dropping the dot if none remain.
+func trimProtoNanos(s string) (trimmed string) {
+	s = strings.TrimSuffix(s, "000")
+	s = strings.TrimSuffix(s, "000")
+	return strings.TrimSuffix(s, ".000")
+}
+
+// UnmarshalProtoJSON parses the protojson encoding data, as written by
+// protojson or by ProtoJSONOptions.Marshal, and stores the result in the value
+// v points to, so that the JSON of Twirp and grpc-gateway services round-trips
+// through structs of Options:
+//
+//   - null leaves an Option unprovided and other fields at their zero value,
+//     and a missing member leaves its field untouched. Any other value
+//     provides an Option, even if it is empty, such as "" or 0.
+//   - Members match fields by JSON name, ignoring case and underscores, so
+//     both the JSON names and the proto names of protobuf fields are
+//     accepted.
+//   - Numbers may be given as strings, including "NaN", "Infinity" and
+//     "-Infinity", bytes as base64 strings, time.Time values as Timestamp
+//     strings and time.Duration values as Duration strings.
+//
+// If a value cannot be converted, UnmarshalProtoJSON returns an error naming
+// the member, and v may be partly set.
+func UnmarshalProtoJSON(data []byte, v any) (err error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("opt: UnmarshalProtoJSON requires a non-nil pointer, got %T", v)
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var src any
+	if err = d.Decode(&src); err != nil {
+		return err
+	}
+
+	return decodeProtoValue(rv.Elem(), src, "")
+}
+
+// decodeProtoValue stores the protojson value src, decoded by encoding/json
+// with numbers kept as json.Number, in dst found at path.
+func decodeProtoValue(dst reflect.Value, src any, path string) (err error) {
+	t := dst.Type()
+
+	if src == nil {
+		dst.SetZero()
+		return nil
+	}
+
+	if isOptionType(t) {
+		value := reflect.New(t).Elem()
+		if err = decodeProtoValue(value.Addr().Interface().(optionSetter).provide(), src, path); err != nil {
+			return err
+		}
+
+		dst.Set(value)
+		return nil
+	}
+
+	if t == protoDurationType {
+		s, ok := src.(string)
+		if !ok {
+			return protoDecodeError(path, conversionError(src, t))
+		}
+
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return protoDecodeError(path, err)
+		}
+
+		dst.SetInt(int64(d))
+		return nil
+	}
+
+	if t != protoTimeType && reflect.PointerTo(t).Implements(unmarshalerType) {
+		data, err := json.Marshal(src)
+		if err != nil {
+			return err
+		}
+
+		if err = json.Unmarshal(data, dst.Addr().Interface()); err != nil {
+			return protoDecodeError(path, err)
+		}
+
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(t.Elem())
+		if err = decodeProtoValue(elem.Elem(), src, path); err != nil {
+			return err
+		}
+
+		dst.Set(elem)
+		return nil
+	case reflect.Interface:
+		if dst.NumMethod() == 0 {
+			dst.Set(reflect.ValueOf(src))
+			return nil
+		}
+	case reflect.Struct:
+		if object, ok := src.(map[string]any); ok && t != protoTimeType {
+			return decodeProtoStruct(dst, object, path)
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			s, ok := src.(string)
+			if !ok {
+				return protoDecodeError(path, conversionError(src, t))
+			}
+
+			b, err := decodeProtoBytes(s)
+			if err != nil {
+				return protoDecodeError(path, err)
+			}
+
+			dst.SetBytes(b)
+			return nil
+		}
+
+		if array, ok := src.([]any); ok {
+			s := reflect.MakeSlice(t, len(array), len(array))
+			for i, elem := range array {
+				if err = decodeProtoValue(s.Index(i), elem, path+"["+strconv.Itoa(i)+"]"); err != nil {
+					return err
+				}
+			}
+
+			dst.Set(s)
+			return nil
+		}
+	case reflect.Map:
+		if object, ok := src.(map[string]any); ok {
+			m := reflect.MakeMapWithSize(t, len(object))
+			for k, elem := range object {
+				key := reflect.New(t.Key()).Elem()
+				if err = assignValue(key, reflect.ValueOf(k)); err != nil {
+					return protoDecodeError(joinPath(path, k), err)
+				}
+
+				value := reflect.New(t.Elem()).Elem()
+				if err = decodeProtoValue(value, elem, joinPath(path, k)); err != nil {
+					return err
+				}
+
+				m.SetMapIndex(key, value)
+			}
+
+			dst.Set(m)
+			return nil
+		}
+	}
+
+	switch src.(type) {
+	case map[string]any, []any:
+		return protoDecodeError(path, conversionError(src, t))
+	}
+
+	if err = assignValue(dst, reflect.ValueOf(src)); err != nil {
+		return protoDecodeError(path, err)
+	}
+
+	return nil
+}
+
+// decodeProtoStruct stores the members of object in the fields of the struct
+// dst found at path.
+func decodeProtoStruct(dst reflect.Value, object map[string]any, path string) (err error) {
+	for _, f := range cachedFields(dst.Type()) {
+		key, found := lookupProtoKey(object, f.name)
+		if !found {
+			continue
+		}
+
+		fv := allocFieldByIndex(dst, f.index)
+		if err = decodeProtoValue(fv, object[key], joinPath(path, f.name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookupProtoKey returns the key of object matching name, preferring an exact
+// match and otherwise ignoring case and underscores, so that "file_name"
+// matches "fileName".
+func lookupProtoKey(object map[string]any, name string) (key string, found bool) {
+	if _, ok := object[name]; ok {
+		return name, true
+	}
+
+	normalized := normalizeProtoName(name)
+	for k := range object {
+		if normalizeProtoName(k) == normalized {
+			return k, true
+		}
+	}
+
+	return "", false
+}
+
+// normalizeProtoName returns name in lower case without underscores.
+func normalizeProtoName(name string) (normalized string) {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// decodeProtoBytes decodes s, which protojson accepts in standard or URL
+// base64, with or without padding.
+func decodeProtoBytes(s string) (b []byte, err error) {
+	enc := base64.StdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.URLEncoding
+	}
+
+	if len(s)%4 != 0 {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+
+	return enc.DecodeString(s)
+}
+
+// protoDecodeError returns the error reported by UnmarshalProtoJSON when the
+// value at path cannot be stored, because of err.
+func protoDecodeError(path string, err error) (wrapped error) {
+	if path == "" {
+		return fmt.Errorf("opt: UnmarshalProtoJSON: %w", err)
+	}
+
+	return fmt.Errorf("opt: UnmarshalProtoJSON %s: %w", path, err)
+}
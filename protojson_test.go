@@ -0,0 +1,244 @@
+package opt_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type testProtoMethod struct {
+	Name             string `json:"name"`
+	RequestTypeURL   string `json:"requestTypeUrl"`
+	RequestStreaming bool   `json:"requestStreaming"`
+}
+
+type testProtoSourceContext struct {
+	FileName string `json:"fileName"`
+}
+
+type testProtoAPI struct {
+	Name          string                              `json:"name"`
+	Methods       []testProtoMethod                   `json:"methods"`
+	Version       opt.Option[string]                  `json:"version"`
+	SourceContext opt.Option[*testProtoSourceContext] `json:"sourceContext"`
+}
+
+// compactProtoJSON returns the protojson encoding of m without whitespace.
+func compactProtoJSON(t *testing.T, m proto.Message) (s string) {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err = json.Compact(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func Test_MarshalProtoJSON(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 500_000_000, time.FixedZone("CET", 3600))
+
+	testCases := map[string]struct {
+		v    any
+		want proto.Message
+	}{
+		"Empty message": {
+			v:    testProtoAPI{},
+			want: &apipb.Api{},
+		},
+		"Message": {
+			v: testProtoAPI{
+				Name:          "library",
+				Methods:       []testProtoMethod{{Name: "GetBook"}, {Name: "<List>", RequestStreaming: true}},
+				Version:       opt.Some("v1"),
+				SourceContext: opt.Some(&testProtoSourceContext{}),
+			},
+			want: &apipb.Api{
+				Name:          "library",
+				Version:       "v1",
+				Methods:       []*apipb.Method{{Name: "GetBook"}, {Name: "<List>", RequestStreaming: true}},
+				SourceContext: &sourcecontextpb.SourceContext{},
+			},
+		},
+		"Int64":     {v: int64(-5), want: wrapperspb.Int64(-5)},
+		"UInt64":    {v: uint64(5), want: wrapperspb.UInt64(5)},
+		"Int32":     {v: int32(5), want: wrapperspb.Int32(5)},
+		"Double":    {v: 0.000001, want: wrapperspb.Double(0.000001)},
+		"NaN":       {v: math.NaN(), want: wrapperspb.Double(math.NaN())},
+		"Infinity":  {v: math.Inf(-1), want: wrapperspb.Double(math.Inf(-1))},
+		"Bytes":     {v: []byte("library"), want: wrapperspb.Bytes([]byte("library"))},
+		"Timestamp": {v: ts, want: timestamppb.New(ts)},
+		"Duration":  {v: -1500 * time.Millisecond, want: durationpb.New(-1500 * time.Millisecond)},
+		"Seconds":   {v: 90 * time.Second, want: durationpb.New(90 * time.Second)},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			data, err := opt.MarshalProtoJSON(tc.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := string(data), compactProtoJSON(t, tc.want); got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+
+			snaps.MatchSnapshot(t, string(data))
+		})
+	}
+
+	t.Run("Provided nil", func(t *testing.T) {
+		data, err := opt.MarshalProtoJSON(testProtoAPI{SourceContext: opt.Some[*testProtoSourceContext](nil)})
+		snaps.MatchSnapshot(t, string(data), err)
+	})
+}
+
+func Test_Encoder_WithProtoJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := opt.NewEncoder(&buf, opt.WithProtoJSON(true)).Encode(testProtoAPI{
+		Name:    "<library>",
+		Version: opt.Some("v1"),
+	})
+	snaps.MatchSnapshot(t, buf.String(), err)
+}
+
+// twirpMarshal and twirpUnmarshal are the protojson options Twirp uses by
+// default.
+var (
+	twirpMarshal   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	twirpUnmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+type testTwirpRequest struct {
+	Name      opt.Option[string]           `json:"name"`
+	Version   opt.Option[string]           `json:"version"`
+	Count     opt.Option[int64]            `json:"count"`
+	Ratio     opt.Option[float64]          `json:"ratio"`
+	Enabled   opt.Option[bool]             `json:"enabled"`
+	Payload   opt.Option[[]byte]           `json:"payload"`
+	CreatedAt opt.Option[time.Time]        `json:"createdAt"`
+	Timeout   opt.Option[time.Duration]    `json:"timeout"`
+	Tags      []string                     `json:"tags"`
+	Labels    map[string]opt.Option[int64] `json:"labels"`
+}
+
+func Test_ProtoJSONOptions_Marshal(t *testing.T) {
+	testCases := map[string]testTwirpRequest{
+		"None": {},
+		"Empty values": {
+			Name:    opt.Some(""),
+			Count:   opt.Some[int64](0),
+			Enabled: opt.Some(false),
+			Payload: opt.Some([]byte{}),
+		},
+		"Values": {
+			Name:      opt.Some("library"),
+			Count:     opt.Some[int64](42),
+			Ratio:     opt.Some(math.Inf(1)),
+			CreatedAt: opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+			Timeout:   opt.Some(1500 * time.Millisecond),
+			Tags:      []string{"a"},
+			Labels:    map[string]opt.Option[int64]{"shelves": opt.Some[int64](3)},
+		},
+	}
+
+	for n, req := range testCases {
+		t.Run(n, func(t *testing.T) {
+			data, err := opt.ProtoJSONOptions{EmitUnpopulated: true}.Marshal(req)
+			snaps.MatchSnapshot(t, string(data), err)
+		})
+	}
+}
+
+func Test_UnmarshalProtoJSON(t *testing.T) {
+	testCases := map[string]string{
+		"Empty":          `{}`,
+		"Null":           `{"name": null, "count": null, "tags": null}`,
+		"Empty values":   `{"name": "", "count": "0", "enabled": false, "payload": ""}`,
+		"Proto names":    `{"created_at": "2024-01-02T03:04:05.500Z", "timeout": "1.500s"}`,
+		"Numbers":        `{"count": "42", "ratio": "NaN", "labels": {"shelves": "3"}}`,
+		"Base64":         `{"payload": "bGlicmFyeQ"}`,
+		"Invalid number": `{"count": "many"}`,
+		"Invalid label":  `{"labels": {"shelves": true}}`,
+	}
+
+	for n, data := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var req testTwirpRequest
+			err := opt.UnmarshalProtoJSON([]byte(data), &req)
+			snaps.MatchSnapshot(t, req, err)
+		})
+	}
+
+	t.Run("Not a pointer", func(t *testing.T) {
+		err := opt.UnmarshalProtoJSON([]byte(`{}`), testTwirpRequest{})
+		snaps.MatchSnapshot(t, err)
+	})
+}
+
+type testTwirpAPI struct {
+	Name          string                              `json:"name"`
+	Version       string                              `json:"version"`
+	Methods       []testProtoMethod                   `json:"methods"`
+	SourceContext opt.Option[*testProtoSourceContext] `json:"sourceContext"`
+}
+
+func Test_ProtoJSON_Twirp_RoundTrip(t *testing.T) {
+	testCases := map[string]testTwirpAPI{
+		"None":  {},
+		"Empty": {SourceContext: opt.Some(&testProtoSourceContext{})},
+		"Values": {
+			Name:          "library",
+			Version:       "v1",
+			Methods:       []testProtoMethod{{Name: "GetBook", RequestStreaming: true}},
+			SourceContext: opt.Some(&testProtoSourceContext{FileName: "library.proto"}),
+		},
+	}
+
+	for n, api := range testCases {
+		t.Run(n, func(t *testing.T) {
+			// A client sends api to a Twirp server, which answers with the
+			// message it decoded.
+			data, err := opt.ProtoJSONOptions{EmitUnpopulated: true}.Marshal(api)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var msg apipb.Api
+			if err = twirpUnmarshal.Unmarshal(data, &msg); err != nil {
+				t.Fatal(err)
+			}
+
+			if data, err = twirpMarshal.Marshal(&msg); err != nil {
+				t.Fatal(err)
+			}
+
+			var got testTwirpAPI
+			if err = opt.UnmarshalProtoJSON(data, &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got.SourceContext, api.SourceContext) {
+				t.Errorf("got source context %v, want %v", got.SourceContext, api.SourceContext)
+			}
+
+			snaps.MatchSnapshot(t, got)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package opt
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// DecodeQuery decodes the query parameters values into the struct target
+// points to. Each exported field is decoded from the parameter named by its
+// query tag, or by the field name if it has none. Fields tagged "-" are
+// skipped, and the fields of untagged embedded structs are promoted.
+// An Option field is provided if its parameter is present, even if its value is
+// empty, so that ?name= decodes to Some(""), and is left untouched if it is
+// absent. Other fields are set only if their parameter is present.
+// Values are converted as they are by Scan: strings are parsed into numbers,
+// booleans and encoding.TextUnmarshaler implementations. Slices receive every
+// value of their parameter, and other types the first.
+// If a value cannot be converted, DecodeQuery returns an error naming the
+// parameter, and the fields decoded before it are left in place.
+func DecodeQuery(values url.Values, target any) (err error) {
+	return decodeStrings("DecodeQuery", target, "query", func(name string) (found []string, ok bool) {
+		found, ok = values[name]
+		return found, ok
+	})
+}
+
+// decodeStrings decodes the strings lookup finds for the fields of the struct
+// target points to, naming the fields by tag, for the function caller.
+func decodeStrings(caller string, target any, tag string, lookup func(name string) (values []string, ok bool)) (err error) {
+	return decodeFields(caller, target, tag, func(dst reflect.Value, name string) (err error) {
+		values, ok := lookup(name)
+		if !ok {
+			return nil
+		}
+
+		return assignStrings(dst, values)
+	})
+}
+
+// decodeFields calls decode with each exported field of the struct target
+// points to and its name, read from tag, for the function caller.
+func decodeFields(caller string, target any, tag string, decode func(dst reflect.Value, name string) (err error)) (err error) {
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Pointer || tv.IsNil() || tv.Elem().Kind() != reflect.Struct || isOptionType(tv.Elem().Type()) {
+		return fmt.Errorf("opt: %s requires a non-nil pointer to a struct target, got %T", caller, target)
+	}
+
+	return decodeStructFields(caller, tv.Elem(), tag, decode)
+}
+
+// decodeStructFields does the work for decodeFields on the struct v.
+func decodeStructFields(caller string, v reflect.Value, tag string, decode func(dst reflect.Value, name string) (err error)) (err error) {
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+
+		name, _, _ := strings.Cut(sf.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct && !isOptionType(sf.Type) {
+			if err = decodeStructFields(caller, v.Field(i), tag, decode); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		if err = decode(v.Field(i), name); err != nil {
+			return fmt.Errorf("opt: %s %s: %w", caller, name, err)
+		}
+	}
+
+	return nil
+}
+
+// assignStrings stores values in dst, leaving dst unchanged if they cannot be
+// converted.
+func assignStrings(dst reflect.Value, values []string) (err error) {
+	value := reflect.New(dst.Type()).Elem()
+
+	elem := value
+	if isOptionType(value.Type()) {
+		elem = value.Addr().Interface().(optionSetter).provide()
+	}
+
+	if elem.Kind() == reflect.Pointer {
+		elem.Set(reflect.New(elem.Type().Elem()))
+		elem = elem.Elem()
+	}
+
+	et := elem.Type()
+	if et.Kind() == reflect.Slice && et.Elem().Kind() != reflect.Uint8 && !reflect.PointerTo(et).Implements(textUnmarshalerType) {
+		elem.Set(reflect.MakeSlice(et, len(values), len(values)))
+		for i, s := range values {
+			if err = assignValue(elem.Index(i), reflect.ValueOf(s)); err != nil {
+				return err
+			}
+		}
+	} else {
+		var s string
+		if len(values) > 0 {
+			s = values[0]
+		}
+
+		if err = assignValue(elem, reflect.ValueOf(s)); err != nil {
+			return err
+		}
+	}
+
+	dst.Set(value)
+	return nil
+}
+
+// EncodeValues implements the query.Encoder interface so an Option can be
+// encoded as query parameters by github.com/google/go-querystring.
+// If the value is provided, EncodeValues adds its text form under key: the
+// text of an encoding.TextMarshaler, or the formatted string, number or
+// boolean, with every element of a slice added as a value of key.
+// If the value is not provided, EncodeValues adds nothing, so the parameter is
+// left out of the query.
+func (o Option[T]) EncodeValues(key string, v *url.Values) (err error) {
+	if !o.exists {
+		return nil
+	}
+
+	rv := reflect.ValueOf(o.value)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		text, err := formatValue(o.value)
+		if err != nil {
+			return err
+		}
+
+		v.Add(key, text)
+		return nil
+	}
+
+	for i := 0; i < rv.Len(); i++ {
+		text, err := formatValue(rv.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+
+		v.Add(key, text)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package opt_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/google/go-querystring/query"
+)
+
+type testQueryPage struct {
+	Page  opt.Option[int] `query:"page"`
+	Limit opt.Option[int] `query:"limit"`
+}
+
+type testQuery struct {
+	testQueryPage
+	Name    opt.Option[string]    `query:"name"`
+	Active  opt.Option[bool]      `query:"active"`
+	Since   opt.Option[time.Time] `query:"since"`
+	Tags    opt.Option[[]string]  `query:"tag"`
+	IDs     []int                 `query:"id"`
+	Sort    *string               `query:"sort"`
+	Format  string
+	Ignored opt.Option[string] `query:"-"`
+}
+
+func Test_DecodeQuery(t *testing.T) {
+	testCases := map[string]string{
+		"Empty":        "",
+		"Present":      "name=&active=true&page=0&tag=a&tag=b&id=1&id=2&sort=asc&Format=csv&Ignored=x",
+		"Empty values": "name=&tag=",
+		"Time":         "since=2024-01-02T03:04:05Z",
+		"Invalid":      "name=ada&page=one",
+	}
+
+	for n, query := range testCases {
+		t.Run(n, func(t *testing.T) {
+			values, err := url.ParseQuery(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var q testQuery
+			err = opt.DecodeQuery(values, &q)
+			snaps.MatchSnapshot(t, q, err)
+		})
+	}
+
+	t.Run("Not a pointer", func(t *testing.T) {
+		err := opt.DecodeQuery(url.Values{}, testQuery{})
+		snaps.MatchSnapshot(t, err)
+	})
+}
+
+type testQueryOptions struct {
+	Name   opt.Option[string]    `url:"name"`
+	Page   opt.Option[int]       `url:"page"`
+	Active opt.Option[bool]      `url:"active"`
+	Since  opt.Option[time.Time] `url:"since"`
+	Tags   opt.Option[[]string]  `url:"tag"`
+}
+
+func Test_Option_EncodeValues(t *testing.T) {
+	testCases := map[string]testQueryOptions{
+		"None": {},
+		"Zero values": {
+			Name:   opt.Some(""),
+			Page:   opt.Some(0),
+			Active: opt.Some(false),
+			Tags:   opt.Some([]string{}),
+		},
+		"Values": {
+			Name:   opt.Some("ada"),
+			Page:   opt.Some(2),
+			Active: opt.Some(true),
+			Since:  opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+			Tags:   opt.Some([]string{"a", "b"}),
+		},
+	}
+
+	for n, options := range testCases {
+		t.Run(n, func(t *testing.T) {
+			values, err := query.Values(options)
+			snaps.MatchSnapshot(t, values.Encode(), err)
+		})
+	}
+}
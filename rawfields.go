@@ -0,0 +1,51 @@
+package opt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// RawFields returns the JSON encoding of each top-level field of the struct v,
+// keyed by JSON name, so that partial payloads can be spliced into other
+// documents without decoding them.
+// RawFields includes the same fields Marshal does: fields holding an Option
+// that was not provided are left out, and so are empty fields tagged with
+// omitempty. v may also be a pointer to a struct.
+func RawFields(v any) (fields map[string]json.RawMessage, err error) {
+	rv := indirectValue(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("opt: RawFields requires a struct, got %T", v)
+	}
+
+	if err = checkType(rv.Type()); err != nil {
+		return nil, err
+	}
+
+	fields = map[string]json.RawMessage{}
+	for _, f := range cachedFields(rv.Type()) {
+		fv, err := rv.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+
+		if f.option {
+			if _, exists := fv.Interface().(option).AnyValue(); !exists {
+				continue
+			}
+		} else if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		var buf bytes.Buffer
+		e := encodeState{w: &buf, escapeHTML: true}
+		if err = e.encodeValue(fv); err != nil {
+			return nil, err
+		}
+
+		fields[f.name] = buf.Bytes()
+	}
+
+	return fields, nil
+}
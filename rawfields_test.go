@@ -0,0 +1,48 @@
+package opt_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testRawFields struct {
+	ID       int                          `json:"id"`
+	Note     string                       `json:"note,omitempty"`
+	Name     opt.Option[string]           `json:"name"`
+	Nickname opt.Option[string]           `json:"nickname"`
+	Address  opt.Option[testAliasAddress] `json:"address"`
+	Tags     opt.Option[[]string]         `json:"tags"`
+}
+
+func Test_RawFields(t *testing.T) {
+	t.Run("Present fields", func(t *testing.T) {
+		fields, err := opt.RawFields(&testRawFields{
+			ID:      1,
+			Name:    opt.Some("<Ada>"),
+			Address: opt.Some(testAliasAddress{}),
+			Tags:    opt.Some[[]string](nil),
+		})
+
+		result := map[string]string{}
+		for k, v := range fields {
+			result[k] = string(v)
+		}
+
+		snaps.MatchSnapshot(t, result, fmt.Sprint(err))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		fields, err := opt.RawFields([]int{1})
+		snaps.MatchSnapshot(t, fields == nil, fmt.Sprint(err))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		fields, err := opt.RawFields(struct {
+			C opt.Option[chan int] `json:"c"`
+		}{})
+		snaps.MatchSnapshot(t, fields == nil, fmt.Sprint(err))
+	})
+}
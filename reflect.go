@@ -0,0 +1,39 @@
+package opt
+
+import "reflect"
+
+// IsOptionType reports whether t is an Option type, that is an instantiation
+// of Option or a struct embedding one such as Tracked. The methods of t are
+// relied upon, so encoders can use IsOptionType rather than matching the name
+// of t.
+func IsOptionType(t reflect.Type) (ok bool) {
+	return t != nil && isOptionType(t)
+}
+
+// ElemType returns the type T of the values held by the Option type t.
+// If t is not an Option type, ElemType returns nil.
+func ElemType(t reflect.Type) (elem reflect.Type) {
+	if !IsOptionType(t) {
+		return nil
+	}
+
+	return optionElemType(t)
+}
+
+// ValueOf returns the value held by the Option v as a reflect.Value of type T,
+// even if T is an interface type, along with whether it was provided.
+// The value returned is a copy, so setting it does not change v.
+// If the value is not provided, or v does not hold an Option, ValueOf returns
+// the zero Value and false.
+func ValueOf(v reflect.Value) (value reflect.Value, exists bool) {
+	if !v.IsValid() || !IsOptionType(v.Type()) {
+		return reflect.Value{}, false
+	}
+
+	o := v.Interface().(option)
+	if _, exists = o.AnyValue(); !exists {
+		return reflect.Value{}, false
+	}
+
+	return o.reflectValue(), true
+}
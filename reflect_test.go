@@ -0,0 +1,54 @@
+package opt_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_IsOptionType(t *testing.T) {
+	for n, typ := range map[string]reflect.Type{
+		"Option":         reflect.TypeFor[opt.Option[string]](),
+		"Tracked":        reflect.TypeFor[opt.Tracked[int]](),
+		"Option pointer": reflect.TypeFor[*opt.Option[string]](),
+		"Struct":         reflect.TypeFor[testPayload](),
+		"String":         reflect.TypeFor[string](),
+		"Nil":            nil,
+	} {
+		t.Run(n, func(t *testing.T) {
+			snaps.MatchSnapshot(t, opt.IsOptionType(typ), fmt.Sprint(opt.ElemType(typ)))
+		})
+	}
+}
+
+func Test_ValueOf(t *testing.T) {
+	for n, v := range map[string]any{
+		"Some":      opt.Some("hello world"),
+		"None":      opt.None[string](),
+		"Interface": opt.Some[any](nil),
+		"Tracked":   opt.Tracked[int]{Option: opt.Some(1)},
+		"String":    "hello world",
+	} {
+		t.Run(n, func(t *testing.T) {
+			value, exists := opt.ValueOf(reflect.ValueOf(v))
+
+			var typ reflect.Type
+			if value.IsValid() {
+				typ = value.Type()
+			}
+
+			snaps.MatchSnapshot(t, fmt.Sprint(typ), exists)
+		})
+	}
+
+	t.Run("Copy", func(t *testing.T) {
+		o := opt.Some("hello world")
+
+		value, _ := opt.ValueOf(reflect.ValueOf(o))
+		value.SetString("changed")
+		snaps.MatchSnapshot(t, value.String(), o.Unwrap())
+	})
+}
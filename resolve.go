@@ -0,0 +1,70 @@
+package opt
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Source is a candidate for Resolve: an Option along with where it came from.
+type Source[T any] struct {
+	// Name identifies the source, such as "flag", "env" or "file".
+	Name string
+
+	// Priority orders the sources; the provided Option with the highest
+	// priority wins.
+	Priority int
+
+	// Option holds the value given by the source, if any.
+	Option Option[T]
+}
+
+// Resolved is the outcome of Resolve.
+type Resolved[T any] struct {
+	// value is the Option of the winning source.
+	value Option[T]
+
+	// source is the name of the winning source.
+	source string
+
+	// candidates holds every source considered, highest priority first.
+	candidates []Source[T]
+}
+
+// Resolve returns the value of the highest priority source whose Option is
+// provided, along with the name of that source and every candidate considered.
+// Sources of equal priority are considered in the order they are given.
+// If none of the sources provide a value, the resolved value is not provided.
+func Resolve[T any](sources ...Source[T]) (r Resolved[T]) {
+	r.candidates = slices.Clone(sources)
+	slices.SortStableFunc(r.candidates, func(a, b Source[T]) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+
+	for _, s := range r.candidates {
+		if s.Option.exists {
+			r.value = s.Option
+			r.source = s.Name
+			break
+		}
+	}
+
+	return r
+}
+
+// Value returns the resolved Option.
+// If no source provided a value, the Option is not provided.
+func (r Resolved[T]) Value() (o Option[T]) {
+	return r.value
+}
+
+// SourceName returns the name of the source the value was taken from.
+// If no source provided a value, SourceName returns "".
+func (r Resolved[T]) SourceName() (name string) {
+	return r.source
+}
+
+// Candidates returns every source that was considered, highest priority first,
+// including those that did not provide a value.
+func (r Resolved[T]) Candidates() (sources []Source[T]) {
+	return slices.Clone(r.candidates)
+}
@@ -0,0 +1,39 @@
+package opt_test
+
+import (
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+var resolveTestCases = map[string][]opt.Source[int]{
+	"Highest priority wins": {
+		{Name: "default", Priority: 0, Option: opt.Some(1)},
+		{Name: "flag", Priority: 20, Option: opt.Some(3)},
+		{Name: "env", Priority: 10, Option: opt.Some(2)},
+	},
+	"Skips absent": {
+		{Name: "default", Priority: 0, Option: opt.Some(1)},
+		{Name: "flag", Priority: 20, Option: opt.None[int]()},
+		{Name: "env", Priority: 10, Option: opt.Some(0)},
+	},
+	"Ties keep order": {
+		{Name: "first", Priority: 5, Option: opt.Some(1)},
+		{Name: "second", Priority: 5, Option: opt.Some(2)},
+	},
+	"None provided": {
+		{Name: "flag", Priority: 20},
+		{Name: "env", Priority: 10},
+	},
+	"No sources": nil,
+}
+
+func Test_Resolve(t *testing.T) {
+	for n, sources := range resolveTestCases {
+		t.Run(n, func(t *testing.T) {
+			r := opt.Resolve(sources...)
+			snaps.MatchSnapshot(t, r.Value(), r.SourceName(), r.Candidates())
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package opt
+
+import "errors"
+
+// ErrNotProvided is held by the Result returned by FromOption for an Option
+// whose value is not provided when no other error is given.
+var ErrNotProvided = errors.New("opt: value not provided")
+
+// Result holds either a value of type T or the error that prevented it from
+// being produced. Unlike an Option, which only records whether a value is
+// present, a Result keeps the error.
+// The zero Result holds the zero value of T and no error.
+type Result[T any] struct {
+	// value holds the value of type T.
+	value T
+
+	// err holds the error, or nil if the value was produced.
+	err error
+}
+
+// Ok returns a Result holding the provided value.
+func Ok[T any](value T) (r Result[T]) {
+	return Result[T]{
+		value: value,
+	}
+}
+
+// Err returns a Result holding the provided error.
+// If err is nil, the Result holds the zero value of T instead.
+func Err[T any](err error) (r Result[T]) {
+	return Result[T]{
+		err: err,
+	}
+}
+
+// ResultOf returns a Result holding value, or err if it is not nil, so that
+// the results of functions such as strconv.Atoi can be held in a Result:
+//
+//	port := opt.ResultOf(strconv.Atoi(s))
+func ResultOf[T any](value T, err error) (r Result[T]) {
+	if err != nil {
+		return Err[T](err)
+	}
+
+	return Ok(value)
+}
+
+// FromOption returns a Result holding the value of o, or err if the value of o
+// is not provided.
+// If the value is provided, FromOption returns Ok of the value.
+// If the value is not provided, FromOption returns Err of err, or of
+// ErrNotProvided if err is nil, so that the Result never holds a value that
+// was not provided.
+func FromOption[T any](o Option[T], err error) (r Result[T]) {
+	if !o.exists {
+		if err == nil {
+			err = ErrNotProvided
+		}
+
+		return Err[T](err)
+	}
+
+	return Ok(o.value)
+}
+
+// IsOk reports whether the Result holds a value.
+func (r Result[T]) IsOk() (ok bool) {
+	return r.err == nil
+}
+
+// IsErr reports whether the Result holds an error.
+func (r Result[T]) IsErr() (isErr bool) {
+	return r.err != nil
+}
+
+// Unwrap returns the value.
+// If the Result holds an error, Unwrap returns the zero value of the type.
+func (r Result[T]) Unwrap() (value T) {
+	if r.err != nil {
+		return value
+	}
+
+	return r.value
+}
+
+// UnwrapErr returns the error.
+// If the Result holds a value, UnwrapErr returns nil.
+func (r Result[T]) UnwrapErr() (err error) {
+	return r.err
+}
+
+// Get returns the value and the error, so that a Result can be returned from
+// a function returning (T, error).
+// If the Result holds an error, Get returns the zero value of the type.
+func (r Result[T]) Get() (value T, err error) {
+	return r.Unwrap(), r.err
+}
+
+// Option returns the Result as an Option, discarding the error.
+// If the Result holds a value, Option returns Some of the value.
+// If the Result holds an error, Option returns None.
+func (r Result[T]) Option() (o Option[T]) {
+	if r.err != nil {
+		return None[T]()
+	}
+
+	return Some(r.value)
+}
@@ -0,0 +1,35 @@
+package opt_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+var errTestNotFound = errors.New("not found")
+
+func Test_Result(t *testing.T) {
+	for n, r := range map[string]opt.Result[int]{
+		"Ok":                      opt.Ok(42),
+		"Ok zero":                 opt.Ok(0),
+		"Err":                     opt.Err[int](errTestNotFound),
+		"Err nil":                 opt.Err[int](nil),
+		"Zero":                    {},
+		"Of value":                opt.ResultOf(strconv.Atoi("42")),
+		"Of error":                opt.ResultOf(strconv.Atoi("forty-two")),
+		"From some":               opt.FromOption(opt.Some(42), errTestNotFound),
+		"From none":               opt.FromOption(opt.None[int](), errTestNotFound),
+		"From none without error": opt.FromOption(opt.None[int](), nil),
+	} {
+		t.Run(n, func(t *testing.T) {
+			value, err := r.Get()
+			o := r.Option()
+			snaps.MatchSnapshot(t, r.IsOk(), r.IsErr(), r.Unwrap(), fmt.Sprint(r.UnwrapErr()),
+				value, fmt.Sprint(err), errors.Is(err, opt.ErrNotProvided), o.Exists(), o.Unwrap())
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package opt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// redacted is written in place of the value of a Secret.
+const redacted = "<redacted>"
+
+// ErrRedacted is returned when unmarshaling the "<redacted>" placeholder
+// written by Secret.MarshalJSON into a Secret, which would otherwise replace
+// the value with the placeholder.
+var ErrRedacted = errors.New("opt: cannot unmarshal a redacted Secret")
+
+// Secret is an Option for sensitive values such as credentials, whose value
+// is never written by String, GoString, fmt, log/slog or JSON encoding, while
+// Unwrap still returns it.
+// Because the value is never written as JSON, a Secret does not survive a
+// JSON round trip: encoding it and decoding the result fails with
+// ErrRedacted rather than replacing the value with the placeholder. Send
+// the value itself, such as Unwrap returns, wherever it has to be read back.
+// Unlike a struct embedding an Option, Secret is not seen as an Option by
+// Marshal and the other reflection helpers, which would otherwise reveal the
+// value.
+// The zero Secret holds a value that is not provided.
+type Secret[T any] struct {
+	// o holds the value.
+	o Option[T]
+}
+
+// NewSecret returns a Secret holding the provided value.
+func NewSecret[T any](value T) (s Secret[T]) {
+	return Secret[T]{o: Some(value)}
+}
+
+// Redact returns a Secret holding the value of o, if it is provided.
+func Redact[T any](o Option[T]) (s Secret[T]) {
+	return Secret[T]{o: o}
+}
+
+// Exists reports whether the value was provided.
+func (s Secret[T]) Exists() (exists bool) {
+	return s.o.exists
+}
+
+// Unwrap returns the value.
+// If the value is not provided, Unwrap returns the zero value of the type.
+func (s Secret[T]) Unwrap() (value T) {
+	return s.o.Unwrap()
+}
+
+// UnwrapDefault returns the value, or returns the defaultValue if the value
+// is not provided.
+func (s Secret[T]) UnwrapDefault(defaultValue T) (value T) {
+	return s.o.UnwrapDefault(defaultValue)
+}
+
+// Option returns the value as an Option, which no longer redacts it.
+func (s Secret[T]) Option() (o Option[T]) {
+	return s.o
+}
+
+// String returns a string representation of the Secret.
+// If the value is provided, String returns "<redacted>".
+// If the value is not provided, String returns "<empty>".
+func (s Secret[T]) String() (str string) {
+	if !s.o.exists {
+		return "<empty>"
+	}
+
+	return redacted
+}
+
+// GoString returns the same as String, so that %#v does not print the value
+// either.
+func (s Secret[T]) GoString() (str string) {
+	return s.String()
+}
+
+// Format implements fmt.Formatter, writing the same as String for every verb
+// so that verbs such as %d or %x do not print the value either.
+func (s Secret[T]) Format(f fmt.State, verb rune) {
+	io.WriteString(f, s.String())
+}
+
+// LogValue implements slog.LogValuer.
+// If the value is provided, LogValue returns "<redacted>".
+// If the value is not provided, LogValue returns "<empty>".
+func (s Secret[T]) LogValue() (value slog.Value) {
+	return slog.StringValue(s.String())
+}
+
+// MarshalJSON marshals the Secret to JSON without revealing its value.
+// If the value is provided, MarshalJSON returns "\"<redacted>\"".
+// If the value is not provided, MarshalJSON returns "null".
+// The output cannot be unmarshaled back into a Secret.
+func (s Secret[T]) MarshalJSON() (data []byte, err error) {
+	if !s.o.exists {
+		return nullBytes, nil
+	}
+
+	return json.Marshal(redacted)
+}
+
+// UnmarshalJSON unmarshals the Secret from JSON as Option.UnmarshalJSON does.
+// Errors mention the type of the Secret but not the data, which may hold the
+// value.
+// UnmarshalJSON returns ErrRedacted for the "<redacted>" placeholder written
+// by MarshalJSON, leaving the Secret unchanged.
+func (s *Secret[T]) UnmarshalJSON(data []byte) (err error) {
+	var placeholder string
+	if json.Unmarshal(data, &placeholder) == nil && placeholder == redacted {
+		return ErrRedacted
+	}
+
+	var o Option[T]
+	if err = o.UnmarshalJSON(data); err != nil {
+		return fmt.Errorf("opt: cannot unmarshal JSON into %T", s)
+	}
+
+	s.o = o
+	return nil
+}
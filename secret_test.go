@@ -0,0 +1,72 @@
+package opt_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testSecretConfig struct {
+	User     string             `json:"user"`
+	Password opt.Secret[string] `json:"password"`
+	PIN      opt.Secret[int]    `json:"pin"`
+}
+
+func Test_Secret(t *testing.T) {
+	for n, s := range map[string]opt.Secret[string]{
+		"Provided": opt.NewSecret("hunter2"),
+		"Redact":   opt.Redact(opt.Some("hunter2")),
+		"Empty":    opt.Redact(opt.None[string]()),
+	} {
+		t.Run(n, func(t *testing.T) {
+			var logs bytes.Buffer
+			slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+
+					return a
+				},
+			})).Info("login", "password", s)
+
+			data, err := json.Marshal(s)
+			snaps.MatchSnapshot(t,
+				s.Exists(), s.Unwrap(), s.UnwrapDefault("default"), s.Option().Unwrap(),
+				s.String(), fmt.Sprintf("%v %+v %#v %s %q %x %d", s, s, s, s, s, s, s),
+				logs.String(), string(data), fmt.Sprint(err),
+			)
+		})
+	}
+}
+
+func Test_SecretJSON(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		var config testSecretConfig
+
+		err := json.Unmarshal([]byte(`{"user": "ada", "password": "hunter2", "pin": 1234}`), &config)
+		data, _ := opt.Marshal(config)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), config.Password.Unwrap(), config.PIN.Unwrap(), fmt.Sprintf("%+v", config), string(data))
+	})
+
+	t.Run("Redacted", func(t *testing.T) {
+		config := testSecretConfig{User: "ada", Password: opt.NewSecret("hunter2")}
+
+		data, _ := json.Marshal(config)
+		err := json.Unmarshal(data, &config)
+		snaps.MatchSnapshot(t, string(data), errors.Is(err, opt.ErrRedacted), config.Password.Unwrap())
+	})
+
+	t.Run("Type error", func(t *testing.T) {
+		var config testSecretConfig
+
+		err := json.Unmarshal([]byte(`{"pin": "1234"}`), &config)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), config.PIN.Exists())
+	})
+}
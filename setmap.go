@@ -0,0 +1,47 @@
+package opt
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ToSetMap returns the values of the Option fields of the struct v that are
+// provided, keyed by column name, ready to be passed to an SQL builder such as
+// squirrel's UpdateBuilder.SetMap.
+// The column name of a field is the name given by its db tag, or else its JSON
+// name. Fields tagged with db:"-" are left out, and so are fields that do not
+// hold an Option. v may also be a pointer to a struct; ToSetMap returns nil for
+// anything else.
+func ToSetMap(v any) (set map[string]any) {
+	rv := indirectValue(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	set = map[string]any{}
+	for _, f := range cachedFields(rv.Type()) {
+		if !f.option {
+			continue
+		}
+
+		name := f.name
+		if tag, ok := rv.Type().FieldByIndex(f.index).Tag.Lookup("db"); ok {
+			if tag, _, _ = strings.Cut(tag, ","); tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+		}
+
+		fv, err := rv.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+
+		if value, exists := fv.Interface().(option).AnyValue(); exists {
+			set[name] = value
+		}
+	}
+
+	return set
+}
@@ -0,0 +1,49 @@
+package opt_test
+
+import (
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testSetMapAudit struct {
+	UpdatedBy opt.Option[string] `db:"updated_by"`
+}
+
+type testSetMap struct {
+	testSetMapAudit
+	ID       int                `db:"id"`
+	Name     opt.Option[string] `db:"name" json:"full_name"`
+	Email    opt.Option[string] `json:"email"`
+	Age      opt.Option[int]    `db:"age,omitempty"`
+	Secret   opt.Option[string] `db:"-"`
+	Nickname opt.Option[string] `db:"nickname"`
+	Parent   opt.Option[*int]   `db:"parent_id"`
+}
+
+func Test_ToSetMap(t *testing.T) {
+	t.Run("Present fields", func(t *testing.T) {
+		snaps.MatchSnapshot(t, opt.ToSetMap(&testSetMap{
+			testSetMapAudit: testSetMapAudit{UpdatedBy: opt.Some("admin")},
+			ID:              1,
+			Name:            opt.Some("Ada"),
+			Email:           opt.Some(""),
+			Age:             opt.Some(0),
+			Secret:          opt.Some("hunter2"),
+			Parent:          opt.Some[*int](nil),
+		}))
+	})
+
+	t.Run("Embedded absent", func(t *testing.T) {
+		snaps.MatchSnapshot(t, opt.ToSetMap(testSetMap{Name: opt.Some("Ada")}))
+	})
+
+	t.Run("None present", func(t *testing.T) {
+		snaps.MatchSnapshot(t, opt.ToSetMap(testSetMap{ID: 1}))
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		snaps.MatchSnapshot(t, opt.ToSetMap("hello world") == nil)
+	})
+}
@@ -0,0 +1,79 @@
+package opt
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"github.com/fletcharoo/opt/internal/logwalk"
+)
+
+var (
+	logValuerType = reflect.TypeFor[slog.LogValuer]()
+	stringerType  = reflect.TypeFor[fmt.Stringer]()
+	errorType     = reflect.TypeFor[error]()
+)
+
+// logWalker groups the fields of structs unless they implement one of the
+// interfaces slog handlers format values with. Types implementing a JSON or
+// text marshaler describe themselves too, as isLeafType reports.
+var logWalker = logwalk.Walker{
+	IsOption:   isOptionType,
+	Interfaces: []reflect.Type{logValuerType, stringerType, errorType},
+}
+
+// LogValue implements slog.LogValuer so that an Option is logged as its value
+// rather than as a struct of its internals.
+// If the value is provided, LogValue returns it. A struct value is returned as
+// a group of its fields, named by their JSON names, leaving out Option fields
+// that are not provided, unless its type describes itself by implementing
+// slog.LogValuer, fmt.Stringer, error, or a JSON or text marshaler.
+// A struct reached again through a pointer cycle is logged as "<cycle>".
+// If the value is not provided, LogValue returns "<empty>".
+func (o Option[T]) LogValue() (value slog.Value) {
+	if !o.exists {
+		return slog.StringValue("<empty>")
+	}
+
+	return logValue(reflect.ValueOf(&o.value).Elem(), logwalk.Seen{})
+}
+
+// logValue returns the slog.Value of v, grouping the fields of structs. The
+// structs in seen are being grouped, so v is logged as a cycle if it is one of
+// them.
+func logValue(v reflect.Value, seen logwalk.Seen) (value slog.Value) {
+	rv, ok := logWalker.Struct(v)
+	if !ok || isLeafType(v.Type()) || isLeafType(rv.Type()) {
+		return slog.AnyValue(v.Interface())
+	}
+
+	if seen.Contains(rv) {
+		return slog.StringValue(logwalk.Cycle)
+	}
+
+	seen.Enter(rv)
+	defer seen.Leave(rv)
+
+	attrs := make([]slog.Attr, 0, rv.NumField())
+	for _, f := range cachedFields(rv.Type()) {
+		fv, err := rv.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+
+		if f.option {
+			// The value of a provided Option is logged in place, rather
+			// than through its LogValue, so that cycles through Options
+			// are seen too.
+			if fv, exists := ValueOf(fv); exists {
+				attrs = append(attrs, slog.Attr{Key: f.name, Value: logValue(fv, seen)})
+			}
+
+			continue
+		}
+
+		attrs = append(attrs, slog.Attr{Key: f.name, Value: logValue(fv, seen)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
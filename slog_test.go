@@ -0,0 +1,97 @@
+package opt_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testSlogAddress struct {
+	City     opt.Option[string] `json:"city"`
+	Postcode opt.Option[string] `json:"postcode"`
+}
+
+type testSlogUser struct {
+	Name    string                      `json:"name"`
+	Email   opt.Option[string]          `json:"email"`
+	Age     opt.Option[int]             `json:"age"`
+	Address testSlogAddress             `json:"address"`
+	Manager *testSlogUser               `json:"manager"`
+	Joined  opt.Option[time.Time]       `json:"joined"`
+	Billing opt.Option[testSlogAddress] `json:"billing"`
+}
+
+// logOutput returns the output of logging value under the key "value" with
+// both the text and the JSON handler of log/slog.
+func logOutput(value any) (text, json string) {
+	dropTime := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey && len(groups) == 0 {
+			return slog.Attr{}
+		}
+
+		return a
+	}
+
+	var t, j bytes.Buffer
+	slog.New(slog.NewTextHandler(&t, &slog.HandlerOptions{ReplaceAttr: dropTime})).Info("test", "value", value)
+	slog.New(slog.NewJSONHandler(&j, &slog.HandlerOptions{ReplaceAttr: dropTime})).Info("test", "value", value)
+
+	return t.String(), j.String()
+}
+
+func Test_LogValue(t *testing.T) {
+	for n, v := range map[string]any{
+		"Some":        opt.Some("hello world"),
+		"None":        opt.None[string](),
+		"Int":         opt.Some(42),
+		"Time":        opt.Some(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		"Nested none": opt.Some(opt.None[int]()),
+		"Pointer":     opt.Some[*int](nil),
+		"Struct": opt.Some(testSlogUser{
+			Name:    "Ada",
+			Email:   opt.Some("ada@example.com"),
+			Address: testSlogAddress{City: opt.Some("London")},
+			Manager: &testSlogUser{Name: "Charles"},
+			Billing: opt.Some(testSlogAddress{Postcode: opt.Some("W1")}),
+		}),
+		"Nested some": opt.Some(opt.Some("inner")),
+	} {
+		t.Run(n, func(t *testing.T) {
+			text, json := logOutput(v)
+			snaps.MatchSnapshot(t, text, json)
+		})
+	}
+}
+
+type testSlogNode struct {
+	Name   string                    `json:"name"`
+	Parent opt.Option[*testSlogNode] `json:"parent"`
+	Next   *testSlogNode             `json:"next"`
+}
+
+func Test_LogValue_Cycle(t *testing.T) {
+	leaf := &testSlogNode{Name: "leaf"}
+	shared := &testSlogNode{Name: "shared", Parent: opt.Some(leaf), Next: leaf}
+
+	root := &testSlogNode{Name: "root"}
+	child := &testSlogNode{Name: "child", Parent: opt.Some(root)}
+	root.Next = child
+
+	testCases := map[string]*testSlogNode{
+		"Shared": shared,
+		"Cycle":  root,
+		"Self":   {Name: "self"},
+	}
+	testCases["Self"].Next = testCases["Self"]
+
+	for n, node := range testCases {
+		t.Run(n, func(t *testing.T) {
+			text, json := logOutput(opt.Some(node))
+			snaps.MatchSnapshot(t, text, json)
+		})
+	}
+}
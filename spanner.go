@@ -0,0 +1,62 @@
+package opt
+
+import (
+	"encoding/base64"
+	"reflect"
+)
+
+// EncodeSpanner implements the spanner.Encoder interface so an Option can be
+// written to a nullable Cloud Spanner column.
+// If the value is provided, EncodeSpanner returns the value.
+// If the value is not provided, EncodeSpanner returns a nil *T, or a nil T if
+// T is a slice, which Spanner writes as a NULL of the column's type.
+func (o Option[T]) EncodeSpanner() (value any, err error) {
+	if o.exists {
+		return o.value, nil
+	}
+
+	if reflect.TypeFor[T]().Kind() == reflect.Slice {
+		return o.value, nil
+	}
+
+	return (*T)(nil), nil
+}
+
+// DecodeSpanner implements the spanner.Decoder interface so an Option can be
+// read from a nullable Cloud Spanner column.
+// If the column is NULL, the value is not set and DecodeSpanner returns nil.
+// Otherwise DecodeSpanner converts the column value to T and sets exists to
+// true. Spanner hands INT64, NUMERIC, TIMESTAMP and DATE columns over as
+// strings; they are parsed into numeric types and types implementing
+// encoding.TextUnmarshaler. BYTES columns are base64 decoded into []byte.
+func (o *Option[T]) DecodeSpanner(input any) (err error) {
+	*o = Option[T]{}
+
+	if input == nil {
+		return nil
+	}
+
+	if rv := reflect.ValueOf(input); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil
+	}
+
+	if s, ok := input.(string); ok {
+		if _, isBytes := any(o.value).([]byte); isBytes {
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return err
+			}
+
+			input = b
+		}
+	}
+
+	value, err := convertValue[T](input)
+	if err != nil {
+		return err
+	}
+
+	o.value = value
+	o.exists = true
+	return nil
+}
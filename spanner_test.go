@@ -0,0 +1,65 @@
+package opt_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// spannerDecoder is the interface Cloud Spanner uses to decode columns into
+// custom types.
+type spannerDecoder interface {
+	DecodeSpanner(input any) error
+}
+
+// spannerEncoder is the interface Cloud Spanner uses to encode custom types
+// into columns.
+type spannerEncoder interface {
+	EncodeSpanner() (any, error)
+}
+
+type spannerTestCase struct {
+	option func() spannerDecoder
+	input  any
+}
+
+var spannerTestCases = map[string]spannerTestCase{
+	"String":           {option: newSpannerOption[string], input: "hello world"},
+	"String null":      {option: newSpannerOption[string], input: (*string)(nil)},
+	"Int64":            {option: newSpannerOption[int64], input: "42"},
+	"Int64 invalid":    {option: newSpannerOption[int64], input: "forty two"},
+	"Int64 null":       {option: newSpannerOption[int64], input: (*string)(nil)},
+	"Float64":          {option: newSpannerOption[float64], input: 1.5},
+	"Float64 infinity": {option: newSpannerOption[float64], input: "Infinity"},
+	"Bool":             {option: newSpannerOption[bool], input: true},
+	"Bool null":        {option: newSpannerOption[bool], input: (*bool)(nil)},
+	"Bytes":            {option: newSpannerOption[[]byte], input: "aGVsbG8gd29ybGQ="},
+	"Bytes null":       {option: newSpannerOption[[]byte], input: (*string)(nil)},
+	"Timestamp":        {option: newSpannerOption[time.Time], input: "2024-01-02T03:04:05.123Z"},
+	"Untyped null":     {option: newSpannerOption[string], input: nil},
+}
+
+func newSpannerOption[T any]() spannerDecoder {
+	return new(opt.Option[T])
+}
+
+func Test_Spanner(t *testing.T) {
+	for n, c := range spannerTestCases {
+		t.Run(n, func(t *testing.T) {
+			o := c.option()
+
+			t.Run("DecodeSpanner", func(t *testing.T) {
+				err := o.DecodeSpanner(c.input)
+				snaps.MatchSnapshot(t, fmt.Sprint(err), fmt.Sprint(o))
+			})
+
+			t.Run("EncodeSpanner", func(t *testing.T) {
+				value, err := o.(spannerEncoder).EncodeSpanner()
+				snaps.MatchSnapshot(t, fmt.Sprint(err), fmt.Sprintf("%T %v", value, value))
+			})
+		})
+	}
+}
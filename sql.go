@@ -0,0 +1,154 @@
+package opt
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+)
+
+// Value implements the driver.Valuer interface so an Option can be used as a
+// query argument.
+// If the value is provided, Value converts it with
+// driver.DefaultParameterConverter, so values implementing driver.Valuer and
+// the named types of basic kinds are handled as they are by database/sql.
+// If the value is not provided, Value returns nil, which is written as NULL.
+func (o Option[T]) Value() (value driver.Value, err error) {
+	if !o.exists {
+		return nil, nil
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(o.value)
+}
+
+// Scan implements the sql.Scanner interface so an Option can be used as a
+// query destination.
+// If the column is NULL, the value is not set and Scan returns nil.
+// Otherwise Scan stores the column in T and sets exists to true. If *T
+// implements sql.Scanner it is used, otherwise the column is converted as it
+// is by the Spanner decoder: strings and byte slices are parsed into numbers,
+// booleans and encoding.TextUnmarshaler implementations, and numbers are
+// converted between numeric kinds.
+func (o *Option[T]) Scan(src any) (err error) {
+	*o = Option[T]{}
+
+	if src == nil {
+		return nil
+	}
+
+	// Drivers may reuse the memory of a []byte once Scan returns.
+	if b, ok := src.([]byte); ok {
+		src = bytes.Clone(b)
+	}
+
+	var value T
+	if s, ok := any(&value).(sql.Scanner); ok {
+		err = s.Scan(src)
+	} else {
+		value, err = convertValue[T](src)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	o.value = value
+	o.exists = true
+	return nil
+}
+
+// FromSQLNull returns an Option holding the value of n if n is valid, and an
+// Option whose value is not provided otherwise.
+func FromSQLNull[T any](n sql.Null[T]) (o Option[T]) {
+	if !n.Valid {
+		return Option[T]{}
+	}
+
+	return Some(n.V)
+}
+
+// ToSQLNull returns a valid sql.Null holding the value if it is provided, and
+// an invalid sql.Null otherwise.
+func ToSQLNull[T any](o Option[T]) (n sql.Null[T]) {
+	return sql.Null[T]{V: o.value, Valid: o.exists}
+}
+
+// FromNullString returns an Option holding the value of n if n is valid, and
+// an Option whose value is not provided otherwise.
+func FromNullString(n sql.NullString) (o Option[string]) {
+	if !n.Valid {
+		return Option[string]{}
+	}
+
+	return Some(n.String)
+}
+
+// ToNullString returns a valid sql.NullString holding the value if it is
+// provided, and an invalid sql.NullString otherwise.
+func ToNullString(o Option[string]) (n sql.NullString) {
+	return sql.NullString{String: o.value, Valid: o.exists}
+}
+
+// FromNullInt64 returns an Option holding the value of n if n is valid, and
+// an Option whose value is not provided otherwise.
+func FromNullInt64(n sql.NullInt64) (o Option[int64]) {
+	if !n.Valid {
+		return Option[int64]{}
+	}
+
+	return Some(n.Int64)
+}
+
+// ToNullInt64 returns a valid sql.NullInt64 holding the value if it is
+// provided, and an invalid sql.NullInt64 otherwise.
+func ToNullInt64(o Option[int64]) (n sql.NullInt64) {
+	return sql.NullInt64{Int64: o.value, Valid: o.exists}
+}
+
+// FromNullBool returns an Option holding the value of n if n is valid, and
+// an Option whose value is not provided otherwise.
+func FromNullBool(n sql.NullBool) (o Option[bool]) {
+	if !n.Valid {
+		return Option[bool]{}
+	}
+
+	return Some(n.Bool)
+}
+
+// ToNullBool returns a valid sql.NullBool holding the value if it is
+// provided, and an invalid sql.NullBool otherwise.
+func ToNullBool(o Option[bool]) (n sql.NullBool) {
+	return sql.NullBool{Bool: o.value, Valid: o.exists}
+}
+
+// FromNullFloat64 returns an Option holding the value of n if n is valid, and
+// an Option whose value is not provided otherwise.
+func FromNullFloat64(n sql.NullFloat64) (o Option[float64]) {
+	if !n.Valid {
+		return Option[float64]{}
+	}
+
+	return Some(n.Float64)
+}
+
+// ToNullFloat64 returns a valid sql.NullFloat64 holding the value if it is
+// provided, and an invalid sql.NullFloat64 otherwise.
+func ToNullFloat64(o Option[float64]) (n sql.NullFloat64) {
+	return sql.NullFloat64{Float64: o.value, Valid: o.exists}
+}
+
+// FromNullTime returns an Option holding the value of n if n is valid, and
+// an Option whose value is not provided otherwise.
+func FromNullTime(n sql.NullTime) (o Option[time.Time]) {
+	if !n.Valid {
+		return Option[time.Time]{}
+	}
+
+	return Some(n.Time)
+}
+
+// ToNullTime returns a valid sql.NullTime holding the value if it is
+// provided, and an invalid sql.NullTime otherwise.
+func ToNullTime(o Option[time.Time]) (n sql.NullTime) {
+	return sql.NullTime{Time: o.value, Valid: o.exists}
+}
@@ -0,0 +1,141 @@
+package opt_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type sqlOption interface {
+	sql.Scanner
+	driver.Valuer
+}
+
+type sqlTestCase struct {
+	option func() sqlOption
+	src    any
+}
+
+type testStatus string
+
+var sqlTestCases = map[string]sqlTestCase{
+	"String":           {option: newSQLOption[string], src: "hello world"},
+	"String bytes":     {option: newSQLOption[string], src: []byte("hello world")},
+	"String null":      {option: newSQLOption[string], src: nil},
+	"Int64":            {option: newSQLOption[int64], src: int64(42)},
+	"Int32":            {option: newSQLOption[int32], src: int64(42)},
+	"Int from bytes":   {option: newSQLOption[int], src: []byte("42")},
+	"Int invalid":      {option: newSQLOption[int], src: "forty two"},
+	"Int8 overflow":    {option: newSQLOption[int8], src: int64(300)},
+	"Uint negative":    {option: newSQLOption[uint], src: int64(-1)},
+	"Uint8 from uint":  {option: newSQLOption[uint8], src: uint64(255)},
+	"Int from float":   {option: newSQLOption[int], src: 3.0},
+	"Int fraction":     {option: newSQLOption[int], src: 3.9},
+	"Int64 huge float": {option: newSQLOption[int64], src: 1e19},
+	"Float32 overflow": {option: newSQLOption[float32], src: 1e39},
+	"Float64":          {option: newSQLOption[float64], src: 1.5},
+	"Bool":             {option: newSQLOption[bool], src: true},
+	"Bool from int":    {option: newSQLOption[bool], src: int64(1)},
+	"Bool invalid":     {option: newSQLOption[bool], src: int64(2)},
+	"Bytes":            {option: newSQLOption[[]byte], src: []byte("hello world")},
+	"Time":             {option: newSQLOption[time.Time], src: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	"Named string":     {option: newSQLOption[testStatus], src: "active"},
+	"Scanner":          {option: newSQLOption[sql.NullInt64], src: int64(7)},
+	"Scanner null":     {option: newSQLOption[sql.NullInt64], src: nil},
+}
+
+func newSQLOption[T any]() sqlOption {
+	return new(opt.Option[T])
+}
+
+func Test_SQL(t *testing.T) {
+	for n, c := range sqlTestCases {
+		t.Run(n, func(t *testing.T) {
+			o := c.option()
+
+			t.Run("Scan", func(t *testing.T) {
+				err := o.Scan(c.src)
+				snaps.MatchSnapshot(t, fmt.Sprint(err), fmt.Sprint(o))
+			})
+
+			t.Run("Value", func(t *testing.T) {
+				value, err := o.Value()
+				snaps.MatchSnapshot(t, fmt.Sprint(err), fmt.Sprintf("%T %v", value, value))
+			})
+		})
+	}
+
+	t.Run("Bytes are copied", func(t *testing.T) {
+		src := []byte("hello")
+
+		var o opt.Option[[]byte]
+		err := o.Scan(src)
+		src[0] = 'j'
+
+		snaps.MatchSnapshot(t, fmt.Sprint(err), string(o.Unwrap()))
+	})
+}
+
+func Test_SQLNull(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("From", func(t *testing.T) {
+		snaps.MatchSnapshot(t,
+			opt.FromNullString(sql.NullString{String: "hello world", Valid: true}),
+			opt.FromNullString(sql.NullString{String: "ignored"}),
+			opt.FromNullInt64(sql.NullInt64{Int64: 42, Valid: true}),
+			opt.FromNullInt64(sql.NullInt64{}),
+			opt.FromNullBool(sql.NullBool{Bool: false, Valid: true}),
+			opt.FromNullBool(sql.NullBool{}),
+			opt.FromNullFloat64(sql.NullFloat64{Float64: 1.5, Valid: true}),
+			opt.FromNullFloat64(sql.NullFloat64{}),
+			opt.FromNullTime(sql.NullTime{Time: created, Valid: true}),
+			opt.FromNullTime(sql.NullTime{}),
+		)
+	})
+
+	t.Run("To", func(t *testing.T) {
+		snaps.MatchSnapshot(t,
+			opt.ToNullString(opt.Some("")),
+			opt.ToNullString(opt.None[string]()),
+			opt.ToNullInt64(opt.Some(int64(42))),
+			opt.ToNullInt64(opt.None[int64]()),
+			opt.ToNullBool(opt.Some(true)),
+			opt.ToNullBool(opt.None[bool]()),
+			opt.ToNullFloat64(opt.Some(1.5)),
+			opt.ToNullFloat64(opt.None[float64]()),
+			opt.ToNullTime(opt.Some(created)),
+			opt.ToNullTime(opt.None[time.Time]()),
+		)
+	})
+}
+
+func Test_SQLNullGeneric(t *testing.T) {
+	t.Run("From", func(t *testing.T) {
+		snaps.MatchSnapshot(t,
+			opt.FromSQLNull(sql.Null[string]{V: "hello world", Valid: true}),
+			opt.FromSQLNull(sql.Null[string]{V: "ignored"}),
+			opt.FromSQLNull(sql.Null[testStatus]{V: "active", Valid: true}),
+		)
+	})
+
+	t.Run("To", func(t *testing.T) {
+		snaps.MatchSnapshot(t,
+			opt.ToSQLNull(opt.Some(0)),
+			opt.ToSQLNull(opt.None[int]()),
+			opt.ToSQLNull(opt.Some[*int](nil)),
+		)
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		snaps.MatchSnapshot(t,
+			opt.FromSQLNull(opt.ToSQLNull(opt.Some(""))),
+			opt.FromSQLNull(opt.ToSQLNull(opt.None[string]())),
+		)
+	})
+}
@@ -0,0 +1,90 @@
+package opt
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// randomizer is the sqlboiler randomize.Randomizer interface.
+type randomizer interface {
+	Randomize(nextInt func() int64, fieldType string, shouldBeNull bool)
+}
+
+// Randomize implements the sqlboiler randomize.Randomizer interface so Option
+// can replace the volatiletech/null types in generated models, whose tests
+// fill models with random values.
+// If shouldBeNull is true, the value is not set. Otherwise Randomize sets
+// exists to true and derives the value from nextInt: types implementing the
+// interface themselves are asked to randomize, uuid columns get a UUID string,
+// and numbers, strings, booleans, byte slices and times are generated
+// according to their kind. Values of other types are left as their zero value.
+func (o *Option[T]) Randomize(nextInt func() int64, fieldType string, shouldBeNull bool) {
+	*o = Option[T]{}
+
+	if shouldBeNull {
+		return
+	}
+
+	o.exists = true
+
+	if r, ok := any(&o.value).(randomizer); ok {
+		r.Randomize(nextInt, fieldType, false)
+		return
+	}
+
+	if t, ok := any(&o.value).(*time.Time); ok {
+		// Whole seconds in UTC survive a round trip through any database.
+		*t = time.Unix(1e9+nextInt()%1e9, 0).UTC()
+		return
+	}
+
+	randomizeValue(reflect.ValueOf(&o.value).Elem(), nextInt, fieldType)
+}
+
+// randomizeValue sets v, which is not a time.Time, to a value derived from
+// nextInt according to its kind.
+func randomizeValue(v reflect.Value, nextInt func() int64, fieldType string) {
+	n := nextInt()
+
+	switch v.Kind() {
+	case reflect.String:
+		if strings.Contains(strings.ToLower(fieldType), "uuid") {
+			v.SetString(randomUUID(n))
+			return
+		}
+
+		v.SetString(strconv.FormatInt(n, 36))
+	case reflect.Bool:
+		v.SetBool(n%2 == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if bits := v.Type().Bits(); bits < 64 {
+			n %= 1 << (bits - 1)
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := uint64(n) & math.MaxInt64
+		if bits := v.Type().Bits(); bits < 64 {
+			u %= 1 << bits
+		}
+
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		// Two decimal places fit any numeric column sqlboiler maps to floats.
+		v.SetFloat(float64(n%1e6) / 100)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(strconv.FormatInt(n, 36)))
+		}
+	}
+}
+
+// randomUUID formats n as a version 4 UUID.
+func randomUUID(n int64) (uuid string) {
+	u := uint64(n)
+	return fmt.Sprintf("%08x-%04x-4%03x-8%03x-%012x", u>>32, u>>16&0xffff, u&0xfff, u>>52&0xfff, u&0xffffffffffff)
+}
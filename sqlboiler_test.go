@@ -0,0 +1,77 @@
+package opt_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+// testRandomized randomizes itself.
+type testRandomized struct {
+	FieldType string
+}
+
+func (r *testRandomized) Randomize(nextInt func() int64, fieldType string, shouldBeNull bool) {
+	r.FieldType = fieldType
+}
+
+// sequence returns a nextInt func counting up from start.
+func sequence(start int64) func() int64 {
+	return func() int64 {
+		start++
+		return start
+	}
+}
+
+func Test_Randomize(t *testing.T) {
+	t.Run("Null", func(t *testing.T) {
+		o := opt.Some("stale")
+		o.Randomize(sequence(0), "text", true)
+		snaps.MatchSnapshot(t, o)
+	})
+
+	t.Run("Kinds", func(t *testing.T) {
+		var (
+			str        opt.Option[string]
+			uuid       opt.Option[string]
+			i8         opt.Option[int8]
+			i64        opt.Option[int64]
+			u16        opt.Option[uint16]
+			f          opt.Option[float64]
+			b          opt.Option[bool]
+			bytes      opt.Option[[]byte]
+			created    opt.Option[time.Time]
+			randomized opt.Option[testRandomized]
+			unknown    opt.Option[struct{ A int }]
+		)
+
+		next := sequence(1000)
+		str.Randomize(next, "text", false)
+		uuid.Randomize(next, "uuid", false)
+		i8.Randomize(next, "smallint", false)
+		i64.Randomize(next, "bigint", false)
+		u16.Randomize(next, "integer", false)
+		f.Randomize(next, "double precision", false)
+		b.Randomize(next, "boolean", false)
+		bytes.Randomize(next, "bytea", false)
+		created.Randomize(next, "timestamp", false)
+		randomized.Randomize(next, "jsonb", false)
+		unknown.Randomize(next, "jsonb", false)
+
+		snaps.MatchSnapshot(t, str, uuid, i8, i64, u16, f, b, bytes, created.Unwrap().String(), randomized, unknown)
+	})
+
+	t.Run("Large UUID", func(t *testing.T) {
+		var uuid opt.Option[string]
+		uuid.Randomize(sequence(math.MaxInt64-1), "uuid", false)
+
+		if n := len(uuid.Unwrap()); n != 36 {
+			t.Errorf("got UUID %s of length %d, want 36", uuid.Unwrap(), n)
+		}
+
+		snaps.MatchSnapshot(t, uuid)
+	})
+}
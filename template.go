@@ -0,0 +1,71 @@
+package opt
+
+import "text/template"
+
+// IsSet reports whether the value was provided. It is Exists under a name that
+// reads naturally in templates:
+//
+//	{{if .Nickname.IsSet}}Hi {{.Nickname.Unwrap}}{{end}}
+func (o Option[T]) IsSet() (set bool) {
+	return o.exists
+}
+
+// ValueOr returns the value if it is provided, and def otherwise. Unlike
+// UnwrapDefault, it takes and returns an any, so that templates can pass a
+// default of any type:
+//
+//	{{.Nickname.ValueOr "anonymous"}}
+func (o Option[T]) ValueOr(def any) (value any) {
+	if !o.exists {
+		return def
+	}
+
+	return o.value
+}
+
+// FuncMap returns template functions for consuming Options in text/template
+// and html/template, whose FuncMap is the same type:
+//
+//	tmpl := template.New("page").Funcs(opt.FuncMap())
+//
+// The functions are:
+//
+//	optSet v
+//		Reports whether v is an Option whose value is provided, or a
+//		non-nil value that is not an Option.
+//	optOr def v
+//		Returns the value of the Option v if it is provided, and def
+//		otherwise. A non-nil value that is not an Option is returned as is.
+//		It reads naturally in pipelines: {{.Nickname | optOr "anonymous"}}
+func FuncMap() (funcs template.FuncMap) {
+	return template.FuncMap{
+		"optSet": templateSet,
+		"optOr":  templateOr,
+	}
+}
+
+// templateSet implements the optSet template function.
+func templateSet(v any) (set bool) {
+	_, set = templateValue(v)
+	return set
+}
+
+// templateOr implements the optOr template function.
+func templateOr(def, v any) (value any) {
+	value, set := templateValue(v)
+	if !set {
+		return def
+	}
+
+	return value
+}
+
+// templateValue returns the value of v and whether it is set, unwrapping v if
+// it is an Option.
+func templateValue(v any) (value any, set bool) {
+	if w, ok := v.(Wrapper); ok {
+		return w.AnyValue()
+	}
+
+	return v, v != nil
+}
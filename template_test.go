@@ -0,0 +1,46 @@
+package opt_test
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testTemplateData struct {
+	Name     opt.Option[string]
+	Nickname opt.Option[string]
+	Age      opt.Option[int]
+	Plain    string
+	Nil      any
+}
+
+var testTemplateValue = testTemplateData{
+	Name:  opt.Some("<Ada>"),
+	Age:   opt.Some(0),
+	Plain: "plain",
+}
+
+const testTemplateText = `{{if .Name.IsSet}}name={{.Name.Unwrap}}{{end}}
+nickname={{.Nickname.ValueOr "anonymous"}} age={{.Age.ValueOr -1}}
+set={{optSet .Name}},{{optSet .Nickname}},{{optSet .Plain}},{{optSet .Nil}}
+or={{.Nickname | optOr "anonymous"}},{{.Age | optOr 18}},{{.Plain | optOr "default"}},{{.Nil | optOr "default"}}`
+
+func Test_Template(t *testing.T) {
+	t.Run("Text", func(t *testing.T) {
+		var b strings.Builder
+		tmpl := template.Must(template.New("text").Funcs(opt.FuncMap()).Parse(testTemplateText))
+		err := tmpl.Execute(&b, testTemplateValue)
+		snaps.MatchSnapshot(t, b.String(), err)
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		var b strings.Builder
+		tmpl := htmltemplate.Must(htmltemplate.New("html").Funcs(opt.FuncMap()).Parse(testTemplateText))
+		err := tmpl.Execute(&b, testTemplateValue)
+		snaps.MatchSnapshot(t, b.String(), err)
+	})
+}
@@ -0,0 +1,64 @@
+package opt
+
+import "reflect"
+
+// Tracked is an Option that also records whether it was changed by Set after
+// it was decoded, so that a value that came from a request can be told apart
+// from one filled in later, such as by a default.
+// Tracked embeds Option, so it is encoded, decoded and inspected as one.
+// Decoding into a Tracked does not mark it as dirty.
+type Tracked[T any] struct {
+	Option[T]
+
+	// dirty indicates whether Set was called since the last reset.
+	dirty bool
+}
+
+// Set sets the value and marks it as provided and dirty.
+func (t *Tracked[T]) Set(value T) {
+	t.Option = Some(value)
+	t.dirty = true
+}
+
+// Dirty reports whether Set was called since the Tracked was decoded or last
+// reset.
+func (t Tracked[T]) Dirty() (dirty bool) {
+	return t.dirty
+}
+
+// ResetDirty clears the dirty flag, leaving the value unchanged.
+func (t *Tracked[T]) ResetDirty() {
+	t.dirty = false
+}
+
+// dirtyResetter is implemented by every *Tracked[T].
+type dirtyResetter interface {
+	ResetDirty()
+}
+
+// ResetDirty clears the dirty flags of every Tracked field of the struct v
+// points to, including those of nested structs.
+func ResetDirty(v any) {
+	resetDirty(reflect.ValueOf(v))
+}
+
+// resetDirty does the work for ResetDirty.
+func resetDirty(v reflect.Value) {
+	v = indirectValue(v)
+	if !v.IsValid() || v.Kind() != reflect.Struct || !v.CanAddr() {
+		return
+	}
+
+	for _, f := range cachedFields(v.Type()) {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue
+		}
+
+		if r, ok := fv.Addr().Interface().(dirtyResetter); ok {
+			r.ResetDirty()
+		} else if !f.option {
+			resetDirty(fv)
+		}
+	}
+}
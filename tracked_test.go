@@ -0,0 +1,50 @@
+package opt_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testTrackedAddress struct {
+	City opt.Tracked[string] `json:"city"`
+}
+
+type testTracked struct {
+	Name    opt.Tracked[string] `json:"name"`
+	Role    opt.Tracked[string] `json:"role"`
+	Age     opt.Tracked[int]    `json:"age"`
+	Address *testTrackedAddress `json:"address"`
+}
+
+func Test_Tracked(t *testing.T) {
+	var v testTracked
+	if err := json.Unmarshal([]byte(`{"name": "Ada", "address": {"city": "London"}}`), &v); err != nil {
+		t.Fatalf("Unexpected unmarshal error: %s", err)
+	}
+
+	t.Run("Decoded", func(t *testing.T) {
+		snaps.MatchSnapshot(t, v.Name.Exists(), v.Name.Dirty(), v.Role.Exists(), v.Role.Dirty())
+	})
+
+	v.Role.Set("member")
+	v.Address.City.Set("Paris")
+
+	t.Run("Set", func(t *testing.T) {
+		data, _ := opt.Marshal(v)
+		snaps.MatchSnapshot(t, v.Role.Unwrap(), v.Role.Dirty(), v.Address.City.Dirty(), v.Name.Dirty(), string(data))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		opt.ResetDirty(&v)
+		snaps.MatchSnapshot(t, v.Role.Exists(), v.Role.Dirty(), v.Address.City.Dirty())
+	})
+
+	t.Run("Reset one", func(t *testing.T) {
+		v.Age.Set(36)
+		v.Age.ResetDirty()
+		snaps.MatchSnapshot(t, v.Age.Unwrap(), v.Age.Dirty())
+	})
+}
@@ -0,0 +1,146 @@
+package opt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Tristate is a boolean that may also be absent, for settings such as feature
+// flags where "not configured" is distinct from explicitly false.
+// The zero Tristate is Absent, so struct fields tagged with omitempty are left
+// out of JSON when they are absent.
+type Tristate uint8
+
+const (
+	// Absent is a Tristate that was not configured.
+	Absent Tristate = iota
+
+	// False is a Tristate that was explicitly set to false.
+	False
+
+	// True is a Tristate that was explicitly set to true.
+	True
+)
+
+// TristateOf returns the Tristate matching o.
+// If the value is provided, TristateOf returns True or False.
+// If the value is not provided, TristateOf returns Absent.
+func TristateOf(o Option[bool]) (t Tristate) {
+	switch {
+	case !o.exists:
+		return Absent
+	case o.value:
+		return True
+	}
+
+	return False
+}
+
+// Option returns the Tristate as an Option, which is not provided if the
+// Tristate is Absent.
+func (t Tristate) Option() (o Option[bool]) {
+	if t == Absent {
+		return Option[bool]{}
+	}
+
+	return Some(t == True)
+}
+
+// IsSet reports whether the Tristate is True or False.
+func (t Tristate) IsSet() (ok bool) {
+	return t == True || t == False
+}
+
+// EnabledOr reports whether the Tristate is True.
+// If the Tristate is Absent, EnabledOr returns def.
+func (t Tristate) EnabledOr(def bool) (enabled bool) {
+	if !t.IsSet() {
+		return def
+	}
+
+	return t == True
+}
+
+// String returns "true", "false" or "absent".
+func (t Tristate) String() (str string) {
+	switch t {
+	case True:
+		return "true"
+	case False:
+		return "false"
+	case Absent:
+		return "absent"
+	}
+
+	return "Tristate(" + strconv.Itoa(int(t)) + ")"
+}
+
+// MarshalJSON marshals the Tristate to JSON.
+// If the Tristate is True or False, MarshalJSON returns the boolean.
+// If the Tristate is Absent, MarshalJSON returns "null".
+func (t Tristate) MarshalJSON() (data []byte, err error) {
+	if !t.IsSet() {
+		return nullBytes, nil
+	}
+
+	return json.Marshal(t == True)
+}
+
+// UnmarshalJSON unmarshals the Tristate from a JSON boolean or null, which
+// sets it to Absent.
+func (t *Tristate) UnmarshalJSON(data []byte) (err error) {
+	if bytes.Equal(data, nullBytes) {
+		*t = Absent
+		return nil
+	}
+
+	var b bool
+	if err = json.Unmarshal(data, &b); err != nil {
+		return fmt.Errorf("opt: cannot unmarshal %s into Tristate", data)
+	}
+
+	*t = TristateOf(Some(b))
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// If the Tristate is Absent, MarshalText returns empty text.
+func (t Tristate) MarshalText() (text []byte, err error) {
+	if !t.IsSet() {
+		return []byte{}, nil
+	}
+
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Empty text sets the Tristate to Absent; anything else is parsed with
+// strconv.ParseBool.
+func (t *Tristate) UnmarshalText(text []byte) (err error) {
+	if len(text) == 0 {
+		*t = Absent
+		return nil
+	}
+
+	b, err := strconv.ParseBool(string(text))
+	if err != nil {
+		return fmt.Errorf("opt: cannot parse %q as Tristate", text)
+	}
+
+	*t = TristateOf(Some(b))
+	return nil
+}
+
+// Set implements the flag.Value interface, parsing s as UnmarshalText does.
+func (t *Tristate) Set(s string) (err error) {
+	return t.UnmarshalText([]byte(s))
+}
+
+// IsBoolFlag lets a Tristate be given as a bare command-line flag, as in
+// -feature, which sets it to True. A flag that is not given leaves the
+// Tristate Absent.
+func (t *Tristate) IsBoolFlag() (ok bool) {
+	return true
+}
@@ -0,0 +1,93 @@
+package opt_test
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testFlags struct {
+	Beta    opt.Tristate `json:"beta"`
+	Dark    opt.Tristate `json:"dark,omitempty"`
+	Preview opt.Tristate `json:"preview,omitempty"`
+}
+
+func Test_Tristate(t *testing.T) {
+	t.Run("EnabledOr", func(t *testing.T) {
+		snaps.MatchSnapshot(t,
+			opt.Absent.EnabledOr(true),
+			opt.Absent.EnabledOr(false),
+			opt.False.EnabledOr(true),
+			opt.True.EnabledOr(false),
+		)
+	})
+
+	t.Run("Option", func(t *testing.T) {
+		snaps.MatchSnapshot(t,
+			opt.TristateOf(opt.None[bool]()),
+			opt.TristateOf(opt.Some(false)),
+			opt.TristateOf(opt.Some(true)),
+			opt.Absent.Option(),
+			opt.False.Option(),
+			opt.True.Option(),
+		)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		snaps.MatchSnapshot(t, opt.Absent.String(), opt.False.String(), opt.True.String(), opt.Tristate(7).String())
+	})
+
+	t.Run("Marshal JSON", func(t *testing.T) {
+		data, err := json.Marshal(testFlags{Dark: opt.False})
+		snaps.MatchSnapshot(t, string(data), fmt.Sprint(err))
+	})
+
+	t.Run("Unmarshal JSON", func(t *testing.T) {
+		var flags testFlags
+		err := json.Unmarshal([]byte(`{"beta": null, "dark": false, "preview": true}`), &flags)
+		snaps.MatchSnapshot(t, flags, fmt.Sprint(err))
+	})
+
+	t.Run("Unmarshal JSON invalid", func(t *testing.T) {
+		var flags testFlags
+		err := json.Unmarshal([]byte(`{"beta": "yes"}`), &flags)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var absent, set opt.Tristate
+		absentErr := absent.UnmarshalText([]byte(""))
+		setErr := set.UnmarshalText([]byte("0"))
+		text, _ := opt.True.MarshalText()
+		snaps.MatchSnapshot(t, absent, fmt.Sprint(absentErr), set, fmt.Sprint(setErr), string(text))
+	})
+
+	t.Run("Flag", func(t *testing.T) {
+		var beta, dark, preview opt.Tristate
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Var(&beta, "beta", "")
+		fs.Var(&dark, "dark", "")
+		fs.Var(&preview, "preview", "")
+
+		err := fs.Parse([]string{"-beta", "-dark=false"})
+		snaps.MatchSnapshot(t, beta, dark, preview, fmt.Sprint(err))
+	})
+
+	t.Run("Flag invalid", func(t *testing.T) {
+		var beta opt.Tristate
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Var(&beta, "beta", "")
+
+		err := fs.Parse([]string{"-beta=maybe"})
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+}
@@ -0,0 +1,194 @@
+package opt
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	unmarshalerType     = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// Unmarshal parses the JSON encoded data and stores the result in the value
+// pointed to by v.
+// Unmarshal behaves like json.Unmarshal except that it honors the aliases key
+// of the opt struct tag, which lists alternative JSON names for a field:
+//
+//	Email Option[string] `json:"email" opt:"aliases=email_address,mail"`
+//
+// If the JSON object holds no key for the field's own name, the value of the
+// first alias present is used instead. This lets renamed fields keep accepting
+// payloads written with their old names.
+func Unmarshal(data []byte, v any) (err error) {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() && hasAliases(rv.Type()) {
+		if data, err = resolveAliases(data, rv.Type()); err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// tagAliases returns the aliases listed in the opt struct tag.
+// Keys in the opt tag are separated by semicolons.
+func tagAliases(tag string) (aliases []string) {
+	for _, kv := range strings.Split(tag, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(kv), "=")
+		if key != "aliases" {
+			continue
+		}
+
+		for _, alias := range strings.Split(value, ",") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				aliases = append(aliases, alias)
+			}
+		}
+	}
+
+	return aliases
+}
+
+// aliasCache maps a reflect.Type to whether a field with aliases is reachable
+// from it.
+var aliasCache sync.Map
+
+// hasAliases reports whether a struct field with aliases is reachable from t.
+func hasAliases(t reflect.Type) (ok bool) {
+	if c, ok := aliasCache.Load(t); ok {
+		return c.(bool)
+	}
+
+	c, _ := aliasCache.LoadOrStore(t, reachesAliases(t, map[reflect.Type]bool{}))
+	return c.(bool)
+}
+
+// reachesAliases does the work for hasAliases.
+func reachesAliases(t reflect.Type, seen map[reflect.Type]bool) (ok bool) {
+	if isOptionType(t) {
+		return reachesAliases(optionElemType(t), seen)
+	}
+
+	if seen[t] || isUnmarshalerType(t) {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return reachesAliases(t.Elem(), seen)
+	case reflect.Struct:
+		for _, f := range cachedFields(t) {
+			if len(f.aliases) > 0 || reachesAliases(f.typ, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isUnmarshalerType reports whether values of t decode themselves.
+func isUnmarshalerType(t reflect.Type) (ok bool) {
+	if isOptionType(t) {
+		return false
+	}
+
+	return reflect.PointerTo(t).Implements(unmarshalerType) ||
+		reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// resolveAliases rewrites data, which is to be decoded into a value of type t,
+// so that every alias key is renamed to the name of its field.
+func resolveAliases(data []byte, t reflect.Type) (resolved []byte, err error) {
+	if !hasAliases(t) {
+		return data, nil
+	}
+
+	if isOptionType(t) {
+		return resolveAliases(data, optionElemType(t))
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return resolveAliases(data, t.Elem())
+	case reflect.Slice, reflect.Array:
+		var elems []json.RawMessage
+		if json.Unmarshal(data, &elems) != nil {
+			// Leave the reporting of type errors to encoding/json.
+			return data, nil
+		}
+
+		for i, elem := range elems {
+			if elems[i], err = resolveAliases(elem, t.Elem()); err != nil {
+				return nil, err
+			}
+		}
+
+		return json.Marshal(elems)
+	case reflect.Map:
+		var entries map[string]json.RawMessage
+		if json.Unmarshal(data, &entries) != nil || entries == nil {
+			return data, nil
+		}
+
+		for k, entry := range entries {
+			if entries[k], err = resolveAliases(entry, t.Elem()); err != nil {
+				return nil, err
+			}
+		}
+
+		return json.Marshal(entries)
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil || object == nil {
+			return data, nil
+		}
+
+		for _, f := range cachedFields(t) {
+			key, found := lookupKey(object, f.name)
+
+			if !found {
+				for _, alias := range f.aliases {
+					if value, ok := object[alias]; ok {
+						delete(object, alias)
+						object[f.name] = value
+						key, found = f.name, true
+						break
+					}
+				}
+			}
+
+			if found {
+				if object[key], err = resolveAliases(object[key], f.typ); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return json.Marshal(object)
+	}
+
+	return data, nil
+}
+
+// lookupKey returns the key of object matching name, preferring an exact match
+// and otherwise matching case-insensitively as encoding/json does.
+func lookupKey(object map[string]json.RawMessage, name string) (key string, found bool) {
+	if _, ok := object[name]; ok {
+		return name, true
+	}
+
+	for k := range object {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+
+	return "", false
+}
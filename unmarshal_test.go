@@ -0,0 +1,75 @@
+package opt_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testAliasAddress struct {
+	Postcode opt.Option[string] `json:"postcode" opt:"aliases=zip,zip_code"`
+}
+
+type testAliasPayload struct {
+	Email     opt.Option[string]                      `json:"email" opt:"aliases=email_address, mail"`
+	Name      string                                  `json:"name" opt:"aliases=full_name"`
+	Address   opt.Option[testAliasAddress]            `json:"address" opt:"aliases=location"`
+	Previous  []testAliasAddress                      `json:"previous"`
+	ByKind    map[string]*testAliasAddress            `json:"byKind"`
+	Untouched opt.Option[string]                      `json:"untouched"`
+	Nested    opt.Option[map[string]testAliasPayload] `json:"nested"`
+}
+
+var aliasTestCases = map[string]string{
+	"Canonical":        `{"email": "a@example.com", "name": "Ada"}`,
+	"Alias":            `{"email_address": "a@example.com", "full_name": "Ada"}`,
+	"Second alias":     `{"mail": "a@example.com"}`,
+	"Canonical wins":   `{"mail": "old@example.com", "email": "new@example.com"}`,
+	"Case insensitive": `{"EMAIL": "a@example.com", "mail": "old@example.com"}`,
+	"Alias null":       `{"email_address": null}`,
+	"Nested":           `{"location": {"zip": "W1"}, "previous": [{"zip_code": "NW1"}, {"postcode": "E1"}], "byKind": {"home": {"zip": "SW1"}, "none": null}}`,
+	"Recursive":        `{"nested": {"child": {"mail": "child@example.com"}}}`,
+	"Absent":           `{"untouched": "value"}`,
+}
+
+func Test_Unmarshal(t *testing.T) {
+	for n, data := range aliasTestCases {
+		t.Run(n, func(t *testing.T) {
+			var payload testAliasPayload
+
+			if err := opt.Unmarshal([]byte(data), &payload); err != nil {
+				t.Fatalf("Unexpected unmarshal error: %s", err)
+			}
+
+			result, err := opt.Marshal(payload)
+			if err != nil {
+				t.Fatalf("Unexpected marshal error: %s", err)
+			}
+
+			snaps.MatchSnapshot(t, string(result))
+		})
+	}
+
+	t.Run("Type error", func(t *testing.T) {
+		var payload testAliasPayload
+
+		err := opt.Unmarshal([]byte(`{"mail": 42}`), &payload)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Syntax error", func(t *testing.T) {
+		var payload testAliasPayload
+
+		err := opt.Unmarshal([]byte(`{"mail": `), &payload)
+		snaps.MatchSnapshot(t, fmt.Sprint(err))
+	})
+
+	t.Run("Without aliases", func(t *testing.T) {
+		var payload testPayload
+
+		err := opt.Unmarshal([]byte(`{"primitive": "hello world"}`), &payload)
+		snaps.MatchSnapshot(t, fmt.Sprint(err), payload.Primitive.Unwrap())
+	})
+}
@@ -0,0 +1,111 @@
+package opt
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fletcharoo/opt/internal/logwalk"
+)
+
+// Walk calls fn for every Option reachable from v, in the order Marshal
+// encodes them, reporting whether it was provided along with its value. value
+// is nil if the Option was not provided.
+// path is the dotted path of JSON names leading to the Option, with slice and
+// array elements written as "[0]", "[1]" and so on, and map values by their
+// key, as in "addresses[0].city" or "byKind.home.city".
+// Walk descends into structs, slices, arrays, maps, pointers, interfaces and
+// the values of provided Options, except for values of types encoding
+// themselves as JSON, which Marshal does not walk either. A struct reached
+// again through a pointer cycle is not walked again.
+// If fn returns an error, Walk stops and returns it.
+func Walk(v any, fn func(path string, present bool, value any) error) (err error) {
+	return walkValue(reflect.ValueOf(v), "", fn, logwalk.Seen{})
+}
+
+// walkValue does the work for Walk, visiting v found at path. seen holds the
+// structs being walked.
+func walkValue(v reflect.Value, path string, fn func(path string, present bool, value any) error, seen logwalk.Seen) (err error) {
+	if !v.IsValid() {
+		return nil
+	}
+
+	t := v.Type()
+
+	if isOptionType(t) {
+		value, exists := v.Interface().(option).AnyValue()
+		if !exists {
+			return fn(path, false, nil)
+		}
+
+		if err = fn(path, true, value); err != nil {
+			return err
+		}
+
+		return walkValue(reflect.ValueOf(value), path, fn, seen)
+	}
+
+	if !containsOption(t) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			return walkValue(v.Elem(), path, fn, seen)
+		}
+	case reflect.Struct:
+		if seen.Contains(v) {
+			return nil
+		}
+
+		seen.Enter(v)
+		defer seen.Leave(v)
+
+		for _, f := range cachedFields(t) {
+			fv, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				continue
+			}
+
+			if err = walkValue(fv, joinPath(path, f.name), fn, seen); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err = walkValue(v.Index(i), path+"["+strconv.Itoa(i)+"]", fn, seen); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		type entry struct {
+			key   string
+			value reflect.Value
+		}
+
+		var entries []entry
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := mapKey(iter.Key())
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, entry{key: key, value: iter.Value()})
+		}
+
+		slices.SortFunc(entries, func(a, b entry) int {
+			return strings.Compare(a.key, b.key)
+		})
+
+		for _, kv := range entries {
+			if err = walkValue(kv.value, joinPath(path, kv.key), fn, seen); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
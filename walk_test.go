@@ -0,0 +1,82 @@
+package opt_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+type testWalk struct {
+	ID        int                                 `json:"id"`
+	Name      opt.Option[string]                  `json:"name"`
+	Email     opt.Option[string]                  `json:"email"`
+	Address   opt.Option[testApplyAddressPatch]   `json:"address"`
+	Previous  []testApplyAddressPatch             `json:"previous"`
+	ByKind    map[string]*testApplyAddressPatch   `json:"byKind"`
+	Extra     any                                 `json:"extra"`
+	Untouched opt.Option[[]testApplyAddressPatch] `json:"untouched"`
+}
+
+func Test_Walk(t *testing.T) {
+	v := testWalk{
+		ID:      1,
+		Name:    opt.Some("Ada"),
+		Address: opt.Some(testApplyAddressPatch{City: opt.Some("London")}),
+		Previous: []testApplyAddressPatch{
+			{Postcode: opt.Some("NW1")},
+		},
+		ByKind: map[string]*testApplyAddressPatch{
+			"work": {City: opt.Some("Paris")},
+			"home": nil,
+		},
+		Extra: testAliasAddress{Postcode: opt.Some("E1")},
+	}
+
+	t.Run("All", func(t *testing.T) {
+		var visits []string
+		err := opt.Walk(&v, func(path string, present bool, value any) error {
+			visits = append(visits, fmt.Sprintf("%s %t %#v", path, present, value))
+			return nil
+		})
+		snaps.MatchSnapshot(t, visits, fmt.Sprint(err))
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		errStop := errors.New("stop")
+
+		var visits []string
+		err := opt.Walk(v, func(path string, present bool, value any) error {
+			visits = append(visits, path)
+			if path == "email" {
+				return errStop
+			}
+
+			return nil
+		})
+		snaps.MatchSnapshot(t, visits, errors.Is(err, errStop))
+	})
+
+	t.Run("Option", func(t *testing.T) {
+		var visits []string
+		err := opt.Walk(opt.Some(1), func(path string, present bool, value any) error {
+			visits = append(visits, fmt.Sprintf("%q %t %#v", path, present, value))
+			return nil
+		})
+		snaps.MatchSnapshot(t, visits, fmt.Sprint(err))
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		node := &testNode{Name: opt.Some("loop")}
+		node.Next = node
+
+		var visits []string
+		err := opt.Walk(node, func(path string, present bool, value any) error {
+			visits = append(visits, fmt.Sprintf("%q %t %#v", path, present, value))
+			return nil
+		})
+		snaps.MatchSnapshot(t, visits, fmt.Sprint(err))
+	})
+}
@@ -0,0 +1,103 @@
+package opt
+
+import "sync"
+
+// Watched is an Option that notifies subscribers when its value is set or
+// cleared, so that components can react when an optional setting appears,
+// changes or is removed.
+// Watched is safe for use by several goroutines at the same time. The zero
+// Watched holds an Option whose value is not provided and has no subscribers.
+// A Watched must not be copied after first use.
+type Watched[T any] struct {
+	// mu guards the fields below.
+	mu sync.RWMutex
+
+	// current holds the Option.
+	current Option[T]
+
+	// subscribers maps subscription IDs to the functions to notify.
+	subscribers map[uint64]func(Option[T])
+
+	// order holds the subscription IDs in the order they subscribed.
+	order []uint64
+
+	// nextID is the ID of the next subscription.
+	nextID uint64
+}
+
+// Load returns the Option the Watched holds.
+func (w *Watched[T]) Load() (o Option[T]) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.current
+}
+
+// Set provides the value and notifies the subscribers.
+func (w *Watched[T]) Set(value T) {
+	w.update(Some(value))
+}
+
+// Clear sets the value to not provided and, if it was provided, notifies the
+// subscribers.
+func (w *Watched[T]) Clear() {
+	w.update(None[T]())
+}
+
+// update stores o and notifies the subscribers, unless both o and the previous
+// Option are not provided.
+func (w *Watched[T]) update(o Option[T]) {
+	w.mu.Lock()
+	changed := o.exists || w.current.exists
+	w.current = o
+
+	var notify []func(Option[T])
+	if changed {
+		notify = make([]func(Option[T]), 0, len(w.order))
+		for _, id := range w.order {
+			notify = append(notify, w.subscribers[id])
+		}
+	}
+	w.mu.Unlock()
+
+	for _, fn := range notify {
+		fn(o)
+	}
+}
+
+// Subscribe registers fn to be called with the new Option each time the value
+// is set, and each time it is cleared after being provided. It returns a
+// function that cancels the subscription.
+// fn is called on the goroutine calling Set or Clear, after the Watched has
+// been updated, so it may call Load. Subscribers are called in the order they
+// subscribed; when Set and Clear are called from several goroutines at once,
+// notifications of the updates may interleave.
+func (w *Watched[T]) Subscribe(fn func(Option[T])) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.subscribers == nil {
+		w.subscribers = map[uint64]func(Option[T]){}
+	}
+
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = fn
+	w.order = append(w.order, id)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+
+			delete(w.subscribers, id)
+			for i, x := range w.order {
+				if x == id {
+					w.order = append(w.order[:i:i], w.order[i+1:]...)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package opt_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_Watched(t *testing.T) {
+	t.Run("Notifications", func(t *testing.T) {
+		var w opt.Watched[string]
+		var events []string
+
+		unsubscribeFirst := w.Subscribe(func(o opt.Option[string]) {
+			events = append(events, "first: "+fmt.Sprint(o)+" / "+fmt.Sprint(w.Load()))
+		})
+		w.Subscribe(func(o opt.Option[string]) {
+			events = append(events, "second: "+fmt.Sprint(o))
+		})
+
+		w.Clear()
+		w.Set("dark")
+		w.Set("light")
+
+		unsubscribeFirst()
+		unsubscribeFirst()
+
+		w.Clear()
+		w.Clear()
+
+		snaps.MatchSnapshot(t, events, w.Load().Exists())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var w opt.Watched[int]
+
+		var mu sync.Mutex
+		notified := 0
+		w.Subscribe(func(opt.Option[int]) {
+			mu.Lock()
+			notified++
+			mu.Unlock()
+		})
+
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.Set(i)
+				w.Load()
+			}()
+		}
+		wg.Wait()
+
+		snaps.MatchSnapshot(t, notified, w.Load().Exists())
+	})
+}
@@ -0,0 +1,28 @@
+package opt
+
+// Wrapper is implemented by every Option[T], and by types embedding one such
+// as Tracked[T], whatever T is. It lets encoders, ORMs and validators that
+// work through reflection or interfaces detect and unwrap Options without
+// knowing T:
+//
+//	if w, ok := v.(opt.Wrapper); ok {
+//		value, exists := w.AnyValue()
+//		...
+//	}
+type Wrapper interface {
+	// AnyValue returns the value held by the Option as an any and whether it
+	// was provided.
+	AnyValue() (value any, exists bool)
+}
+
+// AnyValue returns the value as an any along with whether it was provided.
+// If the value is provided, AnyValue returns the value and true.
+// If the value is not provided, AnyValue returns the zero value of T and
+// false.
+func (o Option[T]) AnyValue() (value any, exists bool) {
+	if !o.exists {
+		return *new(T), false
+	}
+
+	return o.value, true
+}
@@ -0,0 +1,28 @@
+package opt_test
+
+import (
+	"testing"
+
+	"github.com/fletcharoo/opt"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func Test_AnyValue(t *testing.T) {
+	for n, v := range map[string]any{
+		"Some":    opt.Some("hello world"),
+		"None":    opt.None[int](),
+		"Pointer": opt.Some[*int](nil),
+		"Tracked": opt.Tracked[string]{Option: opt.Some("hello world")},
+	} {
+		t.Run(n, func(t *testing.T) {
+			w, ok := v.(opt.Wrapper)
+			value, exists := w.AnyValue()
+			snaps.MatchSnapshot(t, ok, value, exists)
+		})
+	}
+
+	t.Run("Not an Option", func(t *testing.T) {
+		_, ok := any("hello world").(opt.Wrapper)
+		snaps.MatchSnapshot(t, ok)
+	})
+}